@@ -49,6 +49,14 @@ const (
 	OpenapiSchema    = "openapi.schema"
 	OpenapiParameter = "openapi.parameter"
 	OpenapiDocument  = "openapi.document"
+
+	OpenapiSkipDefaultResponse = "openapi.skip_default_response"
+
+	// StreamingMode is the Kitex annotation of streaming Thrift methods.
+	StreamingMode = "streaming.mode"
+	// Deprecated marks Thrift methods, structs, fields and enum values as
+	// deprecated.
+	Deprecated = "deprecated"
 )
 
 const (
@@ -56,6 +64,10 @@ const (
 	CodeGenerationCommentPbRpc      = "// Code generated by protoc-gen-rpc-swagger."
 	CodeGenerationCommentThriftHttp = "// Code generated by thrift-gen-http-swagger."
 	CodeGenerationCommentThriftRpc  = "// Code generated by thrift-gen-rpc-swagger."
+
+	// DoNotEditComment ends the code generation comment of the files
+	// overwritten on every run.
+	DoNotEditComment = " DO NOT EDIT."
 )
 
 const (
@@ -77,11 +89,17 @@ const (
 	DocumentOptionServiceType = "service"
 	DocumentOptionStructType  = "struct"
 
+	// MultiSegmentParameterDesc notes that a path parameter bound by a `**`
+	// wildcard may span several segments.
+	MultiSegmentParameterDesc = "Matches the rest of the path, so the value may contain `/`."
+
 	DefaultResponseDesc          = "Successful response"
 	DefaultExceptionDesc         = "Exception response"
+	DefaultErrorResponseDesc     = "Default error response"
 	StatusOK                     = "200"
 	StatusBadRequest             = "400"
 	SchemaObjectType             = "object"
+	SchemaStringType             = "string"
 	ComponentSchemaPrefix        = "#/components/schemas/"
 	ComponentSchemaSuffixBody    = "Body"
 	ComponentSchemaSuffixForm    = "Form"
@@ -91,6 +109,8 @@ const (
 	ContentTypeFormMultipart  = "multipart/form-data"
 	ContentTypeFormURLEncoded = "application/x-www-form-urlencoded"
 	ContentTypeRawBody        = "text/plain"
+	ContentTypeNDJSON         = "application/x-ndjson"
+	ContentTypeEventStream    = "text/event-stream"
 
 	ParameterInQuery  = "query"
 	ParameterInHeader = "header"
@@ -100,9 +120,32 @@ const (
 	DefaultOutputDir         = "swagger"
 	DefaultOutputYamlFile    = "openapi.yaml"
 	DefaultOutputSwaggerFile = "swagger.go"
+	DefaultOutputIdlFile     = "idl.go"
+	DefaultOutputProxyFile   = "proxy.go"
 
 	DefaultServerURL = "http://127.0.0.1:8888"
 	DefaultKitexAddr = "127.0.0.1:8888"
+	DefaultHertzAddr = "127.0.0.1:8080"
+
+	// PathStyleService documents RPC methods at `/{Service}/{Method}`.
+	PathStyleService = "service"
+	// PathStyleMethod documents RPC methods at `/{Method}`, so methods of the
+	// same name in different services collide.
+	PathStyleMethod = "method"
+
+	// MetainfoStyleHeader carries metainfo in `X-Metainfo-*` and
+	// `X-Metainfo-Persistent-*` headers, in both directions.
+	MetainfoStyleHeader = "header"
+	// MetainfoStyleQuery reads metainfo from the query parameters, a `p_`
+	// prefix marking persistent values, and merges the backward metainfo
+	// into the response body.
+	MetainfoStyleQuery = "query"
+
+	// MetainfoExtensionName documents on each operation the headers carrying
+	// metainfo with the "header" metainfo style, one per key. As the keys are
+	// not known, they can not be documented as parameters.
+	MetainfoExtensionName = "x-metainfo"
+	MetainfoExtension     = "request: X-Metainfo-{Key}\npersistent: X-Metainfo-Persistent-{Key}\nbackward: X-Metainfo-{Key}"
 
 	ParameterNameTTHeader = "ttheader"
 	ParameterDescription  = "metainfo for request"
@@ -110,6 +153,12 @@ const (
 	CommentPatternRegexp    = `//\s*(.*)|/\*([\s\S]*?)\*/`
 	LinterRulePatternRegexp = `\(-- .* --\)`
 
+	StreamingExtensionName = "x-streaming"
+	TryItOutExtensionName  = "x-try-it-out"
+	StreamingClient        = "client"
+	StreamingServer        = "server"
+	StreamingBidi          = "bidi"
+
 	ProtobufValueName = "GoogleProtobufValue"
 	ProtobufAnyName   = "GoogleProtobufAny"
 )
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diagnostics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Severity describes how serious a Diagnostic is.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// Location identifies where a diagnostic applies: a position in an IDL file
+// and the declaration (service, method, struct, field...) that owns it, such as
+// "field HelloReq.Name". Any part may be unknown and left empty.
+type Location struct {
+	Filename string
+	Line     int // 1-based, 0 if unknown
+	Column   int // 1-based, 0 if unknown
+	Owner    string
+}
+
+// Position returns the compiler-style "file:line:col" form of the location,
+// omitting the parts that are unknown.
+func (l Location) Position() string {
+	if l.Filename == "" {
+		return ""
+	}
+	if l.Line <= 0 {
+		return l.Filename
+	}
+	if l.Column <= 0 {
+		return fmt.Sprintf("%s:%d", l.Filename, l.Line)
+	}
+	return fmt.Sprintf("%s:%d:%d", l.Filename, l.Line, l.Column)
+}
+
+// Diagnostic is a single problem found while generating a document.
+type Diagnostic struct {
+	Severity Severity
+	Location Location
+	Message  string
+}
+
+// String formats the diagnostic as "file:line:col: severity: owner: message"
+// so that editors and terminals can jump to the reported position.
+func (d *Diagnostic) String() string {
+	var sb strings.Builder
+	if pos := d.Location.Position(); pos != "" {
+		sb.WriteString(pos)
+		sb.WriteString(": ")
+	}
+	sb.WriteString(d.Severity.String())
+	sb.WriteString(": ")
+	sb.WriteString(d.Text())
+	return sb.String()
+}
+
+// Text returns the message prefixed with its owner, without position or
+// severity.
+func (d *Diagnostic) Text() string {
+	if d.Location.Owner == "" {
+		return d.Message
+	}
+	return d.Location.Owner + ": " + d.Message
+}
+
+// Collector accumulates the diagnostics reported by the generators so that
+// the plugin can decide at the end whether generation failed.
+type Collector struct {
+	diagnostics []*Diagnostic
+}
+
+// NewCollector creates an empty collector.
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Add records a diagnostic with the given severity and location. The
+// generators visit some declarations more than once, so a diagnostic identical
+// to one already recorded is dropped.
+func (c *Collector) Add(severity Severity, loc Location, message string) {
+	for _, d := range c.diagnostics {
+		if d.Severity == severity && d.Location == loc && d.Message == message {
+			return
+		}
+	}
+	c.diagnostics = append(c.diagnostics, &Diagnostic{
+		Severity: severity,
+		Location: loc,
+		Message:  message,
+	})
+}
+
+func (c *Collector) Infof(format string, v ...interface{}) {
+	c.Add(SeverityInfo, Location{}, fmt.Sprintf(format, v...))
+}
+
+func (c *Collector) Warnf(format string, v ...interface{}) {
+	c.Add(SeverityWarning, Location{}, fmt.Sprintf(format, v...))
+}
+
+func (c *Collector) Errorf(format string, v ...interface{}) {
+	c.Add(SeverityError, Location{}, fmt.Sprintf(format, v...))
+}
+
+func (c *Collector) InfofAt(loc Location, format string, v ...interface{}) {
+	c.Add(SeverityInfo, loc, fmt.Sprintf(format, v...))
+}
+
+func (c *Collector) WarnfAt(loc Location, format string, v ...interface{}) {
+	c.Add(SeverityWarning, loc, fmt.Sprintf(format, v...))
+}
+
+func (c *Collector) ErrorfAt(loc Location, format string, v ...interface{}) {
+	c.Add(SeverityError, loc, fmt.Sprintf(format, v...))
+}
+
+// Diagnostics returns every diagnostic in the order it was reported.
+func (c *Collector) Diagnostics() []*Diagnostic {
+	return c.diagnostics
+}
+
+// Count returns the number of diagnostics with the given severity.
+func (c *Collector) Count(severity Severity) int {
+	n := 0
+	for _, d := range c.diagnostics {
+		if d.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// HasErrors reports whether at least one error was collected.
+func (c *Collector) HasErrors() bool {
+	return c.Count(SeverityError) > 0
+}
+
+// Err returns an error listing all collected diagnostics if at least one of
+// them is an error, and nil otherwise.
+func (c *Collector) Err() error {
+	if !c.HasErrors() {
+		return nil
+	}
+	return &Error{Diagnostics: c.diagnostics}
+}
+
+// Report writes every collected diagnostic to w, one per line. In strict mode
+// any error fails the generation instead, and the returned error lists all
+// diagnostics.
+func (c *Collector) Report(w io.Writer, strict bool) error {
+	if strict && c.HasErrors() {
+		return c.Err()
+	}
+	for _, d := range c.diagnostics {
+		if _, err := fmt.Fprintln(w, d.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Error is returned in strict mode when generation produced errors.
+type Error struct {
+	Diagnostics []*Diagnostic
+}
+
+func (e *Error) Error() string {
+	errors := 0
+	lines := make([]string, 0, len(e.Diagnostics))
+	for _, d := range e.Diagnostics {
+		if d.Severity == SeverityError {
+			errors++
+		}
+		lines = append(lines, d.String())
+	}
+	return fmt.Sprintf("generation failed with %d error(s):\n%s", errors, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,248 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diagnostics
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestDiagnosticString(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Diagnostic
+		want string
+	}{
+		{
+			name: "full location",
+			d:    Diagnostic{Severity: SeverityError, Location: Location{Filename: "a.thrift", Line: 3, Column: 5, Owner: "field Req.Name"}, Message: "bad"},
+			want: "a.thrift:3:5: error: field Req.Name: bad",
+		},
+		{
+			name: "no column",
+			d:    Diagnostic{Severity: SeverityWarning, Location: Location{Filename: "a.proto", Line: 3}, Message: "bad"},
+			want: "a.proto:3: warning: bad",
+		},
+		{
+			name: "no line",
+			d:    Diagnostic{Severity: SeverityInfo, Location: Location{Filename: "a.proto", Column: 5}, Message: "bad"},
+			want: "a.proto: info: bad",
+		},
+		{
+			name: "owner only",
+			d:    Diagnostic{Severity: SeverityError, Location: Location{Line: 3, Owner: "service Hello"}, Message: "bad"},
+			want: "error: service Hello: bad",
+		},
+		{
+			name: "no location",
+			d:    Diagnostic{Severity: Severity(7), Message: "bad"},
+			want: "unknown: bad",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectorDedupe(t *testing.T) {
+	a := Location{Filename: "a.thrift", Line: 1, Column: 2, Owner: "field Req.Name"}
+	tests := []struct {
+		name   string
+		report func(c *Collector)
+		want   int
+	}{
+		{
+			name:   "same location",
+			report: func(c *Collector) { c.ErrorfAt(a, "bad"); c.ErrorfAt(a, "%s", "bad") },
+			want:   1,
+		},
+		{
+			name: "same message at another line",
+			report: func(c *Collector) {
+				c.ErrorfAt(a, "bad")
+				c.ErrorfAt(Location{Filename: a.Filename, Line: 2, Owner: a.Owner}, "bad")
+			},
+			want: 2,
+		},
+		{
+			name: "same position with another owner",
+			report: func(c *Collector) {
+				c.WarnfAt(a, "bad")
+				c.WarnfAt(Location{Filename: a.Filename, Line: 1, Column: 2}, "bad")
+			},
+			want: 2,
+		},
+		{
+			name:   "same location with another severity",
+			report: func(c *Collector) { c.InfofAt(a, "bad"); c.WarnfAt(a, "bad"); c.ErrorfAt(a, "bad") },
+			want:   3,
+		},
+		{
+			name:   "without a location",
+			report: func(c *Collector) { c.Warnf("bad"); c.WarnfAt(Location{}, "bad") },
+			want:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector()
+			tt.report(c)
+			if got := len(c.Diagnostics()); got != tt.want {
+				t.Errorf("collected %d diagnostic(s), want %d: %v", got, tt.want, c.Diagnostics())
+			}
+		})
+	}
+}
+
+func TestCollector(t *testing.T) {
+	loc := Location{Filename: "a.thrift", Line: 1, Owner: "struct Req"}
+	tests := []struct {
+		name       string
+		report     func(c *Collector)
+		wantCount  [3]int // Count of info, warnings and errors.
+		wantErr    string
+		wantReport string
+	}{
+		{name: "empty"},
+		{
+			name: "warnings only",
+			report: func(c *Collector) {
+				c.Infof("note %d", 1)
+				c.WarnfAt(loc, "odd %s", "thing")
+			},
+			wantCount:  [3]int{1, 1, 0},
+			wantReport: "info: note 1\na.thrift:1: warning: struct Req: odd thing\n",
+		},
+		{
+			name: "duplicates dropped",
+			report: func(c *Collector) {
+				c.ErrorfAt(loc, "bad")
+				c.ErrorfAt(loc, "bad")
+				c.WarnfAt(loc, "bad")
+				c.Errorf("bad")
+			},
+			wantCount:  [3]int{0, 1, 2},
+			wantErr:    "generation failed with 2 error(s):\na.thrift:1: error: struct Req: bad\na.thrift:1: warning: struct Req: bad\nerror: bad",
+			wantReport: "a.thrift:1: error: struct Req: bad\na.thrift:1: warning: struct Req: bad\nerror: bad\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector()
+			if tt.report != nil {
+				tt.report(c)
+			}
+			for severity, want := range tt.wantCount {
+				if got := c.Count(Severity(severity)); got != want {
+					t.Errorf("Count(%s) = %d, want %d", Severity(severity), got, want)
+				}
+			}
+			if got := c.HasErrors(); got != (tt.wantCount[SeverityError] > 0) {
+				t.Errorf("HasErrors() = %v", got)
+			}
+			err := c.Err()
+			if (err == nil) != (tt.wantErr == "") || err != nil && err.Error() != tt.wantErr {
+				t.Errorf("Err() = %v, want %q", err, tt.wantErr)
+			}
+
+			var buf bytes.Buffer
+			if err := c.Report(&buf, false); err != nil {
+				t.Errorf("Report() error = %v", err)
+			}
+			if buf.String() != tt.wantReport {
+				t.Errorf("Report() wrote %q, want %q", buf.String(), tt.wantReport)
+			}
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("closed")
+}
+
+func TestCollectorReport(t *testing.T) {
+	loc := Location{Filename: "a.proto", Line: 4, Owner: "message Req"}
+	warn := func(c *Collector) { c.WarnfAt(loc, "odd") }
+	fail := func(c *Collector) { c.WarnfAt(loc, "odd"); c.ErrorfAt(loc, "bad") }
+	tests := []struct {
+		name    string
+		report  func(c *Collector)
+		strict  bool
+		w       io.Writer
+		wantErr string
+		wantOut string
+	}{
+		{
+			name:    "warnings in strict mode",
+			report:  warn,
+			strict:  true,
+			wantOut: "a.proto:4: warning: message Req: odd\n",
+		},
+		{
+			name:    "errors in strict mode",
+			report:  fail,
+			strict:  true,
+			wantErr: "generation failed with 1 error(s):\na.proto:4: warning: message Req: odd\na.proto:4: error: message Req: bad",
+		},
+		{
+			name:    "errors without strict mode",
+			report:  fail,
+			wantOut: "a.proto:4: warning: message Req: odd\na.proto:4: error: message Req: bad\n",
+		},
+		{
+			name:    "write failure",
+			report:  warn,
+			w:       failingWriter{},
+			wantErr: "closed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector()
+			tt.report(c)
+			var buf bytes.Buffer
+			w := tt.w
+			if w == nil {
+				w = &buf
+			}
+			err := c.Report(w, tt.strict)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Report() error = %v", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Fatalf("Report() error = %v, want %q", err, tt.wantErr)
+			}
+			if buf.String() != tt.wantOut {
+				t.Errorf("Report() wrote %q, want %q", buf.String(), tt.wantOut)
+			}
+			if tt.strict && tt.wantErr != "" {
+				var de *Error
+				if !errors.As(err, &de) || len(de.Diagnostics) != len(c.Diagnostics()) {
+					t.Errorf("Report() error %#v does not carry the diagnostics", err)
+				}
+			}
+		})
+	}
+}
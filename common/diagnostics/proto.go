@@ -0,0 +1,110 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diagnostics
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Field numbers of the `options` field in the descriptor protos, used to build
+// the SourceCodeInfo path of an option.
+const (
+	fileOptionsField      = 8
+	messageOptionsField   = 7
+	fieldOptionsField     = 8
+	oneofOptionsField     = 2
+	enumOptionsField      = 3
+	enumValueOptionsField = 3
+	serviceOptionsField   = 3
+	methodOptionsField    = 4
+)
+
+// ProtoLocation locates desc using the SourceCodeInfo of its file. If ext is
+// not nil and the option is set on desc, the location points at the option
+// instead of the declaration. SourceCodeInfo is only available for the files
+// protoc was asked to generate; for other files only the file name and owner
+// are filled.
+func ProtoLocation(desc protoreflect.Descriptor, ext protoreflect.ExtensionType) Location {
+	loc := Location{Owner: protoOwner(desc)}
+	file := desc.ParentFile()
+	if file == nil {
+		return loc
+	}
+	loc.Filename = file.Path()
+
+	locations := file.SourceLocations()
+	_, isFile := desc.(protoreflect.FileDescriptor)
+	src := locations.ByDescriptor(desc)
+	if ext != nil && (isFile || len(src.Path) > 0) {
+		if optionsField, ok := protoOptionsField(desc); ok {
+			path := append(append(protoreflect.SourcePath{}, src.Path...), optionsField, int32(ext.TypeDescriptor().Number()))
+			if opt := locations.ByPath(path); len(opt.Path) > 0 {
+				src = opt
+			}
+		}
+	}
+	if len(src.Path) == 0 {
+		return loc
+	}
+	loc.Line = src.StartLine + 1
+	loc.Column = src.StartColumn + 1
+	return loc
+}
+
+func protoOwner(desc protoreflect.Descriptor) string {
+	switch desc.(type) {
+	case protoreflect.FileDescriptor:
+		return "file " + desc.ParentFile().Path()
+	case protoreflect.MessageDescriptor:
+		return "message " + string(desc.FullName())
+	case protoreflect.FieldDescriptor:
+		return "field " + string(desc.FullName())
+	case protoreflect.OneofDescriptor:
+		return "oneof " + string(desc.FullName())
+	case protoreflect.EnumDescriptor:
+		return "enum " + string(desc.FullName())
+	case protoreflect.EnumValueDescriptor:
+		return "enum value " + string(desc.FullName())
+	case protoreflect.ServiceDescriptor:
+		return "service " + string(desc.FullName())
+	case protoreflect.MethodDescriptor:
+		return "method " + string(desc.FullName())
+	}
+	return string(desc.FullName())
+}
+
+func protoOptionsField(desc protoreflect.Descriptor) (int32, bool) {
+	switch desc.(type) {
+	case protoreflect.FileDescriptor:
+		return fileOptionsField, true
+	case protoreflect.MessageDescriptor:
+		return messageOptionsField, true
+	case protoreflect.FieldDescriptor:
+		return fieldOptionsField, true
+	case protoreflect.OneofDescriptor:
+		return oneofOptionsField, true
+	case protoreflect.EnumDescriptor:
+		return enumOptionsField, true
+	case protoreflect.EnumValueDescriptor:
+		return enumValueOptionsField, true
+	case protoreflect.ServiceDescriptor:
+		return serviceOptionsField, true
+	case protoreflect.MethodDescriptor:
+		return methodOptionsField, true
+	}
+	return 0, false
+}
@@ -0,0 +1,315 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diagnostics
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/cloudwego/thriftgo/thrift_reflection"
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+)
+
+// ThriftSource locates declarations in Thrift IDL files. The thriftgo AST does
+// not record source positions, so declarations are found by scanning the IDL
+// text. Files are read on first use and cached. When a file cannot be read or
+// a declaration cannot be found, the returned Location only carries the file
+// name and the owner.
+type ThriftSource struct {
+	files map[string]*thriftFile
+}
+
+type thriftFile struct {
+	// code is the file content with comments and string literals blanked out,
+	// so that offsets still match the original text.
+	code       string
+	lineStarts []int
+}
+
+func NewThriftSource() *ThriftSource {
+	return &ThriftSource{files: map[string]*thriftFile{}}
+}
+
+// Service locates a service. If annotation is set and present on the service,
+// the location points at the annotation instead of the service name.
+func (s *ThriftSource) Service(filename, name, annotation string) Location {
+	loc := Location{Filename: filename, Owner: "service " + name}
+	f := s.file(filename)
+	if f == nil {
+		return loc
+	}
+	pos, _, end := f.declaration(`service`, name)
+	if pos < 0 {
+		return loc
+	}
+	return f.locate(loc, f.annotationAfter(pos, end, annotation))
+}
+
+// Method locates a method of a service.
+func (s *ThriftSource) Method(filename, service, name, annotation string) Location {
+	loc := Location{Filename: filename, Owner: "method " + service + "." + name}
+	f := s.file(filename)
+	if f == nil {
+		return loc
+	}
+	_, bodyStart, bodyEnd := f.declaration(`service`, service)
+	if bodyStart < 0 {
+		return loc
+	}
+	pos := f.find(regexp.MustCompile(`\b`+regexp.QuoteMeta(name)+`\s*\(`), bodyStart, bodyEnd)
+	if pos < 0 {
+		return loc
+	}
+	return f.locate(loc, f.annotationWithin(pos, bodyEnd, annotation))
+}
+
+// Struct locates a struct, union or exception.
+func (s *ThriftSource) Struct(filename, name, annotation string) Location {
+	loc := Location{Filename: filename, Owner: "struct " + name}
+	f := s.file(filename)
+	if f == nil {
+		return loc
+	}
+	pos, _, end := f.declaration(`(?:struct|union|exception)`, name)
+	if pos < 0 {
+		return loc
+	}
+	return f.locate(loc, f.annotationAfter(pos, end, annotation))
+}
+
+// Field locates the field with the given id and name inside a struct, union or
+// exception.
+func (s *ThriftSource) Field(filename, structName string, id int32, name, annotation string) Location {
+	loc := Location{Filename: filename, Owner: "field " + structName + "." + name}
+	f := s.file(filename)
+	if f == nil {
+		return loc
+	}
+	_, bodyStart, bodyEnd := f.declaration(`(?:struct|union|exception)`, structName)
+	if bodyStart < 0 {
+		return loc
+	}
+	idPos := f.find(regexp.MustCompile(`(?:^|[^\w.])`+strconv.Itoa(int(id))+`\s*:`), bodyStart, bodyEnd)
+	if idPos < 0 {
+		return loc
+	}
+	pos := f.find(regexp.MustCompile(`\b`+regexp.QuoteMeta(name)+`\b`), idPos, bodyEnd)
+	if pos < 0 {
+		return loc
+	}
+	return f.locate(loc, f.annotationWithin(pos, bodyEnd, annotation))
+}
+
+// The helpers below locate the declarations of the thrift_reflection
+// descriptors the generators walk. When annotation is not empty and the
+// declaration carries it, the location points at the annotation instead of
+// the declaration name.
+
+// Document locates the service or struct that carries the `openapi.document`
+// annotation of filename, as returned by the generators for the kind
+// (consts.DocumentOptionServiceType or consts.DocumentOptionStructType) and
+// name of its owner.
+func (s *ThriftSource) Document(filename, kind, name string) Location {
+	switch kind {
+	case consts.DocumentOptionServiceType:
+		return s.Service(filename, name, consts.OpenapiDocument)
+	case consts.DocumentOptionStructType:
+		return s.Struct(filename, name, consts.OpenapiDocument)
+	}
+	return Location{Filename: filename}
+}
+
+func (s *ThriftSource) MethodOf(svc *thrift_reflection.ServiceDescriptor, m *thrift_reflection.MethodDescriptor, annotation string) Location {
+	return s.Method(svc.GetFilepath(), svc.GetName(), m.GetName(), annotation)
+}
+
+func (s *ThriftSource) StructOf(st *thrift_reflection.StructDescriptor, annotation string) Location {
+	return s.Struct(st.GetFilepath(), st.GetName(), annotation)
+}
+
+func (s *ThriftSource) FieldOf(st *thrift_reflection.StructDescriptor, f *thrift_reflection.FieldDescriptor, annotation string) Location {
+	return s.Field(st.GetFilepath(), st.GetName(), f.GetID(), f.GetName(), annotation)
+}
+
+// TypeOf only names the type: types are not declared in the IDL, so there is
+// no position to point at.
+func (s *ThriftSource) TypeOf(t *thrift_reflection.TypeDescriptor) Location {
+	return Location{Filename: t.GetFilepath(), Owner: "type " + t.GetName()}
+}
+
+func (s *ThriftSource) file(filename string) *thriftFile {
+	if f, ok := s.files[filename]; ok {
+		return f
+	}
+	var f *thriftFile
+	if content, err := os.ReadFile(filename); err == nil {
+		f = newThriftFile(string(content))
+	}
+	s.files[filename] = f
+	return f
+}
+
+func newThriftFile(text string) *thriftFile {
+	code := []byte(text)
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(code); i++ {
+			if code[i] != '\n' {
+				code[i] = ' '
+			}
+		}
+	}
+	for i := 0; i < len(code); {
+		switch {
+		case code[i] == '#' || (code[i] == '/' && i+1 < len(code) && code[i+1] == '/'):
+			j := i
+			for j < len(code) && code[j] != '\n' {
+				j++
+			}
+			blank(i, j)
+			i = j
+		case code[i] == '/' && i+1 < len(code) && code[i+1] == '*':
+			j := i + 2
+			for j+1 < len(code) && !(code[j] == '*' && code[j+1] == '/') {
+				j++
+			}
+			blank(i, j+2)
+			i = j + 2
+		case code[i] == '"' || code[i] == '\'':
+			quote := code[i]
+			j := i + 1
+			for j < len(code) && code[j] != quote {
+				if code[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			blank(i, j+1)
+			i = j + 1
+		default:
+			i++
+		}
+	}
+
+	lineStarts := []int{0}
+	for i, c := range text {
+		if c == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	return &thriftFile{code: string(code), lineStarts: lineStarts}
+}
+
+// declaration finds a top level declaration and returns the offset of its name
+// and the bounds of its body, or -1 if it is not found.
+func (f *thriftFile) declaration(keyword, name string) (pos, bodyStart, bodyEnd int) {
+	re := regexp.MustCompile(`\b` + keyword + `\s+(` + regexp.QuoteMeta(name) + `)\b`)
+	m := re.FindStringSubmatchIndex(f.code)
+	if m == nil {
+		return -1, -1, -1
+	}
+	pos = m[2]
+	bodyStart = f.find(regexp.MustCompile(`\{`), pos, len(f.code))
+	if bodyStart < 0 {
+		return pos, -1, -1
+	}
+	bodyEnd = f.matching(bodyStart, '{', '}')
+	return pos, bodyStart + 1, bodyEnd
+}
+
+// annotationAfter looks for annotation in the parenthesized annotation list
+// that follows the body ending at bodyEnd, falling back to pos.
+func (f *thriftFile) annotationAfter(pos, bodyEnd int, annotation string) int {
+	if annotation == "" || bodyEnd < 0 {
+		return pos
+	}
+	i := bodyEnd + 1
+	for i < len(f.code) && isSpace(f.code[i]) {
+		i++
+	}
+	if i >= len(f.code) || f.code[i] != '(' {
+		return pos
+	}
+	if p := f.find(annotationRegexp(annotation), i, f.matching(i, '(', ')')); p >= 0 {
+		return p
+	}
+	return pos
+}
+
+// annotationWithin looks for the first occurrence of annotation between pos
+// and end, falling back to pos.
+func (f *thriftFile) annotationWithin(pos, end int, annotation string) int {
+	if annotation == "" {
+		return pos
+	}
+	if p := f.find(annotationRegexp(annotation), pos, end); p >= 0 {
+		return p
+	}
+	return pos
+}
+
+func (f *thriftFile) find(re *regexp.Regexp, from, to int) int {
+	if from < 0 || to > len(f.code) || from >= to {
+		return -1
+	}
+	m := re.FindStringIndex(f.code[from:to])
+	if m == nil {
+		return -1
+	}
+	p := from + m[0]
+	// Skip the leading separator some patterns have to match.
+	for p < len(f.code) && !isWord(f.code[p]) && f.code[p] != '{' {
+		p++
+	}
+	return p
+}
+
+func (f *thriftFile) matching(open int, left, right byte) int {
+	depth := 0
+	for i := open; i < len(f.code); i++ {
+		switch f.code[i] {
+		case left:
+			depth++
+		case right:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(f.code)
+}
+
+func (f *thriftFile) locate(loc Location, offset int) Location {
+	line := sort.Search(len(f.lineStarts), func(i int) bool { return f.lineStarts[i] > offset }) - 1
+	loc.Line = line + 1
+	loc.Column = offset - f.lineStarts[line] + 1
+	return loc
+}
+
+func annotationRegexp(annotation string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:^|[^\w.])` + regexp.QuoteMeta(annotation) + `\s*=`)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isWord(c byte) bool {
+	return c == '_' || c == '.' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package pbgen holds the parts of the OpenAPI document generation that
+// protoc-gen-http-swagger and protoc-gen-rpc-swagger share. The helpers work
+// on the protogen descriptors and the OpenAPI model of idl/protobuf/openapi.
+package pbgen
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	common "github.com/hertz-contrib/swagger-generate/common/utils"
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var linterRulePattern = regexp.MustCompile(consts.LinterRulePatternRegexp)
+
+// FilterComment removes linter rules and directives from comments.
+func FilterComment(c protogen.Comments) string {
+	return common.StripCommentDirectives(linterRulePattern.ReplaceAllString(string(c), ""))
+}
+
+// AllComments joins the detached, leading and trailing comments of an
+// element, in source order.
+func AllComments(comments protogen.CommentSet) protogen.Comments {
+	var parts []string
+	for _, c := range comments.LeadingDetached {
+		parts = append(parts, strings.TrimSpace(string(c)))
+	}
+	parts = append(parts, strings.TrimSpace(string(comments.Leading)), strings.TrimSpace(string(comments.Trailing)))
+	var text string
+	for _, part := range parts {
+		if part != "" {
+			text = common.AppendParagraph(text, part)
+		}
+	}
+	return protogen.Comments(text)
+}
+
+// FieldExample returns the example set by the `@example` directive in the
+// comments of field, or nil if there is none.
+func FieldExample(field *protogen.Field) *openapi.Any {
+	example, ok := common.CommentExample(string(AllComments(field.Comments)))
+	if !ok {
+		return nil
+	}
+	kind := field.Desc.Kind()
+	isString := !field.Desc.IsList() && !field.Desc.IsMap() &&
+		(kind == protoreflect.StringKind || kind == protoreflect.BytesKind || kind == protoreflect.EnumKind)
+	return &openapi.Any{Yaml: common.ExampleYAML(example, isString)}
+}
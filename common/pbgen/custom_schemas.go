@@ -0,0 +1,82 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	common "github.com/hertz-contrib/swagger-generate/common/utils"
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	"google.golang.org/protobuf/encoding/protojson"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadSchemaFile reads the schemas of the `schema_file` option. The file is
+// a YAML or JSON object mapping full message names, e.g. "google.type.Money",
+// to the schema used for every field of that message type. Schemas are
+// written like the `openapi.schema` option:
+//
+//	google.type.Money:
+//	  type: string
+//	  example: {yaml: "12.30 USD"}
+func LoadSchemaFile(path string) (map[string]*openapi.Schema, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries map[string]interface{}
+	if err = yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	schemas := make(map[string]*openapi.Schema, len(entries))
+	for _, name := range names {
+		schema, err := parseSchema(entries[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %s", path, name, err)
+		}
+		schemas[strings.TrimPrefix(name, ".")] = schema
+	}
+	return schemas, nil
+}
+
+func parseSchema(value interface{}) (*openapi.Schema, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	schema := &openapi.Schema{}
+	if err = protojson.Unmarshal(data, schema); err != nil {
+		return nil, err
+	}
+	if err = common.CheckOptionMessage(schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbgen
+
+import (
+	common "github.com/hertz-contrib/swagger-generate/common/utils"
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// IsDeprecated reports whether desc is deprecated, either by its `deprecated`
+// option or by a "Deprecated:" note or `@deprecated` directive in its
+// comments.
+func IsDeprecated(desc protoreflect.Descriptor, comments protogen.CommentSet) bool {
+	return protoDeprecated(desc) || common.IsDeprecatedComment(string(AllComments(comments)))
+}
+
+// ApplyDeprecation marks op as deprecated if its method or service is.
+func ApplyDeprecation(service *protogen.Service, method *protogen.Method, op *openapi.Operation) {
+	if IsDeprecated(method.Desc, method.Comments) || IsDeprecated(service.Desc, service.Comments) {
+		op.Deprecated = true
+	}
+}
+
+// FieldDescription returns the description of field, taken from all of its
+// comments. It lists the deprecated values of enum fields.
+func FieldDescription(field *protogen.Field) string {
+	description := FilterComment(AllComments(field.Comments))
+	if field.Enum == nil {
+		return description
+	}
+	var names []string
+	for _, value := range field.Enum.Values {
+		if IsDeprecated(value.Desc, value.Comments) {
+			names = append(names, string(value.Desc.Name()))
+		}
+	}
+	if note := common.DeprecatedEnumValuesNote(names); note != "" {
+		description = common.AppendParagraph(description, note)
+	}
+	return description
+}
+
+func protoDeprecated(desc protoreflect.Descriptor) bool {
+	switch options := desc.Options().(type) {
+	case *descriptorpb.ServiceOptions:
+		return options.GetDeprecated()
+	case *descriptorpb.MethodOptions:
+		return options.GetDeprecated()
+	case *descriptorpb.MessageOptions:
+		return options.GetDeprecated()
+	case *descriptorpb.FieldOptions:
+		return options.GetDeprecated()
+	case *descriptorpb.EnumOptions:
+		return options.GetDeprecated()
+	case *descriptorpb.EnumValueOptions:
+		return options.GetDeprecated()
+	}
+	return false
+}
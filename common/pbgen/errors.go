@@ -0,0 +1,152 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbgen
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/api"
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ErrorEnum resolves the error enum of method among files, set by
+// `openapi.error_enum` on the method or `openapi.service_error_enum` on its
+// service. Names are looked up as full names first and then relative to the
+// package of the method.
+func ErrorEnum(files []*protogen.File, service *protogen.Service, method *protogen.Method, diag *diagnostics.Collector) *protogen.Enum {
+	var desc protoreflect.Descriptor = method.Desc
+	var ext protoreflect.ExtensionType = openapi.E_ErrorEnum
+	name := proto.GetExtension(method.Desc.Options(), openapi.E_ErrorEnum).(string)
+	if name == "" {
+		desc, ext = service.Desc, openapi.E_ServiceErrorEnum
+		name = proto.GetExtension(service.Desc.Options(), openapi.E_ServiceErrorEnum).(string)
+	}
+	if name == "" {
+		return nil
+	}
+
+	name = strings.TrimPrefix(name, ".")
+	candidates := []string{name}
+	if pkg := string(method.Desc.ParentFile().Package()); pkg != "" {
+		candidates = append(candidates, pkg+"."+name)
+	}
+	for _, candidate := range candidates {
+		if enum := FindEnum(files, protoreflect.FullName(candidate)); enum != nil {
+			return enum
+		}
+	}
+	diag.ErrorfAt(diagnostics.ProtoLocation(desc, ext), "Error parsing %s option: enum %q not found", ext.TypeDescriptor().FullName(), name)
+	return nil
+}
+
+// ErrorCodes groups the values of enum by their `(api.http_code)`. Values
+// without a code are left out, and invalid codes are reported.
+func ErrorCodes(enum *protogen.Enum, diag *diagnostics.Collector) map[int][]*protogen.EnumValue {
+	codes := map[int][]*protogen.EnumValue{}
+	for _, value := range enum.Values {
+		code := int(proto.GetExtension(value.Desc.Options(), api.E_HttpCode).(int32))
+		if code == 0 {
+			continue
+		}
+		if code < 100 || code > 599 {
+			diag.WarnfAt(diagnostics.ProtoLocation(value.Desc, api.E_HttpCode), "ignoring invalid HTTP status code %d", code)
+			continue
+		}
+		codes[code] = append(codes[code], value)
+	}
+	return codes
+}
+
+// ErrorResponseDescription describes the response of code, listing the enum
+// values that map to it.
+func ErrorResponseDescription(code int, values []*protogen.EnumValue) string {
+	description := http.StatusText(code)
+	if description == "" {
+		description = "Error"
+	}
+	lines := make([]string, 0, len(values))
+	for _, value := range values {
+		line := fmt.Sprintf("`%s` (%d)", value.Desc.Name(), value.Desc.Number())
+		if comment := strings.Join(strings.Fields(FilterComment(AllComments(value.Comments))), " "); comment != "" {
+			line += ": " + comment
+		}
+		lines = append(lines, "- "+line)
+	}
+	return description + "\n\n" + strings.Join(lines, "\n")
+}
+
+// HasResponse reports whether responses already has a response named name.
+func HasResponse(responses *openapi.Responses, name string) bool {
+	for _, response := range responses.ResponseOrReference {
+		if response.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// FindEnum returns the enum of files, top level or nested, named name.
+func FindEnum(files []*protogen.File, name protoreflect.FullName) *protogen.Enum {
+	var find func(enums []*protogen.Enum, messages []*protogen.Message) *protogen.Enum
+	find = func(enums []*protogen.Enum, messages []*protogen.Message) *protogen.Enum {
+		for _, enum := range enums {
+			if enum.Desc.FullName() == name {
+				return enum
+			}
+		}
+		for _, message := range messages {
+			if enum := find(message.Enums, message.Messages); enum != nil {
+				return enum
+			}
+		}
+		return nil
+	}
+	for _, file := range files {
+		if enum := find(file.Enums, file.Messages); enum != nil {
+			return enum
+		}
+	}
+	return nil
+}
+
+// FindMessage returns the message of files, top level or nested, named name.
+func FindMessage(files []*protogen.File, name protoreflect.FullName) *protogen.Message {
+	var find func(messages []*protogen.Message) *protogen.Message
+	find = func(messages []*protogen.Message) *protogen.Message {
+		for _, message := range messages {
+			if message.Desc.FullName() == name {
+				return message
+			}
+			if found := find(message.Messages); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	for _, file := range files {
+		if message := find(file.Messages); message != nil {
+			return message
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbgen
+
+import (
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	"google.golang.org/protobuf/compiler/protogen"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// OneofStyleOneOf renders each oneof as a `oneOf` composition of the
+	// property groups of its members.
+	OneofStyleOneOf = "oneOf"
+	// OneofStyleFlatten keeps oneof members as plain properties and lists them
+	// in the `x-oneof` extension of the schema.
+	OneofStyleFlatten = "flatten"
+
+	OneofExtensionName = "x-oneof"
+)
+
+// OneofGroups collects the properties generated for the members of the real
+// oneofs of a message. Synthetic oneofs, which protoc creates for proto3
+// `optional` fields, are ignored.
+type OneofGroups struct {
+	flatten bool
+	oneofs  []*protogen.Oneof
+	members map[*protogen.Oneof][]*openapi.NamedSchemaOrReference
+}
+
+// NewOneofGroups creates the groups of a message for the `oneof_style` option.
+func NewOneofGroups(style string) *OneofGroups {
+	return &OneofGroups{
+		flatten: style == OneofStyleFlatten,
+		members: map[*protogen.Oneof][]*openapi.NamedSchemaOrReference{},
+	}
+}
+
+// Add records property if field belongs to a real oneof. It reports whether the
+// property is taken out of the regular properties of the message, which is the
+// case unless oneofs are flattened.
+func (o *OneofGroups) Add(field *protogen.Field, property *openapi.NamedSchemaOrReference) bool {
+	oneof := field.Oneof
+	if oneof == nil || oneof.Desc.IsSynthetic() {
+		return false
+	}
+	if _, ok := o.members[oneof]; !ok {
+		o.oneofs = append(o.oneofs, oneof)
+	}
+	o.members[oneof] = append(o.members[oneof], property)
+	return !o.flatten
+}
+
+// Apply adds the collected oneofs to schema. A single oneof becomes the
+// `oneOf` of the schema; several oneofs are combined with `allOf`, since each
+// of them independently holds one of its members. Members listed in the
+// required fields of schema are moved to their alternative. Each `oneOf` has an
+// alternative per member, which requires it, and one for the unset oneof.
+func (o *OneofGroups) Apply(schema *openapi.Schema) {
+	if len(o.oneofs) == 0 {
+		return
+	}
+	if o.flatten {
+		groups := &yaml.Node{Kind: yaml.MappingNode}
+		for _, oneof := range o.oneofs {
+			names := &yaml.Node{Kind: yaml.SequenceNode}
+			for _, property := range o.members[oneof] {
+				names.Content = append(names.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: property.Name})
+			}
+			groups.Content = append(groups.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: string(oneof.Desc.Name())}, names)
+		}
+		value, err := yaml.Marshal(groups)
+		if err != nil {
+			return
+		}
+		schema.SpecificationExtension = append(schema.SpecificationExtension, &openapi.NamedAny{
+			Name:  OneofExtensionName,
+			Value: &openapi.Any{Yaml: string(value)},
+		})
+		return
+	}
+
+	var required []string
+	for _, name := range schema.Required {
+		if !o.isMember(name) {
+			required = append(required, name)
+		}
+	}
+	schema.Required = required
+	if schema.Properties != nil && len(schema.Properties.AdditionalProperties) == 0 {
+		schema.Properties = nil
+	}
+
+	for _, oneof := range o.oneofs {
+		alternatives := make([]*openapi.SchemaOrReference, 0, len(o.members[oneof])+1)
+		set := make([]*openapi.SchemaOrReference, 0, len(o.members[oneof]))
+		for _, property := range o.members[oneof] {
+			alternatives = append(alternatives, &openapi.SchemaOrReference{
+				Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{
+					Type:       consts.SchemaObjectType,
+					Properties: &openapi.Properties{AdditionalProperties: []*openapi.NamedSchemaOrReference{property}},
+					Required:   []string{property.Name},
+				}},
+			})
+			set = append(set, &openapi.SchemaOrReference{
+				Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{
+					Required: []string{property.Name},
+				}},
+			})
+		}
+		// A oneof may be left unset: the last alternative matches the
+		// messages that hold none of its members.
+		alternatives = append(alternatives, &openapi.SchemaOrReference{
+			Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{
+				Type: consts.SchemaObjectType,
+				Not:  &openapi.Schema{AnyOf: set},
+			}},
+		})
+		if len(o.oneofs) == 1 {
+			schema.OneOf = alternatives
+			return
+		}
+		schema.AllOf = append(schema.AllOf, &openapi.SchemaOrReference{
+			Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{
+				OneOf: alternatives,
+			}},
+		})
+	}
+}
+
+func (o *OneofGroups) isMember(name string) bool {
+	for _, properties := range o.members {
+		for _, property := range properties {
+			if property.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasProperties reports whether schema describes at least one property,
+// either directly or through the compositions generated for oneofs.
+func HasProperties(schema *openapi.Schema) bool {
+	if schema == nil {
+		return false
+	}
+	return (schema.Properties != nil && len(schema.Properties.AdditionalProperties) > 0) ||
+		len(schema.OneOf) > 0 || len(schema.AllOf) > 0
+}
@@ -0,0 +1,209 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbgen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// testMessage builds a message whose fields are named after fields and belong
+// to the oneof at the same index in oneofs, or to none if it is -1.
+func testMessage(t *testing.T, fields []string, oneofs []int32, oneofNames []string) *protogen.Message {
+	msg := &descriptorpb.DescriptorProto{Name: proto.String("Msg")}
+	for _, name := range oneofNames {
+		msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(name)})
+	}
+	for i, name := range fields {
+		field := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(int32(i + 1)),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		}
+		if oneofs[i] >= 0 {
+			field.OneofIndex = proto.Int32(oneofs[i])
+		}
+		msg.Field = append(msg.Field, field)
+	}
+	file := &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("test.proto"),
+		Package:     proto.String("test"),
+		Syntax:      proto.String("proto3"),
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test")},
+		MessageType: []*descriptorpb.DescriptorProto{msg},
+	}
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"test.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plugin.Files[0].Messages[0]
+}
+
+// apply runs the oneof groups of style over the fields of message, the way the
+// generators build the properties of a message schema.
+func apply(message *protogen.Message, style string, required []string) *openapi.Schema {
+	groups := NewOneofGroups(style)
+	schema := &openapi.Schema{Properties: &openapi.Properties{}, Required: required}
+	for _, field := range message.Fields {
+		property := &openapi.NamedSchemaOrReference{
+			Name:  field.Desc.JSONName(),
+			Value: &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{Type: "string"}}},
+		}
+		if !groups.Add(field, property) {
+			schema.Properties.AdditionalProperties = append(schema.Properties.AdditionalProperties, property)
+		}
+	}
+	groups.Apply(schema)
+	return schema
+}
+
+// alternatives returns, for each alternative of a `oneOf`, the members it
+// requires, or the members it excludes prefixed by "!".
+func alternatives(list []*openapi.SchemaOrReference) [][]string {
+	var result [][]string
+	for _, alt := range list {
+		s := alt.GetSchema()
+		if s.Not != nil {
+			var excluded []string
+			for _, set := range s.Not.AnyOf {
+				excluded = append(excluded, "!"+set.GetSchema().Required[0])
+			}
+			result = append(result, excluded)
+			continue
+		}
+		result = append(result, s.Required)
+	}
+	return result
+}
+
+func propertyNames(schema *openapi.Schema) []string {
+	var names []string
+	if schema.Properties != nil {
+		for _, property := range schema.Properties.AdditionalProperties {
+			names = append(names, property.Name)
+		}
+	}
+	return names
+}
+
+func TestOneofGroupsApply(t *testing.T) {
+	tests := []struct {
+		name           string
+		fields         []string
+		oneofs         []int32
+		oneofNames     []string
+		style          string
+		required       []string
+		wantProperties []string
+		wantRequired   []string
+		wantOneOf      [][]string
+		wantAllOf      [][][]string
+		wantExtension  string
+	}{
+		{
+			name:           "no oneof",
+			fields:         []string{"id"},
+			oneofs:         []int32{-1},
+			style:          OneofStyleOneOf,
+			required:       []string{"id"},
+			wantProperties: []string{"id"},
+			wantRequired:   []string{"id"},
+		},
+		{
+			name:           "single oneof",
+			fields:         []string{"id", "email", "phone"},
+			oneofs:         []int32{-1, 0, 0},
+			oneofNames:     []string{"contact"},
+			style:          OneofStyleOneOf,
+			required:       []string{"id", "email"},
+			wantProperties: []string{"id"},
+			wantRequired:   []string{"id"},
+			wantOneOf:      [][]string{{"email"}, {"phone"}, {"!email", "!phone"}},
+		},
+		{
+			name:       "only oneof members",
+			fields:     []string{"email", "phone"},
+			oneofs:     []int32{0, 0},
+			oneofNames: []string{"contact"},
+			style:      OneofStyleOneOf,
+			wantOneOf:  [][]string{{"email"}, {"phone"}, {"!email", "!phone"}},
+		},
+		{
+			name:       "several oneofs",
+			fields:     []string{"email", "phone", "card", "cash"},
+			oneofs:     []int32{0, 0, 1, 1},
+			oneofNames: []string{"contact", "payment"},
+			style:      OneofStyleOneOf,
+			wantAllOf: [][][]string{
+				{{"email"}, {"phone"}, {"!email", "!phone"}},
+				{{"card"}, {"cash"}, {"!card", "!cash"}},
+			},
+		},
+		{
+			name:           "flatten",
+			fields:         []string{"id", "email", "phone"},
+			oneofs:         []int32{-1, 0, 0},
+			oneofNames:     []string{"contact"},
+			style:          OneofStyleFlatten,
+			required:       []string{"id"},
+			wantProperties: []string{"id", "email", "phone"},
+			wantRequired:   []string{"id"},
+			wantExtension:  "contact:\n    - email\n    - phone\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := apply(testMessage(t, tt.fields, tt.oneofs, tt.oneofNames), tt.style, tt.required)
+			if got := propertyNames(schema); !reflect.DeepEqual(got, tt.wantProperties) {
+				t.Errorf("properties = %v, want %v", got, tt.wantProperties)
+			}
+			if !reflect.DeepEqual(schema.Required, tt.wantRequired) {
+				t.Errorf("required = %v, want %v", schema.Required, tt.wantRequired)
+			}
+			if got := alternatives(schema.OneOf); !reflect.DeepEqual(got, tt.wantOneOf) {
+				t.Errorf("oneOf = %v, want %v", got, tt.wantOneOf)
+			}
+			var allOf [][][]string
+			for _, s := range schema.AllOf {
+				allOf = append(allOf, alternatives(s.GetSchema().OneOf))
+			}
+			if !reflect.DeepEqual(allOf, tt.wantAllOf) {
+				t.Errorf("allOf = %v, want %v", allOf, tt.wantAllOf)
+			}
+			var extension string
+			for _, ext := range schema.SpecificationExtension {
+				if ext.Name == OneofExtensionName {
+					extension = ext.Value.Yaml
+				}
+			}
+			if extension != tt.wantExtension {
+				t.Errorf("%s = %q, want %q", OneofExtensionName, extension, tt.wantExtension)
+			}
+		})
+	}
+}
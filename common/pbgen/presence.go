@@ -0,0 +1,98 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbgen
+
+import (
+	"encoding/base64"
+	"math"
+	"strconv"
+
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FieldRequired reports whether field is required by its cardinality, i.e. a
+// proto2 `required` field or one with the Editions LEGACY_REQUIRED presence.
+func FieldRequired(field protoreflect.FieldDescriptor) bool {
+	return field.Cardinality() == protoreflect.Required
+}
+
+// FieldNullable reports whether the scalar field tracks presence explicitly,
+// like proto3 `optional` fields, so that leaving it unset differs from
+// setting its zero value.
+func FieldNullable(field protoreflect.FieldDescriptor) bool {
+	if field.IsList() || field.Message() != nil || FieldRequired(field) || !field.HasPresence() {
+		return false
+	}
+	if parent, ok := field.Parent().(protoreflect.MessageDescriptor); ok && parent.IsMapEntry() {
+		return false
+	}
+	oneof := field.ContainingOneof()
+	return oneof == nil || oneof.IsSynthetic()
+}
+
+// DefaultForField returns the default value of field set by the proto2 or
+// Editions `default` option, in its JSON form. Enum values are given by name
+// if enumType is "string", and by number otherwise.
+func DefaultForField(field protoreflect.FieldDescriptor, enumType string) *openapi.DefaultType {
+	if !field.HasDefault() || field.IsList() {
+		return nil
+	}
+	value := field.Default()
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return &openapi.DefaultType{Oneof: &openapi.DefaultType_Boolean{Boolean: value.Bool()}}
+	case protoreflect.StringKind:
+		return defaultString(value.String())
+	case protoreflect.BytesKind:
+		return defaultString(base64.StdEncoding.EncodeToString(value.Bytes()))
+	case protoreflect.EnumKind:
+		if enumType == "string" {
+			return defaultString(string(field.DefaultEnumValue().Name()))
+		}
+		return defaultNumber(float64(value.Enum()))
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return defaultNumber(float64(value.Int()))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return defaultNumber(float64(value.Uint()))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		// 64-bit integers are strings in JSON.
+		return defaultString(strconv.FormatInt(value.Int(), 10))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return defaultString(strconv.FormatUint(value.Uint(), 10))
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		f := value.Float()
+		switch {
+		case math.IsNaN(f):
+			return defaultString("NaN")
+		case math.IsInf(f, 1):
+			return defaultString("Infinity")
+		case math.IsInf(f, -1):
+			return defaultString("-Infinity")
+		}
+		return defaultNumber(f)
+	}
+	return nil
+}
+
+func defaultString(s string) *openapi.DefaultType {
+	return &openapi.DefaultType{Oneof: &openapi.DefaultType_String_{String_: s}}
+}
+
+func defaultNumber(n float64) *openapi.DefaultType {
+	return &openapi.DefaultType{Oneof: &openapi.DefaultType_Number{Number: n}}
+}
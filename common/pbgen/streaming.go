@@ -0,0 +1,90 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbgen
+
+import (
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	common "github.com/hertz-contrib/swagger-generate/common/utils"
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// ApplyStreaming documents the streaming sides of method on op. The method is
+// flagged with the `x-streaming` extension, and the JSON bodies of its
+// streaming sides are documented as streams of the same schema: requests as
+// `application/x-ndjson`, responses as contentType. With disableTryItOut, the
+// operation is also marked as not callable from the UI.
+func ApplyStreaming(method *protogen.Method, op *openapi.Operation, contentType string, disableTryItOut bool) {
+	mode := common.StreamingMode(method.Desc.IsStreamingClient(), method.Desc.IsStreamingServer())
+	if mode == "" {
+		return
+	}
+	op.SpecificationExtension = append(op.SpecificationExtension, &openapi.NamedAny{
+		Name:  consts.StreamingExtensionName,
+		Value: &openapi.Any{Yaml: mode},
+	})
+
+	if method.Desc.IsStreamingClient() {
+		if body := op.GetRequestBody().GetRequestBody(); body != nil && streamContent(body.Content, consts.ContentTypeNDJSON) {
+			body.Description = common.AppendParagraph(body.Description, common.StreamBodyDescription(consts.ContentTypeNDJSON))
+		}
+	}
+	if method.Desc.IsStreamingServer() && op.Responses != nil {
+		for _, named := range op.Responses.ResponseOrReference {
+			if named.Name != consts.StatusOK {
+				continue
+			}
+			if response := named.Value.GetResponse(); response != nil && streamContent(response.Content, contentType) {
+				response.Description = common.AppendParagraph(response.Description, common.StreamBodyDescription(contentType))
+			}
+		}
+	}
+
+	if disableTryItOut {
+		op.SpecificationExtension = append(op.SpecificationExtension, &openapi.NamedAny{
+			Name:  consts.TryItOutExtensionName,
+			Value: &openapi.Any{Yaml: "false"},
+		})
+		op.Description = common.AppendParagraph(op.Description, common.StreamingTryItOutNote)
+	}
+}
+
+// LookupStreamContentType checks the `stream_content_type` option, falling
+// back to `application/x-ndjson` if it is not a stream content type.
+func LookupStreamContentType(contentType string, diag *diagnostics.Collector) string {
+	if !common.IsStreamContentType(contentType) {
+		diag.Errorf("stream_content_type: unsupported content type %q, using %q", contentType, consts.ContentTypeNDJSON)
+		return consts.ContentTypeNDJSON
+	}
+	return contentType
+}
+
+// streamContent replaces the JSON media type of content by contentType. It
+// reports whether content had a JSON media type.
+func streamContent(content *openapi.MediaTypes, contentType string) bool {
+	if content == nil {
+		return false
+	}
+	for _, mediaType := range content.AdditionalProperties {
+		if mediaType.Name == consts.ContentTypeJSON {
+			mediaType.Name = contentType
+			return true
+		}
+	}
+	return false
+}
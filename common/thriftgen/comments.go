@@ -0,0 +1,104 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package thriftgen holds the parts of the OpenAPI document generation that
+// thrift-gen-http-swagger and thrift-gen-rpc-swagger share. The helpers work
+// on the thrift_reflection descriptors and the OpenAPI model of idl/thrift.
+package thriftgen
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cloudwego/thriftgo/thrift_reflection"
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	common "github.com/hertz-contrib/swagger-generate/common/utils"
+	openapi "github.com/hertz-contrib/swagger-generate/idl/thrift"
+)
+
+var commentPattern = regexp.MustCompile(consts.CommentPatternRegexp)
+
+// FilterComment removes comment markers and directives from comments.
+func FilterComment(str string) string {
+	var comments []string
+	matches := commentPattern.FindAllStringSubmatch(str, -1)
+
+	for _, match := range matches {
+		if match[1] != "" {
+			// Handle one-line comments
+			comments = append(comments, strings.TrimSpace(match[1]))
+		} else if match[2] != "" {
+			// Handle multiline comments
+			multiLineComment := match[2]
+			lines := strings.Split(multiLineComment, "\n")
+
+			// Find the minimum indentation level (excluding empty lines)
+			minIndent := -1
+			for _, line := range lines {
+				trimmedLine := strings.TrimSpace(line)
+				if trimmedLine != "" {
+					lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+					if minIndent == -1 || lineIndent < minIndent {
+						minIndent = lineIndent
+					}
+				}
+			}
+
+			// Remove the minimum indentation and any leading '*' from each line
+			for i, line := range lines {
+				if minIndent > 0 && len(line) >= minIndent {
+					line = line[minIndent:]
+				}
+				lines[i] = strings.TrimPrefix(line, "*")
+			}
+
+			// Remove leading and trailing empty lines from the comment block
+			comments = append(comments, strings.TrimSpace(strings.Join(lines, "\n")))
+		}
+	}
+
+	return common.StripCommentDirectives(strings.Join(comments, "\n"))
+}
+
+// FieldExample returns the example set by the `@example` directive in the
+// comments of field, or nil if there is none.
+func FieldExample(field *thrift_reflection.FieldDescriptor) *openapi.Any {
+	example, ok := common.CommentExample(field.Comments)
+	if !ok {
+		return nil
+	}
+	isString := field.Type != nil && (field.Type.GetName() == "string" || field.Type.GetName() == "binary" || field.Type.IsEnum())
+	return &openapi.Any{Yaml: common.ExampleYAML(example, isString)}
+}
+
+// ExampleSchema sets the example of schema. A reference is wrapped with
+// `allOf`, since the siblings of `$ref` are ignored.
+func ExampleSchema(schema *openapi.SchemaOrReference, example *openapi.Any) *openapi.SchemaOrReference {
+	schema = WrapReference(schema)
+	schema.Schema.Example = example
+	return schema
+}
+
+// WrapReference wraps a reference with `allOf`, so that siblings can be set
+// next to it. Schemas are returned as is.
+func WrapReference(schema *openapi.SchemaOrReference) *openapi.SchemaOrReference {
+	if schema.IsSetSchema() {
+		return schema
+	}
+	return &openapi.SchemaOrReference{Schema: &openapi.Schema{
+		AllOf: []*openapi.SchemaOrReference{schema},
+	}}
+}
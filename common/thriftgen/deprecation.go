@@ -0,0 +1,69 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thriftgen
+
+import (
+	"github.com/cloudwego/thriftgo/thrift_reflection"
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	common "github.com/hertz-contrib/swagger-generate/common/utils"
+	openapi "github.com/hertz-contrib/swagger-generate/idl/thrift"
+)
+
+// IsDeprecated reports whether an element with annotations and comments is
+// deprecated, either by its `deprecated` annotation or by a "Deprecated:"
+// note or `@deprecated` directive in its comments.
+func IsDeprecated(annotations map[string][]string, comments string) bool {
+	return common.IsDeprecatedAnnotation(annotations[consts.Deprecated]) || common.IsDeprecatedComment(comments)
+}
+
+// ApplyDeprecation marks op as deprecated if its method or service is.
+func ApplyDeprecation(s *thrift_reflection.ServiceDescriptor, m *thrift_reflection.MethodDescriptor, op *openapi.Operation) {
+	if IsDeprecated(m.Annotations, m.Comments) || IsDeprecated(s.Annotations, s.Comments) {
+		op.Deprecated = true
+	}
+}
+
+// FieldDescription returns the description of field, which lists the
+// deprecated values of enum fields.
+func FieldDescription(field *thrift_reflection.FieldDescriptor) string {
+	description := FilterComment(field.Comments)
+	if field.Type == nil || !field.Type.IsEnum() {
+		return description
+	}
+	enum, err := field.Type.GetEnumDescriptor()
+	if err != nil || enum == nil {
+		return description
+	}
+	var names []string
+	for _, value := range enum.Values {
+		if IsDeprecated(value.Annotations, value.Comments) {
+			names = append(names, value.Name)
+		}
+	}
+	if note := common.DeprecatedEnumValuesNote(names); note != "" {
+		description = common.AppendParagraph(description, note)
+	}
+	return description
+}
+
+// DeprecateSchema marks schema as deprecated. A reference is wrapped with
+// `allOf`, since the siblings of `$ref` are ignored.
+func DeprecateSchema(schema *openapi.SchemaOrReference) *openapi.SchemaOrReference {
+	schema = WrapReference(schema)
+	schema.Schema.Deprecated = true
+	return schema
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thriftgen
+
+import (
+	"github.com/cloudwego/thriftgo/thrift_reflection"
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+)
+
+// LookupDefaultResponse resolves the struct or exception named by the
+// `DefaultResponse` argument in file. Structs of included files are named
+// with the include prefix, e.g. `base.BaseResp`.
+func LookupDefaultResponse(file *thrift_reflection.FileDescriptor, name string, diag *diagnostics.Collector) *thrift_reflection.StructDescriptor {
+	if name == "" {
+		return nil
+	}
+	if desc := file.GetStructDescriptor(name); desc != nil {
+		return desc
+	}
+	if desc := file.GetExceptionDescriptor(name); desc != nil {
+		return desc
+	}
+	diag.Errorf("DefaultResponse: struct %q not found", name)
+	return nil
+}
+
+// SkipDefaultResponse reports whether m opts out of the default response
+// with `openapi.skip_default_response="true"`.
+func SkipDefaultResponse(m *thrift_reflection.MethodDescriptor) bool {
+	skip := m.Annotations[consts.OpenapiSkipDefaultResponse]
+	return len(skip) > 0 && skip[0] == "true"
+}
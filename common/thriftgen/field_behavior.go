@@ -0,0 +1,130 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thriftgen
+
+import (
+	"strings"
+
+	"github.com/cloudwego/thriftgo/thrift_reflection"
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	openapi "github.com/hertz-contrib/swagger-generate/idl/thrift"
+)
+
+// Shorthands of the `openapi.property` annotation for fields only sent in
+// requests or only returned in responses, e.g. `openapi.property = "OUTPUT_ONLY"`.
+const (
+	FieldBehaviorInputOnly  = "INPUT_ONLY"
+	FieldBehaviorOutputOnly = "OUTPUT_ONLY"
+)
+
+// Directions of the schemas split by the `SplitIOSchemas` argument, used as
+// suffix of their names.
+const (
+	DirectionInput  = "Input"
+	DirectionOutput = "Output"
+)
+
+// FieldBehavior returns whether descriptor is marked INPUT_ONLY or
+// OUTPUT_ONLY by the `openapi.property` shorthand.
+func FieldBehavior(descriptor *thrift_reflection.FieldDescriptor) (inputOnly, outputOnly bool) {
+	values := descriptor.Annotations[consts.OpenapiProperty]
+	if len(values) == 0 {
+		return false, false
+	}
+	switch trimQuote(values[0]) {
+	case FieldBehaviorInputOnly:
+		return true, false
+	case FieldBehaviorOutputOnly:
+		return false, true
+	}
+	return false, false
+}
+
+func trimQuote(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// SchemaName returns the name of the schema of s while building the schemas
+// of direction. Structs split by direction get the direction as suffix.
+func SchemaName(s *thrift_reflection.StructDescriptor, direction string) string {
+	name := s.GetName()
+	if direction != "" && SplitsByDirection(s) {
+		name += direction
+	}
+	return name
+}
+
+// SplitsByDirection reports whether s gets separate Input and Output schemas
+// under the `SplitIOSchemas` argument, because it has input only or output
+// only fields, directly or through the structs it uses.
+func SplitsByDirection(s *thrift_reflection.StructDescriptor) bool {
+	return hasDirectionalFields(s, map[string]bool{})
+}
+
+func hasDirectionalFields(s *thrift_reflection.StructDescriptor, visited map[string]bool) bool {
+	if visited[s.GetName()] {
+		return false
+	}
+	visited[s.GetName()] = true
+	for _, field := range s.GetFields() {
+		if inputOnly, outputOnly := FieldBehavior(field); inputOnly || outputOnly {
+			return true
+		}
+		if hasDirectionalType(field.GetType(), visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDirectionalType reports whether the structs used by t, through
+// containers and typedefs, have directional fields.
+func hasDirectionalType(t *thrift_reflection.TypeDescriptor, visited map[string]bool) bool {
+	switch {
+	case t == nil:
+		return false
+	case t.IsStruct():
+		s, err := t.GetStructDescriptor()
+		return err == nil && hasDirectionalFields(s, visited)
+	case t.IsMap(), t.IsList():
+		return hasDirectionalType(t.GetValueType(), visited)
+	case t.IsTypedef():
+		typedef, err := t.GetTypedefDescriptor()
+		return err == nil && hasDirectionalType(typedef.Type, visited)
+	}
+	return false
+}
+
+// HiddenField reports whether field is left out of the schemas of direction,
+// because it is only visible in the other direction.
+func HiddenField(field *thrift_reflection.FieldDescriptor, direction string) bool {
+	inputOnly, outputOnly := FieldBehavior(field)
+	return direction == DirectionInput && outputOnly || direction == DirectionOutput && inputOnly
+}
+
+// BehaviorSchema wraps the reference of a field marked INPUT_ONLY or
+// OUTPUT_ONLY, so that `readOnly` or `writeOnly` can be set next to it.
+func BehaviorSchema(field *thrift_reflection.FieldDescriptor, schema *openapi.SchemaOrReference) *openapi.SchemaOrReference {
+	if inputOnly, outputOnly := FieldBehavior(field); inputOnly || outputOnly {
+		return WrapReference(schema)
+	}
+	return schema
+}
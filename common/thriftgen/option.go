@@ -0,0 +1,114 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thriftgen
+
+import (
+	"fmt"
+	"strings"
+
+	thriftgoutils "github.com/cloudwego/thriftgo/utils"
+)
+
+// ParseOptionValue turns an annotation payload such as `{a: "b", c: [1, 2]}`
+// into a tree of maps, lists and string leaves. An unquoted null becomes nil.
+func ParseOptionValue(content string) (interface{}, error) {
+	content = strings.TrimSpace(content)
+	switch {
+	case content == "null":
+		return nil, nil
+	case strings.HasPrefix(content, "'") || strings.HasPrefix(content, "\""):
+		return trimQuote(content), nil
+	case strings.HasPrefix(content, "{"):
+		inner, err := enclosed(content, '{', '}')
+		if err != nil {
+			return nil, err
+		}
+		m := map[string]interface{}{}
+		if strings.TrimSpace(inner) == "" {
+			return m, nil
+		}
+		kv, err := thriftgoutils.ParseKV(content)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range kv {
+			if m[trimQuote(k)], err = ParseOptionValue(v); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case strings.HasPrefix(content, "["):
+		inner, err := enclosed(content, '[', ']')
+		if err != nil {
+			return nil, err
+		}
+		var list []interface{}
+		if strings.TrimSpace(inner) == "" {
+			return list, nil
+		}
+		arr, err := thriftgoutils.ParseArr(content)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range arr {
+			elem, err := ParseOptionValue(v)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, elem)
+		}
+		return list, nil
+	}
+	return content, nil
+}
+
+// enclosed returns what content holds between its opening and closing
+// brackets, and an error if the brackets are not balanced.
+func enclosed(content string, open, closing byte) (string, error) {
+	if len(content) < 2 || content[len(content)-1] != closing {
+		return "", fmt.Errorf("malformed value %s: missing closing %q", content, closing)
+	}
+	depth := 0
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth == 0 && i != len(content)-1 {
+				return "", fmt.Errorf("malformed value %s: unexpected %q", content, content[i+1:])
+			}
+			if depth < 0 {
+				return "", fmt.Errorf("malformed value %s: unbalanced %q", content, c)
+			}
+		}
+	}
+	if depth != 0 || quote != 0 {
+		return "", fmt.Errorf("malformed value %s: unbalanced brackets or quotes", content)
+	}
+	return content[1 : len(content)-1], nil
+}
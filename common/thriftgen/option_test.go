@@ -0,0 +1,64 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thriftgen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOptionValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "open brace", content: "{", wantErr: true},
+		{name: "open bracket", content: "[", wantErr: true},
+		{name: "empty object", content: "{}", want: map[string]interface{}{}},
+		{name: "blank object", content: "{ }", want: map[string]interface{}{}},
+		{name: "empty list", content: "[]", want: []interface{}(nil)},
+		{name: "unbalanced value", content: `{a: [1, 2}`, wantErr: true},
+		{name: "unclosed object", content: `{a: "b"`, wantErr: true},
+		{name: "trailing text", content: `{a: "b"} c}`, wantErr: true},
+		{name: "null", content: "null", want: nil},
+		{name: "quoted", content: `"a b"`, want: "a b"},
+		{name: "scalar", content: "1", want: "1"},
+		{
+			name:    "nested",
+			content: `{a: "b", c: [1, 2], d: {e: null}}`,
+			want: map[string]interface{}{
+				"a": "b",
+				"c": []interface{}{"1", "2"},
+				"d": map[string]interface{}{"e": nil},
+			},
+		},
+		{name: "brackets in strings", content: `{a: "}{"}`, want: map[string]interface{}{"a": "}{"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOptionValue(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOptionValue(%q) error = %v, wantErr %v", tt.content, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseOptionValue(%q) = %#v, want %#v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
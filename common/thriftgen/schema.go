@@ -0,0 +1,61 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thriftgen
+
+import (
+	openapi "github.com/hertz-contrib/swagger-generate/idl/thrift"
+)
+
+// PropertyNames returns the names of the properties of schema.
+func PropertyNames(schema *openapi.Schema) []string {
+	if schema.Properties == nil {
+		return nil
+	}
+	names := make([]string, 0, len(schema.Properties.AdditionalProperties))
+	for _, property := range schema.Properties.AdditionalProperties {
+		names = append(names, property.Name)
+	}
+	return names
+}
+
+// KeepProperties drops the properties and required names of schema that are
+// not in names. A struct has a schema per location (body, form...) holding
+// the fields of that location, and the `openapi.schema` annotation of the
+// struct is merged into each of them: this keeps its overrides from adding
+// the fields of the other locations.
+func KeepProperties(schema *openapi.Schema, names []string) {
+	keep := make(map[string]bool, len(names))
+	for _, name := range names {
+		keep[name] = true
+	}
+	if schema.Properties != nil {
+		properties := schema.Properties.AdditionalProperties[:0]
+		for _, property := range schema.Properties.AdditionalProperties {
+			if keep[property.Name] {
+				properties = append(properties, property)
+			}
+		}
+		schema.Properties.AdditionalProperties = properties
+	}
+	var required []string
+	for _, name := range schema.Required {
+		if keep[name] {
+			required = append(required, name)
+		}
+	}
+	schema.Required = required
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thriftgen
+
+import (
+	"github.com/cloudwego/thriftgo/thrift_reflection"
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	common "github.com/hertz-contrib/swagger-generate/common/utils"
+	openapi "github.com/hertz-contrib/swagger-generate/idl/thrift"
+)
+
+// LookupStreamContentType checks the `StreamContentType` argument, which
+// defaults to `application/x-ndjson`.
+func LookupStreamContentType(contentType string, diag *diagnostics.Collector) string {
+	if contentType == "" {
+		return consts.ContentTypeNDJSON
+	}
+	if !common.IsStreamContentType(contentType) {
+		diag.Errorf("StreamContentType: unsupported content type %q, using %q", contentType, consts.ContentTypeNDJSON)
+		return consts.ContentTypeNDJSON
+	}
+	return contentType
+}
+
+// ApplyStreaming documents the Kitex `streaming.mode` of m on op. The method
+// is flagged with the `x-streaming` extension, and the JSON bodies of its
+// streaming sides are documented as streams of the same schema: requests as
+// `application/x-ndjson`, responses as streamContentType. With
+// disableTryItOut, the operation is also marked as not callable from the UI.
+func ApplyStreaming(m *thrift_reflection.MethodDescriptor, op *openapi.Operation, streamContentType string, disableTryItOut bool) {
+	var mode string
+	if modes := m.Annotations[consts.StreamingMode]; len(modes) > 0 {
+		mode = common.ThriftStreamingMode(modes[0])
+	}
+	if mode == "" {
+		return
+	}
+	op.SpecificationExtension = append(op.SpecificationExtension, &openapi.NamedAny{
+		Name:  consts.StreamingExtensionName,
+		Value: &openapi.Any{Yaml: mode},
+	})
+
+	if mode != consts.StreamingServer && op.RequestBody != nil {
+		if body := op.RequestBody.RequestBody; body != nil && streamContent(body.Content, consts.ContentTypeNDJSON) {
+			body.Description = common.AppendParagraph(body.Description, common.StreamBodyDescription(consts.ContentTypeNDJSON))
+		}
+	}
+	if mode != consts.StreamingClient && op.Responses != nil {
+		for _, named := range op.Responses.ResponseOrReference {
+			if named.Name != consts.StatusOK || named.Value == nil {
+				continue
+			}
+			if response := named.Value.Response; response != nil && streamContent(response.Content, streamContentType) {
+				response.Description = common.AppendParagraph(response.Description, common.StreamBodyDescription(streamContentType))
+			}
+		}
+	}
+
+	if disableTryItOut {
+		op.SpecificationExtension = append(op.SpecificationExtension, &openapi.NamedAny{
+			Name:  consts.TryItOutExtensionName,
+			Value: &openapi.Any{Yaml: "false"},
+		})
+		op.Description = common.AppendParagraph(op.Description, common.StreamingTryItOutNote)
+	}
+}
+
+// streamContent replaces the JSON media type of content by contentType. It
+// reports whether content had a JSON media type.
+func streamContent(content *openapi.MediaTypes, contentType string) bool {
+	if content == nil {
+		return false
+	}
+	for _, mediaType := range content.AdditionalProperties {
+		if mediaType.Name == consts.ContentTypeJSON {
+			mediaType.Name = contentType
+			return true
+		}
+	}
+	return false
+}
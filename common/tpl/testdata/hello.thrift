@@ -0,0 +1,15 @@
+namespace go hello
+
+struct Item {
+    1: string name
+    2: i32 count
+}
+
+exception NotFound {
+    1: string reason
+}
+
+service Svc {
+    Item Get(1: Item req) throws (1: NotFound nf)
+    Item Put(1: Item req)
+}
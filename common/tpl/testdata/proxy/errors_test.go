@@ -0,0 +1,120 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+)
+
+func TestHandleCallError(t *testing.T) {
+	exceptionStatuses["Svc/Get"] = http.StatusNotFound
+	defer delete(exceptionStatuses, "Svc/Get")
+	errorStatuses["Svc/Get"] = map[int32]int{1001: http.StatusForbidden}
+	defer delete(errorStatuses, "Svc/Get")
+
+	exception := kerrors.ErrRemoteOrNetwork.WithCause(errors.New(`{"reason":"missing"}`))
+	tests := []struct {
+		name       string
+		method     string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "declared exception",
+			method:     "Svc/Get",
+			err:        exception,
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"reason":"missing"}`,
+		},
+		{
+			name:       "method without exception",
+			method:     "Svc/Put",
+			err:        exception,
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "transport error",
+			method:     "Svc/Get",
+			err:        kerrors.ErrRemoteOrNetwork.WithCause(errors.New("connection reset")),
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "documented biz error",
+			method:     "Svc/Get",
+			err:        kerrors.NewBizStatusError(1001, "denied"),
+			wantStatus: http.StatusForbidden,
+			wantBody:   `{"code":1001,"extra":null,"message":"denied"}`,
+		},
+		{
+			name:       "undocumented biz error",
+			method:     "Svc/Put",
+			err:        kerrors.NewBizStatusError(1001, "denied"),
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "biz error with an HTTP status code",
+			method:     "Svc/Put",
+			err:        kerrors.NewBizStatusError(http.StatusConflict, "conflict"),
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"code":409,"extra":null,"message":"conflict"}`,
+		},
+		{
+			name:       "biz error with an HTTP success code",
+			method:     "Svc/Put",
+			err:        kerrors.NewBizStatusError(http.StatusOK, "ok"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := app.NewContext(0)
+			handleCallError(ctx, tt.method, tt.err)
+			if got := ctx.Response.StatusCode(); got != tt.wantStatus {
+				t.Errorf("status = %d, want %d", got, tt.wantStatus)
+			}
+			if got := string(ctx.Response.Body()); tt.wantBody != "" && got != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestExceptionPayloadCodecs(t *testing.T) {
+	defer func(statuses map[string]int) { exceptionStatuses = statuses }(exceptionStatuses)
+	for _, declared := range []bool{false, true} {
+		exceptionStatuses = map[string]int{}
+		if declared {
+			exceptionStatuses["Svc/Get"] = http.StatusNotFound
+		}
+		for _, codec := range append(payloadCodecs, "go") {
+			o := ClientOptions{Transport: supportedTransports[0], PayloadCodec: codec, HostPorts: []string{kitexAddr}}
+			_, err := o.clientOptions()
+			// Only dynamicgo decodes the exceptions, go is rejected when
+			// the IDL declares some.
+			supported := checkOption("payload codec", codec, payloadCodecs) == nil
+			if wantErr := !supported || declared && codec == "go"; (err != nil) != wantErr {
+				t.Errorf("exceptions declared = %v, codec %s: clientOptions() error = %v, wantErr %v", declared, codec, err, wantErr)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger
+
+import (
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+func TestSetBackwardMetainfo(t *testing.T) {
+	tests := []struct {
+		name       string
+		backward   map[string]string
+		wantHeader map[string]string
+	}{
+		{
+			name:       "no metainfo",
+			wantHeader: map[string]string{"Access-Control-Expose-Headers": ""},
+		},
+		{
+			name:     "metainfo",
+			backward: map[string]string{"LOG_ID": "123", "ENV": "test"},
+			wantHeader: map[string]string{
+				"X-Metainfo-Log-Id":             "123",
+				"X-Metainfo-Env":                "test",
+				"Access-Control-Expose-Headers": "X-Metainfo-Env, X-Metainfo-Log-Id",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := app.NewContext(0)
+			setBackwardMetainfo(ctx, tt.backward)
+			for name, want := range tt.wantHeader {
+				if got := string(ctx.Response.Header.Peek(name)); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+const mockSpec = `
+openapi: 3.0.3
+paths: {}
+components:
+  schemas:
+    Contact:
+      type: object
+      required: [id]
+      properties:
+        id: {type: integer, minimum: 1}
+      oneOf:
+        - type: object
+          required: [email]
+          properties:
+            email: {type: string, format: email}
+        - type: object
+          required: [phone]
+          properties:
+            phone: {type: string, minLength: 8}
+        - type: object
+          not:
+            anyOf:
+              - required: [email]
+              - required: [phone]
+    Audit:
+      type: object
+      properties:
+        by: {type: string}
+      allOf:
+        - $ref: '#/components/schemas/Contact'
+        - type: object
+          properties:
+            at: {type: string, format: date-time}
+    Tags:
+      type: array
+      items: {type: string, enum: [a, b]}
+      minItems: 2
+      maxItems: 2
+    Choice:
+      oneOf:
+        - type: string
+          enum: [x]
+`
+
+func TestMockValue(t *testing.T) {
+	d, err := newMockDocument([]byte(mockSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &requestValidator{schemas: d.schemas}
+	tests := []struct {
+		name     string
+		schema   string
+		wantKeys []string // Keys every mock object has.
+		anyKeys  []string // Keys of which a mock object has at most one.
+	}{
+		{name: "properties and oneOf", schema: "Contact", wantKeys: []string{"id"}, anyKeys: []string{"email", "phone"}},
+		{name: "properties and allOf", schema: "Audit", wantKeys: []string{"by", "id", "at"}, anyKeys: []string{"email", "phone"}},
+		{name: "array", schema: "Tags"},
+		{name: "oneOf only", schema: "Choice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := map[string]interface{}{"$ref": "#/components/schemas/" + tt.schema}
+			for seed := int64(0); seed < 20; seed++ {
+				value := d.value(schema, rand.New(rand.NewSource(seed)), 0)
+				if value == nil {
+					t.Fatalf("seed %d: value = nil", seed)
+				}
+				if obj, ok := value.(map[string]interface{}); ok {
+					for _, key := range tt.wantKeys {
+						if _, ok := obj[key]; !ok {
+							t.Errorf("seed %d: value = %v, missing %q", seed, obj, key)
+						}
+					}
+					count := 0
+					for _, key := range tt.anyKeys {
+						if _, ok := obj[key]; ok {
+							count++
+						}
+					}
+					if count > 1 {
+						t.Errorf("seed %d: value = %v, has several of %v", seed, obj, tt.anyKeys)
+					}
+				}
+				body, err := json.Marshal(value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if violations := v.validateBody(body, schema); len(violations) > 0 {
+					t.Errorf("seed %d: value %s does not match its schema: %v", seed, body, violations)
+				}
+			}
+		})
+	}
+}
+
+func TestMockInteger(t *testing.T) {
+	tests := []struct {
+		name           string
+		lo, hi         float64
+		wantLo, wantHi int64
+	}{
+		{name: "small", lo: -3, hi: 3, wantLo: -3, wantHi: 3},
+		{name: "single value", lo: 7, hi: 7, wantLo: 7, wantHi: 7},
+		{name: "empty", lo: 7, hi: 5, wantLo: 7, wantHi: 7},
+		{name: "wider than int64", lo: -9e18, hi: 9e18, wantLo: -9e18, wantHi: 9e18},
+		{name: "whole int64 range", lo: -1e30, hi: 1e30, wantLo: math.MinInt64, wantHi: math.MaxInt64},
+		{name: "above int64", lo: 1e19, hi: 1e20, wantLo: math.MaxInt64, wantHi: math.MaxInt64},
+		{name: "below int64", lo: -1e20, hi: -1e19, wantLo: math.MinInt64, wantHi: math.MinInt64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 100; i++ {
+				if got := mockInteger(tt.lo, tt.hi, rnd); got < tt.wantLo || got > tt.wantHi {
+					t.Fatalf("mockInteger(%g, %g) = %d, want within [%d, %d]", tt.lo, tt.hi, got, tt.wantLo, tt.wantHi)
+				}
+			}
+		})
+	}
+}
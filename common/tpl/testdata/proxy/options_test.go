@@ -0,0 +1,142 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestClientOptions(t *testing.T) {
+	type test struct {
+		name    string
+		opts    ClientOptions
+		wantErr bool
+	}
+	tests := []test{
+		{name: "grpc", opts: ClientOptions{Transport: "grpc", PayloadCodec: payloadCodecs[0], HostPorts: []string{kitexAddr}}, wantErr: true},
+		{name: "unknown codec", opts: ClientOptions{Transport: supportedTransports[0], PayloadCodec: "json", HostPorts: []string{kitexAddr}}, wantErr: true},
+		{name: "no host ports", opts: ClientOptions{Transport: supportedTransports[0], PayloadCodec: payloadCodecs[0]}, wantErr: true},
+	}
+	defer func(statuses map[string]int) { exceptionStatuses = statuses }(exceptionStatuses)
+	exceptionStatuses = map[string]int{}
+	for _, transport := range supportedTransports {
+		for _, codec := range payloadCodecs {
+			tests = append(tests, test{
+				name: transport + "/" + codec,
+				opts: ClientOptions{Transport: transport, PayloadCodec: codec, HostPorts: []string{kitexAddr}},
+			})
+		}
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.opts.clientOptions()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("clientOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientOptionsRegisterFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "supported", args: []string{"-kitex-transport", supportedTransports[len(supportedTransports)-1], "-kitex-payload-codec", payloadCodecs[0]}},
+		{name: "unsupported transport", args: []string{"-kitex-transport", "grpc"}, wantErr: true},
+		{name: "unsupported payload codec", args: []string{"-kitex-payload-codec", "json"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := ClientOptions{Transport: supportedTransports[0], PayloadCodec: payloadCodecs[0], HostPorts: []string{kitexAddr}}
+			fs := flag.NewFlagSet("proxy", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			o.RegisterFlags(fs)
+			if err := fs.Parse(tt.args); (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewServerEnv(t *testing.T) {
+	defer func(mock MockOptions, client ClientOptions, validate bool) {
+		MockMode, ProxyClientOptions, ValidateRequests = mock, client, validate
+	}(MockMode, ProxyClientOptions, ValidateRequests)
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		args    []string // Flags of MockMode and ProxyClientOptions.
+		check   func(t *testing.T)
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			env:  map[string]string{"SWAGGER_MOCK": "true", "SWAGGER_VALIDATE": "false", "SWAGGER_KITEX_RPC_TIMEOUT": "2s"},
+			check: func(t *testing.T) {
+				if !MockMode.Enabled || ValidateRequests || ProxyClientOptions.RPCTimeout != 2*time.Second {
+					t.Errorf("mock = %v, validate = %v, RPC timeout = %v", MockMode.Enabled, ValidateRequests, ProxyClientOptions.RPCTimeout)
+				}
+			},
+		},
+		{
+			name: "flag set",
+			env:  map[string]string{"SWAGGER_MOCK": "true", "SWAGGER_KITEX_RPC_TIMEOUT": "2s"},
+			args: []string{"-swagger-mock=false", "-kitex-rpc-timeout", "1s"},
+			check: func(t *testing.T) {
+				if MockMode.Enabled || ProxyClientOptions.RPCTimeout != time.Second {
+					t.Errorf("mock = %v, RPC timeout = %v", MockMode.Enabled, ProxyClientOptions.RPCTimeout)
+				}
+			},
+		},
+		{name: "invalid mock", env: map[string]string{"SWAGGER_MOCK": "maybe"}, wantErr: true},
+		{name: "invalid mock seed", env: map[string]string{"SWAGGER_MOCK_SEED": "x"}, wantErr: true},
+		{name: "invalid replay", env: map[string]string{"SWAGGER_REPLAY": "maybe"}, wantErr: true},
+		{name: "invalid validate", env: map[string]string{"SWAGGER_VALIDATE": "maybe"}, wantErr: true},
+		{name: "invalid timeout", env: map[string]string{"SWAGGER_KITEX_CONNECT_TIMEOUT": "soon"}, wantErr: true},
+		{name: "unsupported transport", env: map[string]string{"SWAGGER_KITEX_TRANSPORT": "grpc"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			MockMode = MockOptions{}
+			ProxyClientOptions = ClientOptions{Transport: supportedTransports[0], PayloadCodec: payloadCodecs[0], HostPorts: []string{kitexAddr}}
+			ValidateRequests = true
+			fs := flag.NewFlagSet("proxy", flag.ContinueOnError)
+			MockMode.RegisterFlags(fs)
+			ProxyClientOptions.RegisterFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			_, err := newServer(openapiYAML)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newServer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t)
+			}
+		})
+	}
+}
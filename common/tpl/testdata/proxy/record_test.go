@@ -0,0 +1,128 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewServerReplayEndpoint(t *testing.T) {
+	defer func(enabled bool) { ReplayEndpoint = enabled }(ReplayEndpoint)
+	for _, enabled := range []bool{false, true} {
+		ReplayEndpoint = enabled
+		h, err := newServer(openapiYAML)
+		if err != nil {
+			t.Fatal(err)
+		}
+		registered := false
+		for _, route := range h.Routes() {
+			if route.Path == "/replay" {
+				registered = true
+			}
+		}
+		if registered != enabled {
+			t.Errorf("ReplayEndpoint = %v: /replay registered = %v", enabled, registered)
+		}
+	}
+}
+
+func TestRecordCall(t *testing.T) {
+	defer func(file string) { RecordFile = file }(RecordFile)
+	RecordFile = filepath.Join(t.TempDir(), "calls.jsonl")
+
+	recordCall(context.Background(), "Svc", "Get", `{"id":1}`, `{"name":"a"}`, nil, 1500*time.Microsecond)
+	recordCall(context.Background(), "Svc", "Get", `not json`, nil, errors.New("boom"), 0)
+
+	f, err := os.Open(RecordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var records []CallRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec CallRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid record %s: %s", scanner.Bytes(), err)
+		}
+		records = append(records, rec)
+	}
+	if len(records) != 2 {
+		t.Fatalf("recorded %d calls, want 2", len(records))
+	}
+	if got := string(records[0].Response); got != `{"name":"a"}` || records[0].LatencyMS != 1.5 {
+		t.Errorf("first record = response %s, latency %v", got, records[0].LatencyMS)
+	}
+	if records[1].Error != "boom" || records[1].Response != nil {
+		t.Errorf("second record = error %q, response %s", records[1].Error, records[1].Response)
+	}
+}
+
+func TestDiffJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		recorded string
+		replayed string
+		want     []ReplayDiff
+	}{
+		{name: "equal", recorded: `{"a":1,"b":[1,2]}`, replayed: `{"b":[1,2],"a":1}`},
+		{
+			name:     "changed value",
+			recorded: `{"a":{"b":"x"}}`,
+			replayed: `{"a":{"b":"y"}}`,
+			want:     []ReplayDiff{{Path: "/a/b", Recorded: "x", Replayed: "y"}},
+		},
+		{
+			name:     "added and removed keys",
+			recorded: `{"a":1}`,
+			replayed: `{"b/c":2}`,
+			want: []ReplayDiff{
+				{Path: "/a", Recorded: float64(1)},
+				{Path: "/b~1c", Replayed: float64(2)},
+			},
+		},
+		{
+			name:     "longer array",
+			recorded: `[1]`,
+			replayed: `[1,2]`,
+			want:     []ReplayDiff{{Path: "/1", Replayed: float64(2)}},
+		},
+		{
+			name:     "changed type",
+			recorded: `{"a":[1]}`,
+			replayed: `{"a":{"0":1}}`,
+			want:     []ReplayDiff{{Path: "/a", Recorded: []interface{}{float64(1)}, Replayed: map[string]interface{}{"0": float64(1)}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diffs []ReplayDiff
+			diffJSON("", decodeJSON([]byte(tt.recorded)), decodeJSON([]byte(tt.replayed)), &diffs)
+			if !reflect.DeepEqual(diffs, tt.want) {
+				t.Errorf("diffJSON() = %#v, want %#v", diffs, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package swagger
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+const validateSpec = `
+openapi: 3.0.3
+paths:
+  /Svc/Create:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Item'}
+  /items/{id}:
+    put:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Item'}
+    get: {}
+  /items/default:
+    put: {}
+components:
+  schemas:
+    Item:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, minLength: 2, pattern: '^[a-z]+$'}
+        count: {type: integer, minimum: 1, maximum: 10}
+        id: {type: string, format: int64}
+        tags:
+          type: array
+          maxItems: 2
+          items: {type: string, enum: [a, b]}
+        ratio: {type: number, exclusiveMaximum: true, maximum: 1}
+        enabled: {type: boolean}
+`
+
+func TestValidateMiddleware(t *testing.T) {
+	handler, err := validateMiddleware([]byte(validateSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{name: "valid body", method: http.MethodPost, path: "/Svc/Create", body: `{"name":"ab"}`, wantStatus: http.StatusOK},
+		{name: "invalid body", method: http.MethodPost, path: "/Svc/Create", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "templated path", method: http.MethodPut, path: "/items/42", body: `{"name":1}`, wantStatus: http.StatusBadRequest},
+		{name: "literal path first", method: http.MethodPut, path: "/items/default", body: `{"name":1}`, wantStatus: http.StatusOK},
+		{name: "operation without body", method: http.MethodGet, path: "/items/42", body: `{"name":1}`, wantStatus: http.StatusOK},
+		{name: "undocumented method", method: http.MethodPost, path: "/items/42", body: `{"name":1}`, wantStatus: http.StatusOK},
+		{name: "undocumented path", method: http.MethodPost, path: "/Svc/Other", body: `{}`, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := app.NewContext(0)
+			ctx.Request.SetMethod(tt.method)
+			ctx.Request.SetRequestURI(tt.path)
+			ctx.Request.SetBodyString(tt.body)
+			handler(context.Background(), ctx)
+			if got := ctx.Response.StatusCode(); got != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", got, tt.wantStatus, ctx.Response.Body())
+			}
+		})
+	}
+}
+
+func TestValidateBody(t *testing.T) {
+	root, err := parseSpec([]byte(validateSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &requestValidator{schemas: specObject(specObject(root["components"])["schemas"])}
+	schema := map[string]interface{}{"$ref": "#/components/schemas/Item"}
+	tests := []struct {
+		name string
+		body string
+		want []Violation
+	}{
+		{name: "valid", body: `{"name":"ab","count":3,"id":"9007199254740993","tags":["a"],"ratio":0.5,"enabled":true}`},
+		{name: "empty body", body: ``, want: []Violation{{Path: "/name", Message: "is required"}}},
+		{name: "invalid JSON", body: `{`, want: []Violation{{Message: "invalid JSON: unexpected EOF"}}},
+		{name: "null is unset", body: `{"name":"ab","count":null}`},
+		{name: "not an object", body: `[]`, want: []Violation{{Message: "must be an object"}}},
+		{
+			name: "string constraints",
+			body: `{"name":"A"}`,
+			want: []Violation{
+				{Path: "/name", Message: "must be at least 2 characters long"},
+				{Path: "/name", Message: `must match the pattern "^[a-z]+$"`},
+			},
+		},
+		{name: "integer", body: `{"name":"ab","count":1.5}`, want: []Violation{{Path: "/count", Message: "must be an integer"}}},
+		{name: "integer as string", body: `{"name":"ab","count":"3"}`},
+		{name: "minimum", body: `{"name":"ab","count":0}`, want: []Violation{{Path: "/count", Message: "must be greater than or equal to 1"}}},
+		{name: "exclusive maximum", body: `{"name":"ab","ratio":1}`, want: []Violation{{Path: "/ratio", Message: "must be less than 1"}}},
+		{name: "int64 as number", body: `{"name":"ab","id":42}`},
+		{name: "int64 format", body: `{"name":"ab","id":"x"}`, want: []Violation{{Path: "/id", Message: "must be an integer of format int64"}}},
+		{
+			name: "array",
+			body: `{"name":"ab","tags":["a","c","b"]}`,
+			want: []Violation{
+				{Path: "/tags", Message: "must have at most 2 items"},
+				{Path: "/tags/1", Message: "must be one of [a b]"},
+			},
+		},
+		{name: "boolean", body: `{"name":"ab","enabled":"yes"}`, want: []Violation{{Path: "/enabled", Message: "must be a boolean"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := v.validateBody([]byte(tt.body), schema); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateBody() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,55 +16,1764 @@
 
 package tpl
 
+import "regexp"
+
+// ServicesTemplate and ServicesTemplatePb render the services of the IDL in
+// the generated server.
+const ServicesTemplate = `
+// services lists the services of the IDL with their methods.
+var services = []service{
+{{- range .Services}}
+	{name: "{{.Name}}", methods: []string{ {{- range $i, $m := .Methods}}{{if $i}}, {{end}}"{{$m}}"{{end}}}},
+{{- end}}
+}
+`
+
+const ServicesTemplatePb = `
+// services lists the services of the IDL with their file, relative to its
+// import root, and their methods.
+var services = []service{
+{{- range .Services}}
+	{name: "{{.Name}}", file: "{{.File}}", methods: []string{ {{- range $i, $m := .Methods}}{{if $i}}, {{end}}"{{$m}}"{{end}}}},
+{{- end}}
+}
+`
+
+// ImportPathsTemplate renders the import roots given to the generator, and
+// ImportPathsPattern matches them in an existing server.
+const ImportPathsTemplate = `var importPaths = []string{ {{- range $i, $p := .ImportPaths}}{{if $i}}, {{end}}"{{$p}}"{{end}}}`
+
+// IdlTemplate renders the content of the IDL files, embedded in the generated
+// server so that the proxy does not depend on the IDL at runtime.
+const IdlTemplate = `package swagger
+
+// idlFiles holds the content of the IDL files by path.
+var idlFiles = map[string]string{
+{{- range .IdlFiles}}
+	"{{.Path}}": {{.Literal}},
+{{- end}}
+}
+`
+
+// ErrorStatusesTemplate renders the HTTP status of the biz error codes of each
+// method, as documented from its error enum.
+const ErrorStatusesTemplate = `
+// errorStatuses maps the biz error codes of each method to their HTTP status.
+var errorStatuses = map[string]map[int32]int{
+{{- range .ErrorStatuses}}
+	"{{.Method}}": { {{- range $i, $c := .Codes}}{{if $i}}, {{end}}{{$c.Code}}: {{$c.Status}}{{end}}},
+{{- end}}
+}
+`
+
+// ExceptionStatusesTemplate renders the HTTP status of the exception declared
+// by each method, as documented for it.
+const ExceptionStatusesTemplate = `
+// exceptionStatuses maps the methods declaring an exception to its HTTP status.
+var exceptionStatuses = map[string]int{
+{{- range .ExceptionStatuses}}
+	"{{.Method}}": {{.Status}},
+{{- end}}
+}
+`
+
+var ImportPathsPattern = regexp.MustCompile(`var importPaths = \[\]string\{.*\}`)
+
+// LegacyServerPattern matches the generated servers that held the whole proxy
+// in the file now only holding their settings.
+var LegacyServerPattern = regexp.MustCompile(`(?m)^func StartServer\(`)
+
+// SettingsTemplateRpc and SettingsTemplateRpcPb render the settings of the
+// generated RPC servers. The file is only created once: the settings are
+// updated from the generator arguments, and the code added to it is kept.
+const SettingsTemplateRpc = `package swagger
+
+// The settings of the server, updated when it is regenerated.
+const (
+	hertzAddr = "{{.HertzAddr}}"
+	kitexAddr = "{{.KitexAddr}}"
+	idlFile   = "{{.IdlPath}}"
+	pathStyle = "{{.PathStyle}}"
+
+	// metainfoStyle is "header" to carry metainfo in X-Metainfo-* headers, or
+	// "query" to read it from the query parameters.
+	metainfoStyle = "{{.MetainfoStyle}}"
+)
+`
+
+const SettingsTemplateRpcPb = `package swagger
+
+// The settings of the server, updated when it is regenerated.
+const (
+	hertzAddr = "{{.HertzAddr}}"
+	kitexAddr = "{{.KitexAddr}}"
+	pathStyle = "{{.PathStyle}}"
+
+	// metainfoStyle is "header" to carry metainfo in X-Metainfo-* headers, or
+	// "query" to read it from the query parameters.
+	metainfoStyle = "{{.MetainfoStyle}}"
+)
+
+// importPaths lists the import roots of the IDL, relative to the working
+// directory, besides the roots of the files of the services.
+` + ImportPathsTemplate + `
+`
+
+// MockTemplate is the mock mode shared by the generated servers.
+const MockTemplate = `
+// MockOptions configures the mock mode, in which the server answers the
+// documented operations with data synthesized from their response schema.
+type MockOptions struct {
+	Enabled bool
+	// Seed makes the synthesized data deterministic: a seed always gives the
+	// same response for an operation.
+	Seed int64
+	// FixtureDir holds static responses overriding the synthesized ones, in
+	// <operationId>.json files read on every request.
+	FixtureDir string
+
+	flags *flag.FlagSet
+}
+
+// MockMode configures the mock mode. Set it before the server starts. The
+// SWAGGER_MOCK, SWAGGER_MOCK_SEED and SWAGGER_MOCK_FIXTURE_DIR environment
+// variables override it when the server starts, unless their flag was set.
+var MockMode MockOptions
+
+// loadEnv overrides the options by the environment variables.
+func (o *MockOptions) loadEnv() error {
+	return loadEnv(o.flags, []envVar{
+		{"SWAGGER_MOCK", "swagger-mock", func(v string) (err error) {
+			o.Enabled, err = strconv.ParseBool(v)
+			return err
+		}},
+		{"SWAGGER_MOCK_SEED", "swagger-mock-seed", func(v string) (err error) {
+			o.Seed, err = strconv.ParseInt(v, 10, 64)
+			return err
+		}},
+		{"SWAGGER_MOCK_FIXTURE_DIR", "swagger-mock-fixture-dir", func(v string) error {
+			o.FixtureDir = v
+			return nil
+		}},
+	})
+}
+
+// envVar is an environment variable overriding an option, unless the flag of
+// the option was set.
+type envVar struct {
+	name string
+	flag string
+	set  func(string) error
+}
+
+// loadEnv sets the options of the environment variables that are not empty.
+// fs holds the flags of the options, if they were registered.
+func loadEnv(fs *flag.FlagSet, vars []envVar) error {
+	set := map[string]bool{}
+	if fs != nil {
+		fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	}
+	for _, v := range vars {
+		value := os.Getenv(v.name)
+		if value == "" || set[v.flag] {
+			continue
+		}
+		if err := v.set(value); err != nil {
+			return fmt.Errorf("invalid %s: %w", v.name, err)
+		}
+	}
+	return nil
+}
+
+// RegisterFlags registers the flags overriding the options in fs, e.g. in
+// flag.CommandLine before flag.Parse is called.
+func (o *MockOptions) RegisterFlags(fs *flag.FlagSet) {
+	o.flags = fs
+	fs.BoolVar(&o.Enabled, "swagger-mock", o.Enabled, "answer the documented operations with mock data")
+	fs.Int64Var(&o.Seed, "swagger-mock-seed", o.Seed, "seed of the mock data")
+	fs.StringVar(&o.FixtureDir, "swagger-mock-fixture-dir", o.FixtureDir, "directory of the <operationId>.json responses overriding the mock data")
+}
+
+// mockMiddleware answers the operations documented by spec with mock data.
+func mockMiddleware(spec []byte) (app.HandlerFunc, error) {
+	d, err := newMockDocument(spec)
+	if err != nil {
+		return nil, err
+	}
+	return func(c context.Context, ctx *app.RequestContext) {
+		if d.serve(ctx) {
+			ctx.Abort()
+			return
+		}
+		ctx.Next(c)
+	}, nil
+}
+
+// mockDocument answers the operations of an OpenAPI document with mock data.
+type mockDocument struct {
+	operations []*mockOperation
+	schemas    map[string]interface{}
+}
+
+type mockOperation struct {
+	*specOperation
+	id     string
+	status int
+	schema interface{}
+}
+
+func newMockDocument(spec []byte) (*mockDocument, error) {
+	root, err := parseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	d := &mockDocument{schemas: specObject(specObject(root["components"])["schemas"])}
+	for _, op := range specOperations(root) {
+		id, _ := op.spec["operationId"].(string)
+		status, schema := mockResponse(specObject(op.spec["responses"]))
+		d.operations = append(d.operations, &mockOperation{
+			specOperation: op,
+			id:            id,
+			status:        status,
+			schema:        schema,
+		})
+	}
+	return d, nil
+}
+
+// mockResponse returns the status and the JSON schema of the first success
+// response.
+func mockResponse(responses map[string]interface{}) (int, interface{}) {
+	var codes []int
+	for code := range responses {
+		if c, err := strconv.Atoi(code); err == nil && c >= 200 && c < 300 {
+			codes = append(codes, c)
+		}
+	}
+	if len(codes) == 0 {
+		return http.StatusOK, nil
+	}
+	sort.Ints(codes)
+	content := specObject(specObject(responses[strconv.Itoa(codes[0])])["content"])
+	return codes[0], specObject(content["application/json"])["schema"]
+}
+
+// serve answers the request with the fixture or the mock data of its
+// operation, reporting whether the operation is documented.
+func (d *mockDocument) serve(ctx *app.RequestContext) bool {
+	method, path := string(ctx.Method()), string(ctx.Path())
+	var op *mockOperation
+	for _, o := range d.operations {
+		if o.matches(method, path) {
+			op = o
+			break
+		}
+	}
+	if op == nil {
+		return false
+	}
+
+	if MockMode.FixtureDir != "" && op.id != "" {
+		fixture, err := os.ReadFile(filepath.Join(MockMode.FixtureDir, op.id+".json"))
+		if err == nil {
+			ctx.Data(op.status, "application/json", fixture)
+			return true
+		}
+		if !os.IsNotExist(err) {
+			hlog.Errorf("Failed to read fixture: %v", err)
+		}
+	}
+	if op.schema == nil {
+		ctx.SetStatusCode(op.status)
+		return true
+	}
+
+	// Each operation draws from its own source, so that its data does not
+	// depend on the requests made before.
+	h := fnv.New64a()
+	h.Write([]byte(op.method + " " + op.path.String()))
+	rnd := rand.New(rand.NewSource(MockMode.Seed ^ int64(h.Sum64())))
+	ctx.JSON(op.status, d.value(op.schema, rnd, 0))
+	return true
+}
+
+// mockMaxDepth bounds the nesting of the mock data of recursive schemas.
+const mockMaxDepth = 8
+
+// value synthesizes a value of schema from its example, default or enum, or
+// else from its type, format and constraints.
+func (d *mockDocument) value(schema interface{}, rnd *rand.Rand, depth int) interface{} {
+	s := specObject(schema)
+	if s == nil || depth > mockMaxDepth {
+		return nil
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		return d.value(d.schemas[strings.TrimPrefix(ref, "#/components/schemas/")], rnd, depth+1)
+	}
+	if v, ok := s["example"]; ok {
+		return v
+	}
+	if v, ok := s["default"]; ok {
+		return v
+	}
+	if values, ok := s["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[rnd.Intn(len(values))]
+	}
+
+	// The schema is combined with all of its allOf schemas and with one of its
+	// oneOf or anyOf schemas: the objects they describe are merged into the
+	// object of the schema itself.
+	var branches []interface{}
+	if schemas, ok := s["allOf"].([]interface{}); ok {
+		branches = append(branches, schemas...)
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if schemas, ok := s[key].([]interface{}); ok && len(schemas) > 0 {
+			branches = append(branches, schemas[rnd.Intn(len(schemas))])
+		}
+	}
+	value := d.typed(s, rnd, depth)
+	if len(branches) == 0 {
+		return value
+	}
+	obj, _ := value.(map[string]interface{})
+	for _, branch := range branches {
+		v := d.value(branch, rnd, depth+1)
+		sub, ok := v.(map[string]interface{})
+		if !ok {
+			if value == nil {
+				value = v
+			}
+			continue
+		}
+		if obj == nil {
+			obj = map[string]interface{}{}
+		}
+		for k, v := range sub {
+			obj[k] = v
+		}
+	}
+	if obj != nil {
+		return obj
+	}
+	return value
+}
+
+// typed synthesizes a value of schema from its type, format and constraints.
+func (d *mockDocument) typed(s map[string]interface{}, rnd *rand.Rand, depth int) interface{} {
+	switch s["type"] {
+	case "object":
+		properties := specObject(s["properties"])
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		obj := make(map[string]interface{}, len(names))
+		for _, name := range names {
+			obj[name] = d.value(properties[name], rnd, depth+1)
+		}
+		return obj
+	case "array":
+		items := make([]interface{}, mockLength(s, "minItems", "maxItems", 1, 3, rnd))
+		for i := range items {
+			items[i] = d.value(s["items"], rnd, depth+1)
+		}
+		return items
+	case "string":
+		return mockString(s, rnd)
+	case "integer":
+		lo, hi := mockRange(s, 1)
+		return mockInteger(lo, hi, rnd)
+	case "number":
+		lo, hi := mockRange(s, 0)
+		return lo + rnd.Float64()*(hi-lo)
+	case "boolean":
+		return rnd.Intn(2) == 1
+	}
+	return nil
+}
+
+// mockRange returns the bounds of a number schema, moved by step inside the
+// exclusive ones.
+func mockRange(s map[string]interface{}, step float64) (float64, float64) {
+	lo, hasLo := specNumber(s["minimum"])
+	hi, hasHi := specNumber(s["maximum"])
+	switch {
+	case hasLo && !hasHi:
+		hi = lo + 100
+	case !hasLo && hasHi:
+		lo = hi - 100
+	case !hasLo && !hasHi:
+		lo, hi = 0, 100
+	}
+	if exclusive, _ := s["exclusiveMinimum"].(bool); exclusive {
+		lo += step
+	}
+	if exclusive, _ := s["exclusiveMaximum"].(bool); exclusive {
+		hi -= step
+	}
+	if step == 1 {
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// mockInteger returns an integer within [lo, hi], both clamped to the int64
+// range. The width of the range is computed in uint64, as it may exceed
+// math.MaxInt64.
+func mockInteger(lo, hi float64, rnd *rand.Rand) int64 {
+	first, last := clampInt64(lo), clampInt64(hi)
+	if last <= first {
+		return first
+	}
+	n := uint64(last) - uint64(first)
+	if n < math.MaxInt64 {
+		return first + rnd.Int63n(int64(n)+1)
+	}
+	for {
+		// More than half of the values are at most n, or all of them if the
+		// range is the whole int64 range.
+		if v := rnd.Uint64(); n == math.MaxUint64 || v <= n {
+			return first + int64(v)
+		}
+	}
+}
+
+// clampInt64 converts f to the nearest int64.
+func clampInt64(f float64) int64 {
+	switch {
+	case f >= math.MaxInt64:
+		return math.MaxInt64
+	case f <= math.MinInt64:
+		return math.MinInt64
+	}
+	return int64(f)
+}
+
+// mockLength returns a length within the bounds named by minKey and maxKey,
+// defaulting to min and max.
+func mockLength(s map[string]interface{}, minKey, maxKey string, min, max int, rnd *rand.Rand) int {
+	if v, ok := specNumber(s[minKey]); ok {
+		min = int(v)
+		if max < min {
+			max = min
+		}
+	}
+	if v, ok := specNumber(s[maxKey]); ok {
+		max = int(v)
+		if min > max {
+			min = max
+		}
+	}
+	return min + rnd.Intn(max-min+1)
+}
+
+func mockString(s map[string]interface{}, rnd *rand.Rand) string {
+	switch s["format"] {
+	case "date-time":
+		return mockTime(rnd).Format(time.RFC3339)
+	case "date":
+		return mockTime(rnd).Format("2006-01-02")
+	case "time":
+		return mockTime(rnd).Format("15:04:05")
+	case "uuid":
+		b := make([]byte, 16)
+		rnd.Read(b)
+		b[6], b[8] = b[6]&0x0f|0x40, b[8]&0x3f|0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "email":
+		return mockWord(8, rnd) + "@example.com"
+	case "uri", "url":
+		return "https://example.com/" + mockWord(8, rnd)
+	case "hostname":
+		return mockWord(8, rnd) + ".example.com"
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", rnd.Intn(254)+1)
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", rnd.Intn(0xffff)+1)
+	case "byte", "binary":
+		b := make([]byte, mockLength(s, "minLength", "maxLength", 4, 12, rnd))
+		rnd.Read(b)
+		return base64.StdEncoding.EncodeToString(b)
+	case "int64", "uint64", "fixed64", "sfixed64", "sint64":
+		return strconv.FormatInt(rnd.Int63n(1000000), 10)
+	}
+	return mockWord(mockLength(s, "minLength", "maxLength", 5, 10, rnd), rnd)
+}
+
+func mockTime(rnd *rand.Rand) time.Time {
+	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(rnd.Int63n(365*24*3600)) * time.Second)
+}
+
+func mockWord(n int, rnd *rand.Rand) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + rnd.Intn(26))
+	}
+	return string(b)
+}
+
+func specNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// specOperation is an operation of an OpenAPI document.
+type specOperation struct {
+	method string         // Upper-case HTTP method.
+	path   *regexp.Regexp // Request paths of the operation.
+	params int            // Number of parameters of the path template.
+	spec   map[string]interface{}
+}
+
+var specPathParam = regexp.MustCompile("\\{[^/}]+\\}")
+
+// specOperations returns the operations of the document, the literal paths
+// before the templated ones they match.
+func specOperations(root map[string]interface{}) []*specOperation {
+	var operations []*specOperation
+	for path, item := range specObject(root["paths"]) {
+		parts := specPathParam.Split(path, -1)
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		pattern := regexp.MustCompile("^" + strings.Join(parts, "[^/]+") + "$")
+		for method, operation := range specObject(item) {
+			switch method {
+			case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+			default:
+				continue
+			}
+			operations = append(operations, &specOperation{
+				method: strings.ToUpper(method),
+				path:   pattern,
+				params: len(parts) - 1,
+				spec:   specObject(operation),
+			})
+		}
+	}
+	sort.SliceStable(operations, func(i, j int) bool {
+		if operations[i].params != operations[j].params {
+			return operations[i].params < operations[j].params
+		}
+		if operations[i].path.String() != operations[j].path.String() {
+			return operations[i].path.String() < operations[j].path.String()
+		}
+		return operations[i].method < operations[j].method
+	})
+	return operations
+}
+
+// matches reports whether the operation answers the requests of method on
+// path.
+func (op *specOperation) matches(method, path string) bool {
+	return op.method == method && op.path.MatchString(path)
+}
+
+// parseSpec parses an OpenAPI document into JSON values.
+func parseSpec(spec []byte) (map[string]interface{}, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, err
+	}
+	return specObject(specJSON(doc)), nil
+}
+
+// specObject returns v as a JSON object, or nil.
+func specObject(v interface{}) map[string]interface{} {
+	obj, _ := v.(map[string]interface{})
+	return obj
+}
+
+// specJSON converts the YAML maps of v, whose keys may not be strings, to JSON
+// objects.
+func specJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = specJSON(e)
+		}
+		return v
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			obj[fmt.Sprint(k)] = specJSON(e)
+		}
+		return obj
+	case []interface{}:
+		for i, e := range v {
+			v[i] = specJSON(e)
+		}
+		return v
+	}
+	return v
+}
+`
+
+// RecordTemplate records the calls made through the proxy of the generated
+// RPC servers and replays them.
+const RecordTemplate = `
+// RecordFile is the JSONL file the calls made through the proxy are appended
+// to, if set, e.g. by the SWAGGER_RECORD_FILE environment variable when the
+// server starts. Set it before the server starts.
+var RecordFile string
+
+// ReplayEndpoint registers the /replay endpoint, which re-runs the posted calls
+// against the Kitex service. As the endpoint is not authenticated, it is off
+// unless the SWAGGER_REPLAY environment variable is true when the server
+// starts. Set it before the server starts.
+var ReplayEndpoint bool
+
+// CallRecord is a call made through the proxy, recorded as a line of RecordFile.
+type CallRecord struct {
+	Time       time.Time         ` + "`" + `json:"time"` + "`" + `
+	Service    string            ` + "`" + `json:"service"` + "`" + `
+	Method     string            ` + "`" + `json:"method"` + "`" + `
+	Metainfo   map[string]string ` + "`" + `json:"metainfo,omitempty"` + "`" + `
+	Persistent map[string]string ` + "`" + `json:"persistent_metainfo,omitempty"` + "`" + `
+	Request    json.RawMessage   ` + "`" + `json:"request"` + "`" + `
+	Response   json.RawMessage   ` + "`" + `json:"response,omitempty"` + "`" + `
+	Backward   map[string]string ` + "`" + `json:"backward_metainfo,omitempty"` + "`" + `
+	Error      string            ` + "`" + `json:"error,omitempty"` + "`" + `
+	LatencyMS  float64           ` + "`" + `json:"latency_ms"` + "`" + `
+}
+
+var recordMu sync.Mutex
+
+// recordCall appends the call to RecordFile, if set.
+func recordCall(c context.Context, service, method, request string, response interface{}, err error, latency time.Duration) {
+	if RecordFile == "" {
+		return
+	}
+	rec := &CallRecord{
+		Time:       time.Now(),
+		Service:    service,
+		Method:     method,
+		Metainfo:   metainfo.GetAllValues(c),
+		Persistent: metainfo.GetAllPersistentValues(c),
+		Request:    rawJSON(request),
+		Backward:   metainfo.RecvAllBackwardValues(c),
+		LatencyMS:  float64(latency.Microseconds()) / 1000,
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	} else if s, ok := response.(string); ok {
+		rec.Response = rawJSON(s)
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		hlog.Errorf("Failed to marshal call record: %v", err)
+		return
+	}
+
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	f, err := os.OpenFile(RecordFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		hlog.Errorf("Failed to open record file: %v", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		hlog.Errorf("Failed to record call: %v", err)
+	}
+}
+
+// rawJSON returns s as JSON, quoted if it is not valid JSON.
+func rawJSON(s string) json.RawMessage {
+	if json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	quoted, _ := json.Marshal(s)
+	return quoted
+}
+
+// ReplayResult is the outcome of replaying a recorded call.
+type ReplayResult struct {
+	Service string       ` + "`" + `json:"service"` + "`" + `
+	Method  string       ` + "`" + `json:"method"` + "`" + `
+	Error   string       ` + "`" + `json:"error,omitempty"` + "`" + `
+	Diffs   []ReplayDiff ` + "`" + `json:"diffs,omitempty"` + "`" + `
+}
+
+// ReplayDiff is a value of the replayed response that differs from the
+// recorded one, at the JSON pointer Path.
+type ReplayDiff struct {
+	Path     string      ` + "`" + `json:"path"` + "`" + `
+	Recorded interface{} ` + "`" + `json:"recorded"` + "`" + `
+	Replayed interface{} ` + "`" + `json:"replayed"` + "`" + `
+}
+
+// Replay re-runs the calls of a recorded session against the Kitex service
+// and diffs the responses with the recorded ones.
+func Replay(session io.Reader) ([]*ReplayResult, error) {
+	if err := ProxyClientOptions.loadEnv(); err != nil {
+		return nil, err
+	}
+	clients, err := initializeGenericClients()
+	if err != nil {
+		return nil, err
+	}
+	return replay(context.Background(), clients, session)
+}
+
+// replayHandler replays the session posted as JSONL and answers the results.
+func replayHandler(clients map[string]genericclient.Client) app.HandlerFunc {
+	return func(c context.Context, ctx *app.RequestContext) {
+		results, err := replay(c, clients, bytes.NewReader(ctx.Request.Body()))
+		if err != nil {
+			handleError(ctx, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ctx.JSON(http.StatusOK, results)
+	}
+}
+
+func replay(c context.Context, clients map[string]genericclient.Client, session io.Reader) ([]*ReplayResult, error) {
+	var results []*ReplayResult
+	scanner := bufio.NewScanner(session)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var rec CallRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		results = append(results, replayCall(c, clients, &rec))
+	}
+	return results, scanner.Err()
+}
+
+func replayCall(c context.Context, clients map[string]genericclient.Client, rec *CallRecord) *ReplayResult {
+	result := &ReplayResult{Service: rec.Service, Method: rec.Method}
+	cli, ok := clients[rec.Service]
+	if !ok {
+		result.Error = "service not found"
+		return result
+	}
+	for k, v := range rec.Metainfo {
+		c = metainfo.WithValue(c, k, v)
+	}
+	for k, v := range rec.Persistent {
+		c = metainfo.WithPersistentValue(c, k, v)
+	}
+
+	response, err := cli.GenericCall(c, rec.Method, string(rec.Request))
+	if err != nil {
+		result.Error = err.Error()
+		if rec.Error == "" {
+			result.Diffs = append(result.Diffs, ReplayDiff{Recorded: decodeJSON(rec.Response), Replayed: err.Error()})
+		}
+		return result
+	}
+	replayed := decodeJSON([]byte(response.(string)))
+	if rec.Error != "" {
+		result.Diffs = append(result.Diffs, ReplayDiff{Recorded: rec.Error, Replayed: replayed})
+		return result
+	}
+	diffJSON("", decodeJSON(rec.Response), replayed, &result.Diffs)
+	return result
+}
+
+func decodeJSON(data []byte) interface{} {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return string(data)
+	}
+	return v
+}
+
+// diffJSON appends the differences between the recorded and replayed values
+// below the JSON pointer path to diffs.
+func diffJSON(path string, recorded, replayed interface{}, diffs *[]ReplayDiff) {
+	switch r := recorded.(type) {
+	case map[string]interface{}:
+		if p, ok := replayed.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(r)+len(p))
+			for k := range r {
+				keys = append(keys, k)
+			}
+			for k := range p {
+				if _, ok := r[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				diffJSON(path+"/"+escapePointer(k), r[k], p[k], diffs)
+			}
+			return
+		}
+	case []interface{}:
+		if p, ok := replayed.([]interface{}); ok {
+			for i := 0; i < len(r) || i < len(p); i++ {
+				var ri, pi interface{}
+				if i < len(r) {
+					ri = r[i]
+				}
+				if i < len(p) {
+					pi = p[i]
+				}
+				diffJSON(path+"/"+strconv.Itoa(i), ri, pi, diffs)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(recorded, replayed) {
+		*diffs = append(*diffs, ReplayDiff{Path: path, Recorded: recorded, Replayed: replayed})
+	}
+}
+`
+
+// ValidateTemplate validates the request bodies of the proxy of the generated
+// RPC servers against their schema.
+const ValidateTemplate = `
+// ValidateRequests makes the proxy check the request bodies against their
+// schema in openapi.yaml before calling the service, answering 400 with the
+// violations. It is on unless the SWAGGER_VALIDATE environment variable is
+// false when the server starts. Set it before the server starts.
+var ValidateRequests = true
+
+// Violation is a part of a request body that does not match its schema, at
+// the JSON pointer Path.
+type Violation struct {
+	Path    string ` + "`" + `json:"path"` + "`" + `
+	Message string ` + "`" + `json:"message"` + "`" + `
+}
+
+// requestValidator checks the request bodies of the operations against their
+// schema.
+type requestValidator struct {
+	operations []*specOperation
+	requests   map[*specOperation]interface{} // JSON request body schema by operation.
+	schemas    map[string]interface{}
+}
+
+// validateMiddleware answers the requests whose body does not match the
+// schema documented by spec with 400 and the violations. The request is
+// matched to its operation by method and path template, as in mock mode.
+func validateMiddleware(spec []byte) (app.HandlerFunc, error) {
+	root, err := parseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	v := &requestValidator{
+		operations: specOperations(root),
+		requests:   map[*specOperation]interface{}{},
+		schemas:    specObject(specObject(root["components"])["schemas"]),
+	}
+	for _, op := range v.operations {
+		content := specObject(specObject(op.spec["requestBody"])["content"])
+		if schema, ok := specObject(content["application/json"])["schema"]; ok {
+			v.requests[op] = schema
+		}
+	}
+
+	return func(c context.Context, ctx *app.RequestContext) {
+		schema, ok := v.requestSchema(string(ctx.Method()), string(ctx.Path()))
+		if !ok {
+			ctx.Next(c)
+			return
+		}
+		if violations := v.validateBody(ctx.Request.Body(), schema); len(violations) > 0 {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, map[string]interface{}{
+				"error":      "Request body does not match its schema",
+				"violations": violations,
+			})
+			return
+		}
+		ctx.Next(c)
+	}, nil
+}
+
+// requestSchema returns the JSON request body schema of the operation
+// answering the requests of method on path, if any.
+func (v *requestValidator) requestSchema(method, path string) (interface{}, bool) {
+	for _, op := range v.operations {
+		if op.matches(method, path) {
+			schema, ok := v.requests[op]
+			return schema, ok
+		}
+	}
+	return nil, false
+}
+
+func (v *requestValidator) validateBody(body []byte, schema interface{}) []Violation {
+	if len(bytes.TrimSpace(body)) == 0 {
+		body = []byte("{}")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		violation := Violation{Path: "", Message: "invalid JSON: " + err.Error()}
+		return []Violation{violation}
+	}
+	var violations []Violation
+	v.validate("", schema, value, &violations, 0)
+	return violations
+}
+
+// validateMaxDepth bounds the references followed without reaching a value.
+const validateMaxDepth = 64
+
+// validate appends the violations of the schema by the value at the JSON
+// pointer path to violations. Null values are accepted as unset.
+func (v *requestValidator) validate(path string, schema, value interface{}, violations *[]Violation, depth int) {
+	s := specObject(schema)
+	if s == nil || value == nil || depth > validateMaxDepth {
+		return
+	}
+	violate := func(format string, args ...interface{}) {
+		*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		v.validate(path, v.schemas[strings.TrimPrefix(ref, "#/components/schemas/")], value, violations, depth+1)
+		return
+	}
+	if schemas, ok := s["allOf"].([]interface{}); ok {
+		for _, sub := range schemas {
+			v.validate(path, sub, value, violations, depth+1)
+		}
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if schemas, ok := s[key].([]interface{}); ok && len(schemas) > 0 && !v.matchesAny(path, schemas, value, depth) {
+			violate("does not match any of the %s schemas", key)
+		}
+	}
+
+	switch s["type"] {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			violate("must be an object")
+			return
+		}
+		if required, ok := s["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := obj[fmt.Sprint(name)]; !ok {
+					*violations = append(*violations, Violation{Path: path + "/" + escapePointer(fmt.Sprint(name)), Message: "is required"})
+				}
+			}
+		}
+		properties := specObject(s["properties"])
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := properties[name]
+			if !ok {
+				property = s["additionalProperties"]
+			}
+			v.validate(path+"/"+escapePointer(name), property, obj[name], violations, depth+1)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			violate("must be an array")
+			return
+		}
+		if min, ok := specNumber(s["minItems"]); ok && float64(len(items)) < min {
+			violate("must have at least %v items", min)
+		}
+		if max, ok := specNumber(s["maxItems"]); ok && float64(len(items)) > max {
+			violate("must have at most %v items", max)
+		}
+		for i, item := range items {
+			v.validate(path+"/"+strconv.Itoa(i), s["items"], item, violations, depth+1)
+		}
+	case "string":
+		str, ok := value.(string)
+		if n, isNumber := value.(json.Number); isNumber && isIntegerFormat(s["format"]) {
+			str, ok = n.String(), true
+		}
+		if !ok {
+			violate("must be a string")
+			return
+		}
+		if isIntegerFormat(s["format"]) {
+			if _, err := strconv.ParseInt(str, 10, 64); err != nil {
+				if _, err := strconv.ParseUint(str, 10, 64); err != nil {
+					violate("must be an integer of format %v", s["format"])
+				}
+			}
+		}
+		length := float64(len([]rune(str)))
+		if min, ok := specNumber(s["minLength"]); ok && length < min {
+			violate("must be at least %v characters long", min)
+		}
+		if max, ok := specNumber(s["maxLength"]); ok && length > max {
+			violate("must be at most %v characters long", max)
+		}
+		if pattern, ok := s["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(str) {
+				violate("must match the pattern %q", pattern)
+			}
+		}
+	case "integer", "number":
+		kind := "a number"
+		if s["type"] == "integer" {
+			kind = "an integer"
+		}
+		n, ok := value.(json.Number)
+		if str, isString := value.(string); isString && s["type"] == "integer" {
+			// Integers may be sent as strings, to keep the precision of int64.
+			n, ok = json.Number(str), true
+		}
+		f, err := n.Float64()
+		if !ok || err != nil || s["type"] == "integer" && f != math.Trunc(f) {
+			violate("must be %s", kind)
+			return
+		}
+		if min, ok := specNumber(s["minimum"]); ok {
+			if exclusive, _ := s["exclusiveMinimum"].(bool); f < min || exclusive && f == min {
+				violate("must be greater than %s%v", orEqual(!exclusive), min)
+			}
+		}
+		if max, ok := specNumber(s["maximum"]); ok {
+			if exclusive, _ := s["exclusiveMaximum"].(bool); f > max || exclusive && f == max {
+				violate("must be less than %s%v", orEqual(!exclusive), max)
+			}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			violate("must be a boolean")
+			return
+		}
+	}
+
+	if values, ok := s["enum"].([]interface{}); ok && len(values) > 0 {
+		for _, e := range values {
+			if fmt.Sprint(e) == fmt.Sprint(value) {
+				return
+			}
+		}
+		violate("must be one of %v", values)
+	}
+}
+
+// matchesAny reports whether the value matches one of the schemas.
+func (v *requestValidator) matchesAny(path string, schemas []interface{}, value interface{}, depth int) bool {
+	for _, sub := range schemas {
+		var violations []Violation
+		v.validate(path, sub, value, &violations, depth+1)
+		if len(violations) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func isIntegerFormat(format interface{}) bool {
+	return format == "int64" || format == "uint64"
+}
+
+func orEqual(inclusive bool) string {
+	if inclusive {
+		return "or equal to "
+	}
+	return ""
+}
+
+// escapePointer escapes a name as a JSON pointer token.
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+`
+
 const ServerTemplateHttp = `package swagger
 
-import (
-	"context"
-	_ "embed"
+import (
+	"context"
+	_ "embed"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/hertz-contrib/cors"
+	"github.com/hertz-contrib/swagger"
+	swaggerFiles "github.com/swaggo/files"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var openapiYAML []byte
+
+// BindSwagger serves the Swagger UI and, in mock mode, answers the documented
+// operations registered after it, or having no route, with mock data.
+func BindSwagger(h *server.Hertz) {
+	h.Use(cors.Default())
+	if err := MockMode.loadEnv(); err != nil {
+		hlog.Fatal("Invalid mock options:", err)
+	}
+	if MockMode.Enabled {
+		mock, err := mockMiddleware(openapiYAML)
+		if err != nil {
+			hlog.Fatal("Failed to parse openapi.yaml:", err)
+		}
+		h.Use(mock)
+	}
+
+	h.GET("/swagger/*any", swagger.WrapHandler(
+		swaggerFiles.Handler,
+		swagger.URL("/openapi.yaml"),
+	))
+
+	h.GET("/openapi.yaml", func(c context.Context, ctx *app.RequestContext) {
+		ctx.Header("Content-Type", "application/x-yaml")
+		ctx.Write(openapiYAML)
+	})
+}
+` + MockTemplate
+
+// ProxyTemplate is the proxy shared by the generated RPC servers, calling the
+// Kitex service through the generic clients that each server sets up for its
+// IDL.
+const ProxyTemplate = `
+type MixTransHandlerFactory struct {
+	OriginFactory remote.ServerTransHandlerFactory
+}
+
+type transHandler struct {
+	remote.ServerTransHandler
+}
+
+func (t *transHandler) SetInvokeHandleFunc(inkHdlFunc endpoint.Endpoint) {
+	t.ServerTransHandler.(remote.InvokeHandleFuncSetter).SetInvokeHandleFunc(inkHdlFunc)
+}
+
+func (m MixTransHandlerFactory) NewTransHandler(opt *remote.ServerOption) (remote.ServerTransHandler, error) {
+
+	if hertzEngine == nil {
+		if err := StartServer(); err != nil {
+			return nil, err
+		}
+	}
+
+	var kitexOrigin remote.ServerTransHandler
+	var err error
+
+	if m.OriginFactory != nil {
+		kitexOrigin, err = m.OriginFactory.NewTransHandler(opt)
+	} else {
+		kitexOrigin, err = detection.NewSvrTransHandlerFactory(netpoll.NewSvrTransHandlerFactory(), nphttp2.NewSvrTransHandlerFactory()).NewTransHandler(opt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transHandler{ServerTransHandler: kitexOrigin}, nil
+}
+
+func (t *transHandler) OnRead(ctx context.Context, conn net.Conn) error {
+	c, ok := conn.(network.Conn)
+	if ok {
+		pre, _ := c.Peek(4)
+		if httpReg.Match(pre) {
+			klog.Info("using Hertz to process request")
+			err := hertzEngine.Serve(ctx, c)
+			if err != nil {
+				err = errors.New(fmt.Sprintf("HERTZ: %s", err.Error()))
+			}
+			return err
+		}
+	}
+
+	return t.ServerTransHandler.OnRead(ctx, conn)
+}
+
+func StartServer() error {
+	h, err := newServer(openapiYAML)
+	if err != nil {
+		return err
+	}
+
+	hlog.Info("Swagger UI is available at: http://" + kitexAddr + "/swagger/index.html")
+	if err = h.Engine.Init(); err != nil {
+		return err
+	}
+
+	hertzEngine = h.Engine
+	return nil
+}
+
+// RunStandalone serves the Swagger UI and the proxy on hertzAddr, calling the
+// Kitex server at kitexAddr instead of sharing its port. It blocks until the
+// server stops.
+func RunStandalone() error {
+	spec, err := standaloneYAML(openapiYAML)
+	if err != nil {
+		return fmt.Errorf("parse openapi.yaml: %w", err)
+	}
+	h, err := newServer(spec, server.WithHostPorts(hertzAddr))
+	if err != nil {
+		return err
+	}
+
+	hlog.Info("Swagger UI is available at: http://" + hertzAddr + "/swagger/index.html")
+	h.Spin()
+	return nil
+}
+
+// newServer returns the server of the Swagger UI and the proxy, configured by
+// the options and the environment variables overriding them.
+func newServer(spec []byte, opts ...config.Option) (*server.Hertz, error) {
+	if err := loadServerEnv(); err != nil {
+		return nil, err
+	}
+
+	h := server.Default(opts...)
+	h.Use(cors.Default())
+	if ValidateRequests {
+		validate, err := validateMiddleware(spec)
+		if err != nil {
+			return nil, fmt.Errorf("parse openapi.yaml: %w", err)
+		}
+		h.Use(validate)
+	}
+	if MockMode.Enabled {
+		mock, err := mockMiddleware(spec)
+		if err != nil {
+			return nil, fmt.Errorf("parse openapi.yaml: %w", err)
+		}
+		h.Use(mock)
+	}
+
+	clients, err := initializeGenericClients()
+	if err != nil {
+		return nil, err
+	}
+	setupSwaggerRoutes(h, spec)
+	setupProxyRoutes(h, clients)
+	if ReplayEndpoint {
+		h.POST("/replay", replayHandler(clients))
+	}
+	return h, nil
+}
+
+// loadServerEnv overrides the options of the server by the environment
+// variables.
+func loadServerEnv() error {
+	if err := MockMode.loadEnv(); err != nil {
+		return err
+	}
+	if err := ProxyClientOptions.loadEnv(); err != nil {
+		return err
+	}
+	return loadEnv(nil, []envVar{
+		{"SWAGGER_RECORD_FILE", "", func(v string) error {
+			RecordFile = v
+			return nil
+		}},
+		{"SWAGGER_REPLAY", "", func(v string) (err error) {
+			ReplayEndpoint, err = strconv.ParseBool(v)
+			return err
+		}},
+		{"SWAGGER_VALIDATE", "", func(v string) (err error) {
+			ValidateRequests, err = strconv.ParseBool(v)
+			return err
+		}},
+	})
+}
+
+// standaloneYAML removes the servers of the document, of its paths and of
+// their operations, so that Swagger UI calls the standalone server.
+func standaloneYAML(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return data, nil
+	}
+
+	root := doc.Content[0]
+	removeKey(root, "servers")
+	if paths := mappingValue(root, "paths"); paths != nil {
+		for i := 1; i < len(paths.Content); i += 2 {
+			pathItem := paths.Content[i]
+			removeKey(pathItem, "servers")
+			for j := 1; j < len(pathItem.Content); j += 2 {
+				removeKey(pathItem.Content[j], "servers")
+			}
+		}
+	}
+	return yaml.Marshal(&doc)
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func removeKey(node *yaml.Node, key string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// ClientOptions configures the generic clients the proxy calls the Kitex
+// service with.
+type ClientOptions struct {
+	// Transport is the transport protocol of the calls, one of
+	// supportedTransports. Metainfo is only carried by "ttheader" and
+	// "ttheader_framed".
+	Transport string
+	// RPCTimeout and ConnectTimeout limit the calls and the connections to
+	// the service, if positive.
+	RPCTimeout     time.Duration
+	ConnectTimeout time.Duration
+	// PayloadCodec converts the JSON bodies of the calls, one of
+	// payloadCodecs.
+	PayloadCodec string
+	// HostPorts are the addresses of the service.
+	HostPorts []string
+	// Options are added after the options built from the fields above, e.g.
+	// for retries or connection pools.
+	Options []client.Option
+
+	flags *flag.FlagSet
+}
+
+// ProxyClientOptions are the options of the generic clients of the proxy. Set
+// them before the server starts. The SWAGGER_KITEX_TRANSPORT,
+// SWAGGER_KITEX_RPC_TIMEOUT, SWAGGER_KITEX_CONNECT_TIMEOUT,
+// SWAGGER_KITEX_PAYLOAD_CODEC and SWAGGER_KITEX_HOST_PORTS (comma-separated)
+// environment variables override them when the server starts, unless their
+// flag was set.
+var ProxyClientOptions = ClientOptions{Transport: supportedTransports[0], PayloadCodec: payloadCodecs[0], HostPorts: []string{kitexAddr}}
+
+// loadEnv overrides the options by the environment variables.
+func (o *ClientOptions) loadEnv() error {
+	return loadEnv(o.flags, []envVar{
+		{"SWAGGER_KITEX_TRANSPORT", "kitex-transport", func(v string) error {
+			o.Transport = v
+			return checkOption("transport", v, supportedTransports)
+		}},
+		{"SWAGGER_KITEX_RPC_TIMEOUT", "kitex-rpc-timeout", func(v string) (err error) {
+			o.RPCTimeout, err = time.ParseDuration(v)
+			return err
+		}},
+		{"SWAGGER_KITEX_CONNECT_TIMEOUT", "kitex-connect-timeout", func(v string) (err error) {
+			o.ConnectTimeout, err = time.ParseDuration(v)
+			return err
+		}},
+		{"SWAGGER_KITEX_PAYLOAD_CODEC", "kitex-payload-codec", func(v string) error {
+			o.PayloadCodec = v
+			return checkOption("payload codec", v, payloadCodecs)
+		}},
+		{"SWAGGER_KITEX_HOST_PORTS", "kitex-host-ports", func(v string) error {
+			o.HostPorts = splitList(v)
+			return nil
+		}},
+	})
+}
+
+// RegisterFlags registers the flags overriding the options in fs, e.g. in
+// flag.CommandLine before flag.Parse is called.
+func (o *ClientOptions) RegisterFlags(fs *flag.FlagSet) {
+	o.flags = fs
+	fs.Func("kitex-transport", "transport protocol of the proxy: "+strings.Join(supportedTransports, ", ")+" (default "+o.Transport+")", func(v string) error {
+		o.Transport = v
+		return checkOption("transport", v, supportedTransports)
+	})
+	fs.DurationVar(&o.RPCTimeout, "kitex-rpc-timeout", o.RPCTimeout, "timeout of the calls of the proxy")
+	fs.DurationVar(&o.ConnectTimeout, "kitex-connect-timeout", o.ConnectTimeout, "timeout of the connections of the proxy")
+	fs.Func("kitex-payload-codec", "payload codec of the proxy: "+strings.Join(payloadCodecs, ", ")+" (default "+o.PayloadCodec+")", func(v string) error {
+		o.PayloadCodec = v
+		return checkOption("payload codec", v, payloadCodecs)
+	})
+	fs.Func("kitex-host-ports", "comma-separated addresses of the Kitex service (default "+strings.Join(o.HostPorts, ",")+")", func(v string) error {
+		o.HostPorts = splitList(v)
+		return nil
+	})
+}
+
+// validate checks that the generic client of the IDL supports the options.
+func (o *ClientOptions) validate() error {
+	if err := checkOption("transport", o.Transport, supportedTransports); err != nil {
+		return err
+	}
+	if err := checkOption("payload codec", o.PayloadCodec, payloadCodecs); err != nil {
+		return err
+	}
+	if o.PayloadCodec == "go" && len(exceptionStatuses) > 0 {
+		return errors.New("payload codec \"go\" cannot decode the exceptions declared by the IDL, use \"dynamicgo\"")
+	}
+	if len(o.HostPorts) == 0 {
+		return errors.New("no host ports")
+	}
+	return nil
+}
 
-	"github.com/cloudwego/hertz/pkg/app"
-	"github.com/cloudwego/hertz/pkg/app/server"
-	"github.com/hertz-contrib/cors"
-	"github.com/hertz-contrib/swagger"
-	swaggerFiles "github.com/swaggo/files"
-)
+// checkOption checks that value is one of the supported values of an option.
+func checkOption(name, value string, supported []string) error {
+	for _, s := range supported {
+		if value == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported %s %q, expected one of %s", name, value, strings.Join(supported, ", "))
+}
 
-//go:embed openapi.yaml
-var openapiYAML []byte
+// clientOptions builds the options of a generic client.
+func (o *ClientOptions) clientOptions() ([]client.Option, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	var opts []client.Option
+	switch o.Transport {
+	case "ttheader":
+		opts = append(opts, client.WithTransportProtocol(transport.TTHeader), client.WithMetaHandler(transmeta.ClientTTHeaderHandler))
+	case "ttheader_framed":
+		opts = append(opts, client.WithTransportProtocol(transport.TTHeaderFramed), client.WithMetaHandler(transmeta.ClientTTHeaderHandler))
+	case "framed":
+		opts = append(opts, client.WithTransportProtocol(transport.Framed))
+	case "buffered":
+		opts = append(opts, client.WithTransportProtocol(transport.PurePayload))
+	}
+	if o.RPCTimeout > 0 {
+		opts = append(opts, client.WithRPCTimeout(o.RPCTimeout))
+	}
+	if o.ConnectTimeout > 0 {
+		opts = append(opts, client.WithConnectTimeout(o.ConnectTimeout))
+	}
+	opts = append(opts, client.WithHostPorts(o.HostPorts...))
+	return append(opts, o.Options...), nil
+}
 
-func BindSwagger(h *server.Hertz) {
-	h.Use(cors.Default())
+// splitList splits a comma-separated list, dropping the empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
 
-	h.GET("/swagger/*any", swagger.WrapHandler(
-		swaggerFiles.Handler,
-		swagger.URL("/openapi.yaml"),
-	))
+func setupSwaggerRoutes(h *server.Hertz, spec []byte) {
+	h.GET("swagger/*any", swagger.WrapHandler(swaggerFiles.Handler, swagger.URL("/openapi.yaml")))
 
 	h.GET("/openapi.yaml", func(c context.Context, ctx *app.RequestContext) {
 		ctx.Header("Content-Type", "application/x-yaml")
-		ctx.Write(openapiYAML)
+		ctx.Write(spec)
+	})
+}
+
+// setupProxyRoutes routes the requests to the generic client of their
+// service, named by the path or, with the "method" path style, found by the
+// method name.
+func setupProxyRoutes(h *server.Hertz, clients map[string]genericclient.Client) {
+	handler := func(c context.Context, ctx *app.RequestContext) {
+		serviceName, methodName := ctx.Param("Service"), ctx.Param("Method")
+		if pathStyle == "method" {
+			serviceName = serviceOf(methodName)
+		}
+		cli, ok := clients[serviceName]
+		if !ok {
+			handleError(ctx, "Service not found for "+string(ctx.Path()), http.StatusNotFound)
+			return
+		}
+
+		bodyBytes := ctx.Request.Body()
+
+		if metainfoStyle == "query" {
+			c = queryMetainfo(c, ctx)
+		} else {
+			c = headerMetainfo(c, ctx)
+		}
+
+		c = metainfo.WithBackwardValues(c)
+
+		jReq := string(bodyBytes)
+
+		start := time.Now()
+		jRsp, err := cli.GenericCall(c, methodName, jReq)
+		recordCall(c, serviceName, methodName, jReq, jRsp, err, time.Since(start))
+		if metainfoStyle != "query" {
+			setBackwardMetainfo(ctx, metainfo.RecvAllBackwardValues(c))
+		}
+		if err != nil {
+			handleCallError(ctx, serviceName+"/"+methodName, err)
+			return
+		}
+		if metainfoStyle != "query" {
+			ctx.Data(http.StatusOK, "application/json", []byte(jRsp.(string)))
+			return
+		}
+
+		result := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(jRsp.(string)), &result); err != nil {
+			hlog.Errorf("Failed to unmarshal response body: %v", err)
+			ctx.JSON(500, map[string]interface{}{
+				"error": "Failed to unmarshal response body",
+			})
+			return
+		}
+
+		m := metainfo.RecvAllBackwardValues(c)
+
+		for key, value := range m {
+			result[key] = value
+		}
+
+		respBody, err := json.Marshal(result)
+		if err != nil {
+			hlog.Errorf("Failed to marshal response body: %v", err)
+			ctx.JSON(500, map[string]interface{}{
+				"error": "Failed to marshal response body",
+			})
+			return
+		}
+
+		ctx.Data(http.StatusOK, "application/json", respBody)
+	}
+
+	if pathStyle == "method" {
+		h.Any("/:Method", handler)
+	} else {
+		h.Any("/:Service/:Method", handler)
+	}
+}
+
+// serviceOf returns the first service having the method.
+func serviceOf(method string) string {
+	for _, s := range services {
+		for _, m := range s.methods {
+			if m == method {
+				return s.name
+			}
+		}
+	}
+	return ""
+}
+
+const (
+	metainfoHeaderPrefix           = "X-Metainfo-"
+	persistentMetainfoHeaderPrefix = "X-Metainfo-Persistent-"
+)
+
+// setBackwardMetainfo sets the backward metainfo in the X-Metainfo-* response
+// headers, exposing them to the cross-origin callers such as Swagger UI.
+func setBackwardMetainfo(ctx *app.RequestContext, backward map[string]string) {
+	names := make([]string, 0, len(backward))
+	for key, value := range backward {
+		name := http.CanonicalHeaderKey(metainfoHeaderPrefix + metainfo.CGIVariableToHTTPHeader(key))
+		ctx.Response.Header.Set(name, value)
+		names = append(names, name)
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		ctx.Response.Header.Set("Access-Control-Expose-Headers", strings.Join(names, ", "))
+	}
+}
+
+// headerMetainfo adds the metainfo of the X-Metainfo-* request headers to c,
+// persistent if they are X-Metainfo-Persistent-* headers. Header names are
+// turned into metainfo keys as CGI variables, e.g. X-Metainfo-Log-Id gives
+// LOG_ID.
+func headerMetainfo(c context.Context, ctx *app.RequestContext) context.Context {
+	ctx.Request.Header.VisitAll(func(k, v []byte) {
+		key := string(k)
+		if name, ok := trimPrefixFold(key, persistentMetainfoHeaderPrefix); ok {
+			c = metainfo.WithPersistentValue(c, metainfo.HTTPHeaderToCGIVariable(name), string(v))
+		} else if name, ok := trimPrefixFold(key, metainfoHeaderPrefix); ok {
+			c = metainfo.WithValue(c, metainfo.HTTPHeaderToCGIVariable(name), string(v))
+		}
+	})
+	return c
+}
+
+// trimPrefixFold returns s without prefix, matched case-insensitively, and
+// whether s had the prefix followed by a non-empty name.
+func trimPrefixFold(s, prefix string) (string, bool) {
+	if len(s) <= len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return "", false
+	}
+	return s[len(prefix):], true
+}
+
+// queryMetainfo adds the query parameters of the request to c as metainfo,
+// persistent if they have the p_ prefix.
+func queryMetainfo(c context.Context, ctx *app.RequestContext) context.Context {
+	for k, v := range formatQueryParams(ctx) {
+		if strings.HasPrefix(k, "p_") {
+			c = metainfo.WithPersistentValue(c, k, v)
+		} else {
+			c = metainfo.WithValue(c, k, v)
+		}
+	}
+	return c
+}
+
+func formatQueryParams(ctx *app.RequestContext) map[string]string {
+	var QueryParams = make(map[string]string)
+	ctx.Request.URI().QueryArgs().VisitAll(func(key, value []byte) {
+		QueryParams[string(key)] = string(value)
+	})
+	return QueryParams
+}
+
+// handleCallError answers a failed generic call. Biz status errors keep their
+// code, message and extra, with the status documented for the code by the
+// error enum of the method, if any, and the exception declared by the method
+// keeps its body, with the status documented for it in exceptionStatuses.
+func handleCallError(ctx *app.RequestContext, method string, err error) {
+	hlog.Errorf("GenericCall error: %v", err)
+	if bizErr, ok := kerrors.FromBizStatusError(err); ok {
+		ctx.JSON(bizStatus(method, bizErr.BizStatusCode()), bizError(bizErr))
+		return
+	}
+	if status, ok := exceptionStatuses[method]; ok {
+		if exception, ok := declaredException(err); ok {
+			ctx.Data(status, "application/json", exception)
+			return
+		}
+	}
+	handleError(ctx, err.Error(), errorStatus(err))
+}
+
+// declaredException returns the JSON body of the exception declared by a
+// method, which the generic client reports as a remote error made of it.
+func declaredException(err error) ([]byte, bool) {
+	if !errors.Is(err, kerrors.ErrRemoteOrNetwork) {
+		return nil, false
+	}
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		err = cause
+	}
+	body := []byte(strings.TrimSpace(err.Error()))
+	if len(body) == 0 || body[0] != '{' || !json.Valid(body) {
+		return nil, false
+	}
+	return body, true
+}
+
+// errorStatus returns the HTTP status of a failed call: 504 for timeouts, 502
+// for the other errors of the transport to the Kitex server and 500 otherwise.
+func errorStatus(err error) int {
+	var timeout interface{ Timeout() bool }
+	switch {
+	case kerrors.IsTimeoutError(err), errors.Is(err, context.DeadlineExceeded), errors.As(err, &timeout) && timeout.Timeout():
+		return http.StatusGatewayTimeout
+	case errors.Is(err, kerrors.ErrRemoteOrNetwork), errors.Is(err, kerrors.ErrGetConnection),
+		errors.Is(err, kerrors.ErrServiceDiscovery), errors.Is(err, kerrors.ErrLoadbalance),
+		errors.Is(err, kerrors.ErrNoMoreInstance), errors.Is(err, kerrors.ErrCircuitBreak):
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
+// bizStatus returns the HTTP status of a biz status error: the status
+// documented for its code, else the code itself if it is an HTTP error status,
+// else 500.
+func bizStatus(method string, code int32) int {
+	if status, ok := errorStatuses[method][code]; ok {
+		return status
+	}
+	if code >= 400 && code < 600 {
+		return int(code)
+	}
+	return http.StatusInternalServerError
+}
+
+// bizError returns the body of a biz status error, holding its code, message
+// and extra.
+func bizError(bizErr kerrors.BizStatusErrorIface) map[string]interface{} {
+	return map[string]interface{}{
+		"code":    bizErr.BizStatusCode(),
+		"message": bizErr.BizMessage(),
+		"extra":   bizErr.BizExtra(),
+	}
+}
+
+func handleError(ctx *app.RequestContext, errMsg string, statusCode int) {
+	hlog.Errorf("Error: %s", errMsg)
+	ctx.JSON(statusCode, map[string]interface{}{
+		"error": errMsg,
 	})
 }
 `
 
+// ServerTemplateRpc and ServerTemplateRpcPb render the proxy of the generated
+// RPC servers, which is regenerated on every run.
 const ServerTemplateRpc = `package swagger
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	_ "embed"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"math/rand"
 	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bytedance/gopkg/cloud/metainfo"
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/config"
 	"github.com/cloudwego/hertz/pkg/common/hlog"
 	"github.com/cloudwego/hertz/pkg/network"
 	"github.com/cloudwego/hertz/pkg/route"
@@ -72,6 +1781,7 @@ import (
 	"github.com/cloudwego/kitex/client/genericclient"
 	"github.com/cloudwego/kitex/pkg/endpoint"
 	"github.com/cloudwego/kitex/pkg/generic"
+	"github.com/cloudwego/kitex/pkg/kerrors"
 	"github.com/cloudwego/kitex/pkg/klog"
 	"github.com/cloudwego/kitex/pkg/remote"
 	"github.com/cloudwego/kitex/pkg/remote/trans/detection"
@@ -79,9 +1789,11 @@ import (
 	"github.com/cloudwego/kitex/pkg/remote/trans/nphttp2"
 	"github.com/cloudwego/kitex/pkg/transmeta"
 	"github.com/cloudwego/kitex/transport"
+	"github.com/cloudwego/thriftgo/parser"
 	"github.com/hertz-contrib/cors"
 	"github.com/hertz-contrib/swagger"
 	swaggerFiles "github.com/swaggo/files"
+	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -91,77 +1803,24 @@ var (
 	httpReg     = regexp.MustCompile("^(?:GET |POST|PUT|DELE|HEAD|OPTI|CONN|TRAC|PATC)$")
 )
 
-const (
-	kitexAddr = "{{.KitexAddr}}"
-	idlFile   = "{{.IdlPath}}"
-)
-
-type MixTransHandlerFactory struct {
-	OriginFactory remote.ServerTransHandlerFactory
-}
-
-type transHandler struct {
-	remote.ServerTransHandler
-}
-
-func (t *transHandler) SetInvokeHandleFunc(inkHdlFunc endpoint.Endpoint) {
-	t.ServerTransHandler.(remote.InvokeHandleFuncSetter).SetInvokeHandleFunc(inkHdlFunc)
-}
-
-func (m MixTransHandlerFactory) NewTransHandler(opt *remote.ServerOption) (remote.ServerTransHandler, error) {
-
-	if hertzEngine == nil {
-		StartServer()
-	}
-
-	var kitexOrigin remote.ServerTransHandler
-	var err error
-
-	if m.OriginFactory != nil {
-		kitexOrigin, err = m.OriginFactory.NewTransHandler(opt)
-	} else {
-		kitexOrigin, err = detection.NewSvrTransHandlerFactory(netpoll.NewSvrTransHandlerFactory(), nphttp2.NewSvrTransHandlerFactory()).NewTransHandler(opt)
-	}
-	if err != nil {
-		return nil, err
-	}
-	return &transHandler{ServerTransHandler: kitexOrigin}, nil
-}
-
-func (t *transHandler) OnRead(ctx context.Context, conn net.Conn) error {
-	c, ok := conn.(network.Conn)
-	if ok {
-		pre, _ := c.Peek(4)
-		if httpReg.Match(pre) {
-			klog.Info("using Hertz to process request")
-			err := hertzEngine.Serve(ctx, c)
-			if err != nil {
-				err = errors.New(fmt.Sprintf("HERTZ: %s", err.Error()))
-			}
-			return err
-		}
-	}
-
-	return t.ServerTransHandler.OnRead(ctx, conn)
+type service struct {
+	name    string
+	methods []string
 }
 
-func StartServer() {
-	h := server.Default()
-	h.Use(cors.Default())
-
-	cli := initializeGenericClient()
-	setupSwaggerRoutes(h)
-	setupProxyRoutes(h, cli)
-
-	hlog.Info("Swagger UI is available at: http://" + kitexAddr + "/swagger/index.html")
-	err := h.Engine.Init()
-	if err != nil {
-		panic(err)
-	}
+// errorStatuses is empty, as Thrift methods have no error enum documenting
+// the status of their biz error codes.
+var errorStatuses = map[string]map[int32]int{}
 
-	hertzEngine = h.Engine
-}
+// supportedTransports are the transports of the JSON Thrift generic client,
+// the first being the default. It does not support gRPC.
+var supportedTransports = []string{"ttheader", "ttheader_framed", "framed", "buffered"}
 
+// payloadCodecs are the payload codecs of the JSON Thrift generic client, the
+// first being the default: "dynamicgo", which also decodes the exceptions
+// declared by the methods, or "go".
+var payloadCodecs = []string{"dynamicgo", "go"}
+` + ProxyTemplate + `
 func findThriftFile(fileName string) (string, error) {
 	workingDir, err := os.Getwd()
 	if err != nil {
@@ -187,159 +1846,162 @@ func findThriftFile(fileName string) (string, error) {
 				return filepath.SkipDir
 			}
 		}
-		return nil
-	})
-
-	if err == nil && foundPath != "" {
-		return foundPath, nil
-	}
-
-	parentDir := filepath.Dir(workingDir)
-	for parentDir != "/" && parentDir != "." && parentDir != workingDir {
-		filePath := filepath.Join(parentDir, fileName)
-		if _, err := os.Stat(filePath); err == nil {
-			return filePath, nil
-		}
-		workingDir = parentDir
-		parentDir = filepath.Dir(parentDir)
-	}
-
-	return "", errors.New("thrift file not found: " + fileName)
-}
-
-func initializeGenericClient() genericclient.Client {
-	thriftFile, err := findThriftFile(idlFile)
-	if err != nil {
-		hlog.Fatal("Failed to locate Thrift file:", err)
-	}
-
-	p, err := generic.NewThriftFileProviderWithDynamicGo(thriftFile)
-	if err != nil {
-		hlog.Fatal("Failed to create ThriftFileProvider:", err)
-	}
-
-	g, err := generic.JSONThriftGeneric(p)
-	if err != nil {
-		hlog.Fatal("Failed to create JsonThriftGeneric:", err)
-	}
-	var opts []client.Option
-	opts = append(opts, client.WithTransportProtocol(transport.TTHeader))
-	opts = append(opts, client.WithMetaHandler(transmeta.ClientTTHeaderHandler))
-	opts = append(opts, client.WithHostPorts(kitexAddr))
-	cli, err := genericclient.NewClient("swagger", g, opts...)
-	if err != nil {
-		hlog.Fatal("Failed to create generic client:", err)
-	}
-
-	return cli
-}
-
-func setupSwaggerRoutes(h *server.Hertz) {
-	h.GET("swagger/*any", swagger.WrapHandler(swaggerFiles.Handler, swagger.URL("/openapi.yaml")))
-
-	h.GET("/openapi.yaml", func(c context.Context, ctx *app.RequestContext) {
-		ctx.Header("Content-Type", "application/x-yaml")
-		ctx.Write(openapiYAML)
-	})
-}
-
-func setupProxyRoutes(h *server.Hertz, cli genericclient.Client) {
-	h.Any("/*ServiceMethod", func(c context.Context, ctx *app.RequestContext) {
-		serviceMethod := ctx.Param("ServiceMethod")
-		if serviceMethod == "" {
-			handleError(ctx, "ServiceMethod not provided", http.StatusBadRequest)
-			return
-		}
-
-		bodyBytes := ctx.Request.Body()
+		return nil
+	})
 
-		queryMap := formatQueryParams(ctx)
+	if err == nil && foundPath != "" {
+		return foundPath, nil
+	}
 
-		for k, v := range queryMap {
-			if strings.HasPrefix(k, "p_") {
-				c = metainfo.WithPersistentValue(c, k, v)
-			} else {
-				c = metainfo.WithValue(c, k, v)
-			}
+	parentDir := filepath.Dir(workingDir)
+	for parentDir != "/" && parentDir != "." && parentDir != workingDir {
+		filePath := filepath.Join(parentDir, fileName)
+		if _, err := os.Stat(filePath); err == nil {
+			return filePath, nil
 		}
+		workingDir = parentDir
+		parentDir = filepath.Dir(parentDir)
+	}
 
-		c = metainfo.WithBackwardValues(c)
+	return "", errors.New("thrift file not found: " + fileName)
+}
 
-		jReq := string(bodyBytes)
+func initializeGenericClients() (map[string]genericclient.Client, error) {
+	files, err := loadIDL()
+	if err != nil {
+		return nil, fmt.Errorf("locate Thrift file: %w", err)
+	}
 
-		jRsp, err := cli.GenericCall(c, serviceMethod, jReq)
-		if err != nil {
-			hlog.Errorf("GenericCall error: %v", err)
-			ctx.JSON(500, map[string]interface{}{
-				"error": err.Error(),
-			})
-			return
+	clients := make(map[string]genericclient.Client, len(services))
+	for _, s := range services {
+		if clients[s.name], err = newGenericClient(s.name, files); err != nil {
+			return nil, err
 		}
+	}
+	return clients, nil
+}
 
-		result := make(map[string]interface{})
-		if err := json.Unmarshal([]byte(jRsp.(string)), &result); err != nil {
-			hlog.Errorf("Failed to unmarshal response body: %v", err)
-			ctx.JSON(500, map[string]interface{}{
-				"error": "Failed to unmarshal response body",
-			})
-			return
-		}
+// loadIDL returns the Thrift files embedded in idlFiles or, if the IDL is not
+// embedded, read from the filesystem. The files are keyed by path, each
+// include being resolved from the directory of the including file.
+func loadIDL() (map[string]string, error) {
+	mainFile := filepath.Clean(idlFile)
+	if _, ok := idlFiles[mainFile]; ok {
+		return idlFiles, nil
+	}
 
-		m := metainfo.RecvAllBackwardValues(c)
+	thriftFile, err := findThriftFile(idlFile)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := parser.ParseFile(thriftFile, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]string{}
+	if err = readIDL(tree, mainFile, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
 
-		for key, value := range m {
-			result[key] = value
+func readIDL(tree *parser.Thrift, path string, files map[string]string) error {
+	if _, ok := files[path]; ok {
+		return nil
+	}
+	content, err := os.ReadFile(tree.Filename)
+	if err != nil {
+		return err
+	}
+	files[path] = string(content)
+	for _, include := range tree.Includes {
+		if include.Reference == nil {
+			continue
 		}
-
-		respBody, err := json.Marshal(result)
-		if err != nil {
-			hlog.Errorf("Failed to marshal response body: %v", err)
-			ctx.JSON(500, map[string]interface{}{
-				"error": "Failed to marshal response body",
-			})
-			return
+		if err = readIDL(include.Reference, filepath.Join(filepath.Dir(path), include.Path), files); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		ctx.Data(http.StatusOK, "application/json", respBody)
+// serviceIDL returns the files of the IDL with a main file whose only service
+// extends the service named serviceName, and the path of the main file. The
+// descriptor of an IDL only holds one of its services.
+func serviceIDL(serviceName string, files map[string]string) (string, map[string]string) {
+	dir, base := filepath.Split(filepath.Clean(idlFile))
+	mainPath := filepath.Join(dir, serviceName+".swagger.thrift")
 
-	})
+	includes := make(map[string]string, len(files)+1)
+	for path, content := range files {
+		includes[path] = content
+	}
+	includes[mainPath] = fmt.Sprintf("include %q\n\nservice %s extends %s.%s {}\n", base, serviceName, strings.TrimSuffix(base, ".thrift"), serviceName)
+	return mainPath, includes
 }
 
-func formatQueryParams(ctx *app.RequestContext) map[string]string {
-	var QueryParams = make(map[string]string)
-	ctx.Request.URI().QueryArgs().VisitAll(func(key, value []byte) {
-		QueryParams[string(key)] = string(value)
-	})
-	return QueryParams
-}
+func newGenericClient(serviceName string, files map[string]string) (genericclient.Client, error) {
+	opts, err := ProxyClientOptions.clientOptions()
+	if err != nil {
+		return nil, fmt.Errorf("invalid client options: %w", err)
+	}
 
-func handleError(ctx *app.RequestContext, errMsg string, statusCode int) {
-	hlog.Errorf("Error: %s", errMsg)
-	ctx.JSON(statusCode, map[string]interface{}{
-		"error": errMsg,
-	})
+	mainPath, includes := serviceIDL(serviceName, files)
+	var p *generic.ThriftContentWithAbsIncludePathProvider
+	if ProxyClientOptions.PayloadCodec == "go" {
+		p, err = generic.NewThriftContentWithAbsIncludePathProvider(mainPath, includes)
+	} else {
+		p, err = generic.NewThriftContentWithAbsIncludePathProviderWithDynamicGo(mainPath, includes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create ThriftContentProvider: %w", err)
+	}
+
+	g, err := generic.JSONThriftGeneric(p)
+	if err != nil {
+		return nil, fmt.Errorf("create JSONThriftGeneric: %w", err)
+	}
+	cli, err := genericclient.NewClient(serviceName, g, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create generic client: %w", err)
+	}
+	return cli, nil
 }
-`
+` + MockTemplate + RecordTemplate + ValidateTemplate
 
 const ServerTemplateRpcPb = `package swagger
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	_ "embed"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"math/rand"
 	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bytedance/gopkg/cloud/metainfo"
 	"github.com/cloudwego/dynamicgo/proto"
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/config"
 	"github.com/cloudwego/hertz/pkg/common/hlog"
 	"github.com/cloudwego/hertz/pkg/network"
 	"github.com/cloudwego/hertz/pkg/route"
@@ -347,6 +2009,7 @@ import (
 	"github.com/cloudwego/kitex/client/genericclient"
 	"github.com/cloudwego/kitex/pkg/endpoint"
 	"github.com/cloudwego/kitex/pkg/generic"
+	"github.com/cloudwego/kitex/pkg/kerrors"
 	"github.com/cloudwego/kitex/pkg/klog"
 	"github.com/cloudwego/kitex/pkg/remote"
 	"github.com/cloudwego/kitex/pkg/remote/trans/detection"
@@ -356,7 +2019,12 @@ import (
 	"github.com/cloudwego/kitex/transport"
 	"github.com/hertz-contrib/cors"
 	"github.com/hertz-contrib/swagger"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/desc/protoprint"
 	swaggerFiles "github.com/swaggo/files"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -366,77 +2034,24 @@ var (
 	httpReg     = regexp.MustCompile("^(?:GET |POST|PUT|DELE|HEAD|OPTI|CONN|TRAC|PATC)$")
 )
 
-const (
-	kitexAddr = "{{.KitexAddr}}"
-	idlFile   = "{{.IdlPath}}"
-)
-
-type MixTransHandlerFactory struct {
-	OriginFactory remote.ServerTransHandlerFactory
-}
-
-type transHandler struct {
-	remote.ServerTransHandler
-}
-
-func (t *transHandler) SetInvokeHandleFunc(inkHdlFunc endpoint.Endpoint) {
-	t.ServerTransHandler.(remote.InvokeHandleFuncSetter).SetInvokeHandleFunc(inkHdlFunc)
-}
-
-func (m MixTransHandlerFactory) NewTransHandler(opt *remote.ServerOption) (remote.ServerTransHandler, error) {
-
-	if hertzEngine == nil {
-		StartServer()
-	}
-
-	var kitexOrigin remote.ServerTransHandler
-	var err error
-
-	if m.OriginFactory != nil {
-		kitexOrigin, err = m.OriginFactory.NewTransHandler(opt)
-	} else {
-		kitexOrigin, err = detection.NewSvrTransHandlerFactory(netpoll.NewSvrTransHandlerFactory(), nphttp2.NewSvrTransHandlerFactory()).NewTransHandler(opt)
-	}
-	if err != nil {
-		return nil, err
-	}
-	return &transHandler{ServerTransHandler: kitexOrigin}, nil
-}
-
-func (t *transHandler) OnRead(ctx context.Context, conn net.Conn) error {
-	c, ok := conn.(network.Conn)
-	if ok {
-		pre, _ := c.Peek(4)
-		if httpReg.Match(pre) {
-			klog.Info("using Hertz to process request")
-			err := hertzEngine.Serve(ctx, c)
-			if err != nil {
-				err = errors.New(fmt.Sprintf("HERTZ: %s", err.Error()))
-			}
-			return err
-		}
-	}
-
-	return t.ServerTransHandler.OnRead(ctx, conn)
+type service struct {
+	name    string
+	file    string
+	methods []string
 }
 
-func StartServer() {
-	h := server.Default()
-	h.Use(cors.Default())
-
-	cli := initializeGenericClient()
-	setupSwaggerRoutes(h)
-	setupProxyRoutes(h, cli)
-
-	hlog.Info("Swagger UI is available at: http://" + kitexAddr + "/swagger/index.html")
-	err := h.Engine.Init()
-	if err != nil {
-		panic(err)
-	}
+// exceptionStatuses is empty, as protobuf methods declare no exception.
+var exceptionStatuses = map[string]int{}
 
-	hertzEngine = h.Engine
-}
+// supportedTransports are the transports of the JSON protobuf generic client,
+// the first being the default. Kitex protobuf needs a framed transport, and
+// the generic client does not support gRPC.
+var supportedTransports = []string{"ttheader_framed", "framed"}
 
+// payloadCodecs are the payload codecs of the JSON protobuf generic client,
+// which only converts the bodies with dynamicgo.
+var payloadCodecs = []string{"dynamicgo"}
+` + ProxyTemplate + `
 func findPbFile(fileName string) (string, error) {
 	workingDir, err := os.Getwd()
 	if err != nil {
@@ -444,8 +2059,10 @@ func findPbFile(fileName string) (string, error) {
 	}
 
 	foundPath := ""
-	relativePath := fileName
+	relativePath := filepath.FromSlash(fileName)
 
+	// The file may be below its import root, in which case the first file
+	// whose path ends with fileName is taken.
 	err = filepath.Walk(workingDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -461,6 +2078,9 @@ func findPbFile(fileName string) (string, error) {
 				foundPath = path
 				return filepath.SkipDir
 			}
+			if foundPath == "" && strings.HasSuffix(relative, string(filepath.Separator)+relativePath) {
+				foundPath = path
+			}
 		}
 		return nil
 	})
@@ -482,117 +2102,138 @@ func findPbFile(fileName string) (string, error) {
 	return "", errors.New("proto file not found: " + fileName)
 }
 
-func initializeGenericClient() genericclient.Client {
-	pbFile, err := findPbFile(idlFile)
-	if err != nil {
-		hlog.Fatal("Failed to locate Proto file:", err)
-	}
-
-	dOpts := proto.Options{}
-	p, err := generic.NewPbFileProviderWithDynamicGo(pbFile, context.Background(), dOpts)
-	if err != nil {
-		hlog.Fatal("Failed to create PbFileProvider:", err)
+func initializeGenericClients() (map[string]genericclient.Client, error) {
+	var roots []string
+	var err error
+	if !embedded() {
+		if roots, err = importRoots(); err != nil {
+			return nil, fmt.Errorf("locate Proto file: %w", err)
+		}
 	}
 
-	g, err := generic.JSONPbGeneric(p)
-	if err != nil {
-		hlog.Fatal("Failed to create JsonPbGeneric:", err)
-	}
-	var opts []client.Option
-	opts = append(opts, client.WithTransportProtocol(transport.TTHeader))
-	opts = append(opts, client.WithMetaHandler(transmeta.ClientTTHeaderHandler))
-	opts = append(opts, client.WithHostPorts(kitexAddr))
-	cli, err := genericclient.NewClient("swagger", g, opts...)
-	if err != nil {
-		hlog.Fatal("Failed to create generic client:", err)
+	clients := make(map[string]genericclient.Client, len(services))
+	for _, s := range services {
+		if clients[s.name], err = newGenericClient(s.file, s.name, roots); err != nil {
+			return nil, err
+		}
 	}
-
-	return cli
+	return clients, nil
 }
 
-func setupSwaggerRoutes(h *server.Hertz) {
-	h.GET("swagger/*any", swagger.WrapHandler(swaggerFiles.Handler, swagger.URL("/openapi.yaml")))
-
-	h.GET("/openapi.yaml", func(c context.Context, ctx *app.RequestContext) {
-		ctx.Header("Content-Type", "application/x-yaml")
-		ctx.Write(openapiYAML)
-	})
+// embedded reports whether the files of all the services are embedded in
+// idlFiles, in which case the filesystem is not searched for the IDL.
+func embedded() bool {
+	for _, s := range services {
+		if _, ok := idlFiles[s.file]; !ok {
+			return false
+		}
+	}
+	return true
 }
 
-func setupProxyRoutes(h *server.Hertz, cli genericclient.Client) {
-	h.Any("/*ServiceMethod", func(c context.Context, ctx *app.RequestContext) {
-		serviceMethod := ctx.Param("ServiceMethod")
-		if serviceMethod == "" {
-			handleError(ctx, "ServiceMethod not provided", http.StatusBadRequest)
-			return
+// importRoots returns the configured import paths followed by the import
+// roots of the files of the services, found by locating each file.
+func importRoots() ([]string, error) {
+	roots := append([]string{}, importPaths...)
+	seen := map[string]bool{}
+	for _, s := range services {
+		pbFile, err := findPbFile(s.file)
+		if err != nil {
+			return nil, err
 		}
-
-		bodyBytes := ctx.Request.Body()
-
-		queryMap := formatQueryParams(ctx)
-
-		for k, v := range queryMap {
-			if strings.HasPrefix(k, "p_") {
-				c = metainfo.WithPersistentValue(c, k, v)
-			} else {
-				c = metainfo.WithValue(c, k, v)
-			}
+		root := filepath.Clean(strings.TrimSuffix(pbFile, filepath.FromSlash(s.file)))
+		if !seen[root] {
+			seen[root] = true
+			roots = append(roots, root)
 		}
+	}
+	return roots, nil
+}
 
-		c = metainfo.WithBackwardValues(c)
-
-		jReq := string(bodyBytes)
-
-		jRsp, err := cli.GenericCall(c, serviceMethod, jReq)
-		if err != nil {
-			hlog.Errorf("GenericCall error: %v", err)
-			ctx.JSON(500, map[string]interface{}{
-				"error": err.Error(),
-			})
-			return
-		}
+// serviceIDL returns the proto file reduced to the service named serviceName,
+// with the name and content of the files it imports. The descriptor of an
+// IDL only holds one of its services. The files are read from idlFiles, or
+// from the import paths if they are given.
+func serviceIDL(pbFile, serviceName string, importPaths []string) (string, string, map[string]string, error) {
+	p := protoparse.Parser{ImportPaths: importPaths}
+	if len(importPaths) == 0 {
+		p.Accessor = protoparse.FileContentsFromMap(idlFiles)
+	}
+	fds, err := p.ParseFiles(pbFile)
+	if err != nil {
+		return "", "", nil, err
+	}
 
-		result := make(map[string]interface{})
-		if err := json.Unmarshal([]byte(jRsp.(string)), &result); err != nil {
-			hlog.Errorf("Failed to unmarshal response body: %v", err)
-			ctx.JSON(500, map[string]interface{}{
-				"error": "Failed to unmarshal response body",
-			})
-			return
+	fd := fds[0].AsFileDescriptorProto()
+	var kept []*descriptorpb.ServiceDescriptorProto
+	for _, s := range fd.GetService() {
+		if s.GetName() == serviceName {
+			kept = append(kept, s)
 		}
+	}
+	if len(kept) == 0 {
+		return "", "", nil, fmt.Errorf("service %s not found in %s", serviceName, pbFile)
+	}
+	fd.Service = kept
 
-		m := metainfo.RecvAllBackwardValues(c)
+	file, err := desc.CreateFileDescriptor(fd, fds[0].GetDependencies()...)
+	if err != nil {
+		return "", "", nil, err
+	}
+	var printer protoprint.Printer
+	content, err := printer.PrintProtoToString(file)
+	if err != nil {
+		return "", "", nil, err
+	}
+	includes := map[string]string{}
+	if err = printImports(&printer, file, includes); err != nil {
+		return "", "", nil, err
+	}
+	return file.GetName(), content, includes, nil
+}
 
-		for key, value := range m {
-			result[key] = value
+func printImports(printer *protoprint.Printer, file *desc.FileDescriptor, includes map[string]string) error {
+	for _, dep := range file.GetDependencies() {
+		if _, ok := includes[dep.GetName()]; ok {
+			continue
 		}
-
-		respBody, err := json.Marshal(result)
+		content, err := printer.PrintProtoToString(dep)
 		if err != nil {
-			hlog.Errorf("Failed to marshal response body: %v", err)
-			ctx.JSON(500, map[string]interface{}{
-				"error": "Failed to marshal response body",
-			})
-			return
+			return err
 		}
+		includes[dep.GetName()] = content
+		if err = printImports(printer, dep, includes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		ctx.Data(http.StatusOK, "application/json", respBody)
+func newGenericClient(pbFile, serviceName string, importPaths []string) (genericclient.Client, error) {
+	opts, err := ProxyClientOptions.clientOptions()
+	if err != nil {
+		return nil, fmt.Errorf("invalid client options: %w", err)
+	}
 
-	})
-}
+	mainPath, content, includes, err := serviceIDL(pbFile, serviceName, importPaths)
+	if err != nil {
+		return nil, fmt.Errorf("parse Proto file: %w", err)
+	}
 
-func formatQueryParams(ctx *app.RequestContext) map[string]string {
-	var QueryParams = make(map[string]string)
-	ctx.Request.URI().QueryArgs().VisitAll(func(key, value []byte) {
-		QueryParams[string(key)] = string(value)
-	})
-	return QueryParams
-}
+	dOpts := proto.Options{}
+	p, err := generic.NewPbContentProviderWithDynamicGo(context.Background(), dOpts, mainPath, content, includes)
+	if err != nil {
+		return nil, fmt.Errorf("create PbFileProvider: %w", err)
+	}
 
-func handleError(ctx *app.RequestContext, errMsg string, statusCode int) {
-	hlog.Errorf("Error: %s", errMsg)
-	ctx.JSON(statusCode, map[string]interface{}{
-		"error": errMsg,
-	})
+	g, err := generic.JSONPbGeneric(p)
+	if err != nil {
+		return nil, fmt.Errorf("create JSONPbGeneric: %w", err)
+	}
+	cli, err := genericclient.NewClient(serviceName, g, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create generic client: %w", err)
+	}
+	return cli, nil
 }
-`
+` + MockTemplate + RecordTemplate + ValidateTemplate
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tpl
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+type testService struct {
+	Name    string
+	File    string
+	Methods []string
+}
+
+type testIdlFile struct {
+	Path    string
+	Literal string
+}
+
+type testStatus struct {
+	Method string
+	Status int
+}
+
+type testCode struct {
+	Code   int32
+	Status int
+}
+
+type testCodes struct {
+	Method string
+	Codes  []*testCode
+}
+
+// testServer is the data the generators render the server templates with.
+type testServer struct {
+	HertzAddr         string
+	KitexAddr         string
+	IdlPath           string
+	PathStyle         string
+	MetainfoStyle     string
+	ImportPaths       []string
+	Services          []*testService
+	IdlFiles          []*testIdlFile
+	ExceptionStatuses []*testStatus
+	ErrorStatuses     []*testCodes
+}
+
+// TestServerTemplates renders the RPC servers from the IDL of testdata and
+// runs the tests of testdata/proxy against each of them, in a module with the
+// dependencies of the plugin generating it.
+func TestServerTemplates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the generated servers")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	methods := []string{"Get", "Put"}
+	tests := []struct {
+		name   string
+		plugin string // Directory of the plugin module.
+		idl    string
+		files  map[string]string // Templates by generated file.
+		server *testServer
+	}{
+		{
+			name:   "thrift",
+			plugin: "thrift-gen-rpc-swagger",
+			idl:    "hello.thrift",
+			files: map[string]string{
+				"swagger.go": SettingsTemplateRpc,
+				"proxy.go":   ServerTemplateRpc,
+				"idl.go":     IdlTemplate + ServicesTemplate + ExceptionStatusesTemplate,
+			},
+			server: &testServer{
+				IdlPath:           "hello.thrift",
+				Services:          []*testService{{Name: "Svc", Methods: methods}},
+				ExceptionStatuses: []*testStatus{{Method: "Svc/Get", Status: 404}},
+			},
+		},
+		{
+			name:   "protobuf",
+			plugin: "protoc-gen-rpc-swagger",
+			idl:    "hello.proto",
+			files: map[string]string{
+				"swagger.go": SettingsTemplateRpcPb,
+				"proxy.go":   ServerTemplateRpcPb,
+				"idl.go":     IdlTemplate + ServicesTemplatePb + ErrorStatusesTemplate,
+			},
+			server: &testServer{
+				Services:      []*testService{{Name: "Svc", File: "hello.proto", Methods: methods}},
+				ErrorStatuses: []*testCodes{{Method: "Svc/Get", Codes: []*testCode{{Code: 1001, Status: 403}}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestModule(t, dir, filepath.Join("..", "..", tt.plugin))
+
+			pkg := filepath.Join(dir, "swagger")
+			if err := os.Mkdir(pkg, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			idl := readFile(t, filepath.Join("testdata", tt.idl))
+			s := *tt.server
+			s.HertzAddr, s.KitexAddr, s.PathStyle, s.MetainfoStyle = "127.0.0.1:8080", "127.0.0.1:8888", "service", "header"
+			s.IdlFiles = []*testIdlFile{{Path: tt.idl, Literal: "`" + idl + "`"}}
+			for name, text := range tt.files {
+				var buf bytes.Buffer
+				if err := template.Must(template.New(name).Parse(text)).Execute(&buf, &s); err != nil {
+					t.Fatalf("render %s: %s", name, err)
+				}
+				writeFile(t, filepath.Join(pkg, name), buf.String())
+			}
+			writeFile(t, filepath.Join(pkg, "openapi.yaml"), readFile(t, filepath.Join("testdata", "openapi.yaml")))
+			tests, err := filepath.Glob(filepath.Join("testdata", "proxy", "*_test.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, test := range tests {
+				writeFile(t, filepath.Join(pkg, filepath.Base(test)), readFile(t, test))
+			}
+
+			cmd := exec.Command("go", "test", "-count=1", "./swagger")
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("go test: %s\n%s", err, out)
+			}
+		})
+	}
+}
+
+var replaceParent = regexp.MustCompile(`=> \.\./?\n`)
+
+// writeTestModule writes in dir a module with the requirements of the plugin
+// module in pluginDir.
+func writeTestModule(t *testing.T, dir, pluginDir string) {
+	root, err := filepath.Abs(filepath.Join(pluginDir, ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod := readFile(t, filepath.Join(pluginDir, "go.mod"))
+	mod = "module tpltest\n" + mod[strings.Index(mod, "\n")+1:]
+	mod = replaceParent.ReplaceAllString(mod, "=> "+filepath.ToSlash(root)+"\n")
+	writeFile(t, filepath.Join(dir, "go.mod"), mod)
+	writeFile(t, filepath.Join(dir, "go.sum"), readFile(t, filepath.Join(pluginDir, "go.sum")))
+}
+
+func readFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
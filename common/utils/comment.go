@@ -0,0 +1,119 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Comment directives, written on a line of their own.
+const (
+	// deprecatedDirective marks the element as deprecated. Any text after it
+	// is kept in the description as a "Deprecated:" note.
+	deprecatedDirective = "@deprecated"
+	// exampleDirective sets the example of a field. The rest of the line is
+	// the example value, written as YAML or JSON.
+	exampleDirective = "@example"
+)
+
+// commentDirective returns the argument of the directive named name if line
+// holds it. Comment markers around the line are ignored.
+func commentDirective(line, name string) (string, bool) {
+	line = strings.TrimSpace(strings.TrimSuffix(strings.TrimLeft(line, "/* \t"), "*/"))
+	if !strings.HasPrefix(line, name) {
+		return "", false
+	}
+	arg := line[len(name):]
+	if arg != "" && arg[0] != ' ' && arg[0] != '\t' {
+		return "", false
+	}
+	return strings.TrimSpace(arg), true
+}
+
+// StripCommentDirectives removes the directive lines from comment, keeping
+// the note of `@deprecated` as a "Deprecated:" line.
+func StripCommentDirectives(comment string) string {
+	lines := strings.Split(comment, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if _, ok := commentDirective(line, exampleDirective); ok {
+			continue
+		}
+		if note, ok := commentDirective(line, deprecatedDirective); ok {
+			if note != "" {
+				kept = append(kept, deprecatedCommentPrefix+" "+note)
+			}
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// CommentExample returns the value of the first `@example` directive of
+// comment.
+func CommentExample(comment string) (string, bool) {
+	for _, line := range strings.Split(comment, "\n") {
+		if example, ok := commentDirective(line, exampleDirective); ok && example != "" {
+			return example, true
+		}
+	}
+	return "", false
+}
+
+// ExampleYAML returns the YAML of an `@example` value. Values of string
+// fields are quoted unless they already are, so that `@example 42` stays a
+// string.
+func ExampleYAML(example string, isString bool) string {
+	if !isString || strings.HasPrefix(example, `"`) || strings.HasPrefix(example, `'`) {
+		return example
+	}
+	return strconv.Quote(example)
+}
+
+// SplitSummary splits a method comment into the summary and the description
+// of its operation. The summary is the first sentence of the first
+// paragraph, or its first line if it has no sentence end.
+func SplitSummary(comment string) (summary, description string) {
+	comment = strings.TrimSpace(comment)
+	paragraph, rest, _ := strings.Cut(comment, "\n\n")
+	end := sentenceEnd(paragraph)
+	if end < 0 {
+		end = strings.Index(paragraph, "\n")
+	}
+	if end < 0 {
+		return paragraph, strings.TrimSpace(rest)
+	}
+	summary = strings.Join(strings.Fields(paragraph[:end]), " ")
+	description = strings.TrimSpace(paragraph[end:])
+	if rest = strings.TrimSpace(rest); rest != "" {
+		description = AppendParagraph(description, rest)
+	}
+	return summary, description
+}
+
+// sentenceEnd returns the index just after the period ending the first
+// sentence of text, or -1 if the sentence does not end before the text.
+func sentenceEnd(text string) int {
+	for i := 0; i+1 < len(text); i++ {
+		if text[i] == '.' && (text[i+1] == ' ' || text[i+1] == '\n') {
+			return i + 1
+		}
+	}
+	return -1
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"strings"
+)
+
+// deprecatedCommentPrefix starts the deprecation note of a comment, following
+// the Go convention.
+const deprecatedCommentPrefix = "Deprecated:"
+
+// IsDeprecatedComment reports whether comment holds a deprecation note, that
+// is a line starting with "Deprecated:" or an `@deprecated` directive.
+// Comment markers left at the start of the line are ignored.
+func IsDeprecatedComment(comment string) bool {
+	for _, line := range strings.Split(comment, "\n") {
+		if strings.HasPrefix(strings.TrimLeft(line, "/* \t"), deprecatedCommentPrefix) {
+			return true
+		}
+		if _, ok := commentDirective(line, deprecatedDirective); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDeprecatedAnnotation reports whether the values of a Thrift `deprecated`
+// annotation mark the element as deprecated. Any value but "false" does.
+func IsDeprecatedAnnotation(values []string) bool {
+	return len(values) > 0 && values[0] != "false"
+}
+
+// DeprecatedEnumValuesNote lists the deprecated values of an enum for the
+// description of the fields using it, since OpenAPI can not deprecate single
+// enum values.
+func DeprecatedEnumValuesNote(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return "Deprecated values: `" + strings.Join(names, "`, `") + "`."
+}
@@ -0,0 +1,421 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MergeStrategy tells how a list from an annotation is merged into the list
+// of the generated object.
+type MergeStrategy int
+
+const (
+	// MergeDefault picks the strategy from the element type: lists of named
+	// entries (properties, tags, extensions...) are merged by name, lists of
+	// Any values (enum, examples) are replaced and other lists are appended,
+	// skipping strings that are already present.
+	MergeDefault MergeStrategy = iota
+	// MergeAppend appends the annotation entries to the generated ones.
+	MergeAppend
+	// MergeReplace replaces the generated list with the annotation one.
+	MergeReplace
+	// MergeByName deep merges entries with the same name and appends the rest.
+	MergeByName
+)
+
+// Keys of the object form a list can be given in to choose its merge strategy,
+// e.g. `required: {$replace: ["id"]}`.
+var mergeDirectives = map[string]MergeStrategy{
+	"$append":  MergeAppend,
+	"$replace": MergeReplace,
+	"$merge":   MergeByName,
+}
+
+// listDirective unwraps a list given in the object form selecting a merge
+// strategy. Plain lists are returned with MergeDefault.
+func listDirective(value interface{}) (MergeStrategy, interface{}, bool) {
+	m, ok := toStringMap(value)
+	if !ok || len(m) != 1 {
+		return MergeDefault, value, false
+	}
+	for k, v := range m {
+		if strategy, ok := mergeDirectives[k]; ok {
+			return strategy, v, true
+		}
+	}
+	return MergeDefault, value, false
+}
+
+// MergeOptionValue deep merges an annotation payload, in the tree form accepted
+// by CheckOptionValue, into dst, a pointer to an openapi model struct. Objects
+// are merged field by field, so only the keys present in the payload change
+// dst. A present key always wins, which lets annotations override generated
+// values with false, 0 or "", and null clears the generated value. Lists are
+// merged according to their MergeStrategy.
+func MergeOptionValue(dst, value interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("dst must be a non-nil pointer")
+	}
+	return mergeValue(v.Elem(), value, "")
+}
+
+func mergeValue(dst reflect.Value, value interface{}, path string) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	mismatch := func(expected string) error {
+		return fmt.Errorf("%s: expected %s, got %s", pathOrRoot(path), expected, describeValue(value))
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return mergeValue(dst.Elem(), value, path)
+
+	case reflect.Struct:
+		m, ok := toStringMap(value)
+		if !ok {
+			return mismatch("object")
+		}
+		fields := jsonFields(dst.Type())
+		for _, k := range sortedKeys(m) {
+			i, ok := fields[k]
+			if !ok {
+				return fmt.Errorf("%s: unknown key %q", pathOrRoot(path), k)
+			}
+			if f := dst.Field(i); f.CanSet() {
+				if err := mergeValue(f, m[k], joinPath(path, k)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() == reflect.Uint8 {
+			s, ok := value.(string)
+			if !ok {
+				return mismatch("string")
+			}
+			dst.SetBytes([]byte(s))
+			return nil
+		}
+		strategy, items, _ := listDirective(value)
+		list, ok := items.([]interface{})
+		if !ok {
+			if items == nil {
+				dst.Set(reflect.Zero(dst.Type()))
+				return nil
+			}
+			return mismatch("list")
+		}
+		return mergeList(dst, list, strategy, path)
+
+	case reflect.Map:
+		m, ok := toStringMap(value)
+		if !ok {
+			return mismatch("object")
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMap(dst.Type()))
+		}
+		for _, k := range sortedKeys(m) {
+			key := reflect.New(dst.Type().Key()).Elem()
+			if err := mergeValue(key, k, path); err != nil {
+				return err
+			}
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if existing := dst.MapIndex(key); existing.IsValid() {
+				elem.Set(existing)
+			}
+			if err := mergeValue(elem, m[k], joinPath(path, k)); err != nil {
+				return err
+			}
+			dst.SetMapIndex(key, elem)
+		}
+
+	case reflect.String:
+		switch v := value.(type) {
+		case string:
+			dst.SetString(v)
+		case bool:
+			dst.SetString(strconv.FormatBool(v))
+		default:
+			if _, ok := toFloat(value); !ok {
+				return mismatch("string")
+			}
+			dst.SetString(fmt.Sprint(value))
+		}
+
+	case reflect.Bool:
+		switch v := value.(type) {
+		case bool:
+			dst.SetBool(v)
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return mismatch("boolean")
+			}
+			dst.SetBool(b)
+		default:
+			return mismatch("boolean")
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, ok := toFloat(value)
+		if !ok {
+			return mismatch("integer")
+		}
+		dst.SetInt(int64(f))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f, ok := toFloat(value)
+		if !ok {
+			return mismatch("integer")
+		}
+		dst.SetUint(uint64(f))
+
+	case reflect.Float32, reflect.Float64:
+		f, ok := toFloat(value)
+		if !ok {
+			return mismatch("number")
+		}
+		dst.SetFloat(f)
+
+	case reflect.Interface:
+		dst.Set(reflect.ValueOf(value))
+
+	default:
+		return fmt.Errorf("%s: unsupported field type %s", pathOrRoot(path), dst.Type())
+	}
+	return nil
+}
+
+func mergeList(dst reflect.Value, items []interface{}, strategy MergeStrategy, path string) error {
+	elemType := dst.Type().Elem()
+	if strategy == MergeDefault {
+		strategy = defaultStrategy(elemType)
+	}
+
+	switch strategy {
+	case MergeByName:
+		for i, item := range items {
+			elem := reflect.New(elemType).Elem()
+			if err := mergeValue(elem, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+			if j := indexByName(dst, entryName(elem)); j >= 0 {
+				if err := mergeValue(dst.Index(j), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+				continue
+			}
+			dst.Set(reflect.Append(dst, elem))
+		}
+		return nil
+
+	case MergeReplace:
+		dst.Set(reflect.MakeSlice(dst.Type(), 0, len(items)))
+	}
+
+	for i, item := range items {
+		elem := reflect.New(elemType).Elem()
+		if err := mergeValue(elem, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+		if elemType.Kind() == reflect.String && containsValue(dst, elem) {
+			continue
+		}
+		dst.Set(reflect.Append(dst, elem))
+	}
+	return nil
+}
+
+func defaultStrategy(elemType reflect.Type) MergeStrategy {
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() == reflect.Struct {
+		if elemType.Name() == "Any" {
+			return MergeReplace
+		}
+		if f, ok := elemType.FieldByName("Name"); ok && f.Type.Kind() == reflect.String {
+			return MergeByName
+		}
+	}
+	return MergeAppend
+}
+
+func entryName(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	return v.FieldByName("Name").String()
+}
+
+func indexByName(list reflect.Value, name string) int {
+	if name == "" {
+		return -1
+	}
+	for i := 0; i < list.Len(); i++ {
+		if entryName(list.Index(i)) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func containsValue(list, v reflect.Value) bool {
+	for i := 0; i < list.Len(); i++ {
+		if list.Index(i).Interface() == v.Interface() {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonFields(t reflect.Type) map[string]int {
+	fields := map[string]int{}
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		if name != "" {
+			fields[name] = i
+		}
+	}
+	return fields
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+	return path
+}
+
+// MergeOptionMessage deep merges an openapi extension message src into dst
+// with the semantics of MergeOptionValue: messages are merged field by field,
+// maps entry by entry and repeated fields according to MergeDefault. Unlike
+// proto.Merge, repeated fields are therefore not blindly appended, so e.g. the
+// properties of an `openapi.schema` option are merged into the generated ones.
+// proto3 scalar fields have no presence, so they cannot be overridden with
+// their zero value.
+func MergeOptionMessage(dst, src proto.Message) {
+	if dst == nil || src == nil || !src.ProtoReflect().IsValid() {
+		return
+	}
+	mergeMessage(dst.ProtoReflect(), src.ProtoReflect())
+}
+
+func mergeMessage(dst, src protoreflect.Message) {
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			mergeMessageList(dst.Mutable(fd).List(), v.List(), fd)
+		case fd.IsMap():
+			dstMap := dst.Mutable(fd).Map()
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				if fd.MapValue().Message() != nil && dstMap.Has(k) {
+					mergeMessage(dstMap.Mutable(k).Message(), mv.Message())
+				} else {
+					dstMap.Set(k, cloneValue(mv))
+				}
+				return true
+			})
+		case fd.Message() != nil:
+			mergeMessage(dst.Mutable(fd).Message(), v.Message())
+		default:
+			dst.Set(fd, v)
+		}
+		return true
+	})
+}
+
+func mergeMessageList(dst, src protoreflect.List, fd protoreflect.FieldDescriptor) {
+	switch {
+	case fd.Kind() == protoreflect.StringKind:
+		for i := 0; i < src.Len(); i++ {
+			if !listHasString(dst, src.Get(i).String()) {
+				dst.Append(src.Get(i))
+			}
+		}
+	case fd.Message() != nil && fd.Message().Name() == "Any":
+		dst.Truncate(0)
+		for i := 0; i < src.Len(); i++ {
+			dst.Append(cloneValue(src.Get(i)))
+		}
+	case fd.Message() != nil && isNamedMessage(fd.Message()):
+		nameField := fd.Message().Fields().ByName("name")
+		for i := 0; i < src.Len(); i++ {
+			elem := src.Get(i).Message()
+			if j := listIndexByName(dst, nameField, elem.Get(nameField).String()); j >= 0 {
+				mergeMessage(dst.Get(j).Message(), elem)
+				continue
+			}
+			dst.Append(cloneValue(src.Get(i)))
+		}
+	default:
+		for i := 0; i < src.Len(); i++ {
+			dst.Append(cloneValue(src.Get(i)))
+		}
+	}
+}
+
+func isNamedMessage(md protoreflect.MessageDescriptor) bool {
+	f := md.Fields().ByName("name")
+	return f != nil && f.Kind() == protoreflect.StringKind && !f.IsList()
+}
+
+func listIndexByName(list protoreflect.List, nameField protoreflect.FieldDescriptor, name string) int {
+	if name == "" {
+		return -1
+	}
+	for i := 0; i < list.Len(); i++ {
+		if list.Get(i).Message().Get(nameField).String() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func listHasString(list protoreflect.List, s string) bool {
+	for i := 0; i < list.Len(); i++ {
+		if list.Get(i).String() == s {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneValue(v protoreflect.Value) protoreflect.Value {
+	if m, ok := v.Interface().(protoreflect.Message); ok {
+		return protoreflect.ValueOfMessage(proto.Clone(m.Interface()).ProtoReflect())
+	}
+	return v
+}
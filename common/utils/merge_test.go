@@ -0,0 +1,196 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	thrift "github.com/hertz-contrib/swagger-generate/idl/thrift"
+	"google.golang.org/protobuf/proto"
+)
+
+// generatedSchema returns a schema as the generators build it, which the
+// annotations are merged into.
+func generatedSchema() *thrift.Schema {
+	return &thrift.Schema{
+		Title:     "generated",
+		Nullable:  true,
+		MaxLength: 5,
+		Required:  []string{"a", "b"},
+		Enum:      []*thrift.Any{{Yaml: "x"}},
+		Properties: &thrift.Properties{AdditionalProperties: []*thrift.NamedSchemaOrReference{
+			{Name: "a", Value: &thrift.SchemaOrReference{Schema: &thrift.Schema{Type: "string"}}},
+			{Name: "b", Value: &thrift.SchemaOrReference{Schema: &thrift.Schema{Type: "integer"}}},
+		}},
+	}
+}
+
+func TestMergeOptionValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    func(s *thrift.Schema) // Changes the merge makes to the generated schema.
+		wantErr string
+	}{
+		{name: "empty", value: map[string]interface{}{}, want: func(s *thrift.Schema) {}},
+		{
+			name:  "scalars",
+			value: map[string]interface{}{"title": "t", "nullable": "false", "max_length": 0, "pattern": 12},
+			want: func(s *thrift.Schema) {
+				s.Title, s.Nullable, s.MaxLength, s.Pattern = "t", false, 0, "12"
+			},
+		},
+		{
+			name:  "null clears",
+			value: map[string]interface{}{"title": nil, "required": nil},
+			want:  func(s *thrift.Schema) { s.Title, s.Required = "", nil },
+		},
+		{
+			name:  "strings appended once",
+			value: map[string]interface{}{"required": []interface{}{"b", "c"}},
+			want:  func(s *thrift.Schema) { s.Required = []string{"a", "b", "c"} },
+		},
+		{
+			name:  "$replace",
+			value: map[string]interface{}{"required": map[string]interface{}{"$replace": []interface{}{"c"}}},
+			want:  func(s *thrift.Schema) { s.Required = []string{"c"} },
+		},
+		{
+			name:  "$append",
+			value: map[string]interface{}{"enum": map[string]interface{}{"$append": []interface{}{map[string]interface{}{"yaml": "y"}}}},
+			want:  func(s *thrift.Schema) { s.Enum = append(s.Enum, &thrift.Any{Yaml: "y"}) },
+		},
+		{
+			name:  "Any values replaced",
+			value: map[string]interface{}{"enum": []interface{}{map[string]interface{}{"yaml": "y"}}},
+			want:  func(s *thrift.Schema) { s.Enum = []*thrift.Any{{Yaml: "y"}} },
+		},
+		{
+			name: "named entries merged by name",
+			value: map[string]interface{}{"properties": map[string]interface{}{"additional_properties": []interface{}{
+				map[string]interface{}{"name": "a", "value": map[string]interface{}{"schema": map[string]interface{}{"title": "A"}}},
+				map[string]interface{}{"name": "c", "value": map[string]interface{}{"schema": map[string]interface{}{"type": "boolean"}}},
+			}}},
+			want: func(s *thrift.Schema) {
+				s.Properties.AdditionalProperties[0].Value.Schema.Title = "A"
+				s.Properties.AdditionalProperties = append(s.Properties.AdditionalProperties,
+					&thrift.NamedSchemaOrReference{Name: "c", Value: &thrift.SchemaOrReference{Schema: &thrift.Schema{Type: "boolean"}}})
+			},
+		},
+		{
+			name:    "unknown key",
+			value:   map[string]interface{}{"properties": map[string]interface{}{"a": 1}},
+			wantErr: `properties: unknown key "a"`,
+		},
+		{
+			name:    "type mismatch",
+			value:   map[string]interface{}{"nullable": "maybe"},
+			wantErr: `nullable: expected boolean, got string "maybe"`,
+		},
+		{
+			name:    "not an object",
+			value:   "title",
+			wantErr: `<root>: expected object, got string "title"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generatedSchema()
+			err := MergeOptionValue(got, tt.value)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("MergeOptionValue() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergeOptionValue() error = %v", err)
+			}
+			want := generatedSchema()
+			tt.want(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("MergeOptionValue() = %+v, want %+v", got, want)
+			}
+		})
+	}
+	if err := MergeOptionValue(thrift.Schema{}, map[string]interface{}{}); err == nil {
+		t.Error("MergeOptionValue() into a non-pointer succeeded")
+	}
+}
+
+func TestMergeOptionMessage(t *testing.T) {
+	schema := func(typ string) *openapi.SchemaOrReference {
+		return &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{Type: typ}}}
+	}
+	generated := func() *openapi.Schema {
+		return &openapi.Schema{
+			Title:    "generated",
+			Required: []string{"a"},
+			Enum:     []*openapi.Any{{Yaml: "x"}},
+			Properties: &openapi.Properties{AdditionalProperties: []*openapi.NamedSchemaOrReference{
+				{Name: "a", Value: schema("string")},
+			}},
+		}
+	}
+	tests := []struct {
+		name string
+		src  *openapi.Schema
+		want func(s *openapi.Schema)
+	}{
+		{name: "nil", src: nil, want: func(s *openapi.Schema) {}},
+		{name: "scalar", src: &openapi.Schema{Title: "t"}, want: func(s *openapi.Schema) { s.Title = "t" }},
+		{
+			name: "strings appended once",
+			src:  &openapi.Schema{Required: []string{"a", "b"}},
+			want: func(s *openapi.Schema) { s.Required = []string{"a", "b"} },
+		},
+		{
+			name: "Any values replaced",
+			src:  &openapi.Schema{Enum: []*openapi.Any{{Yaml: "y"}}},
+			want: func(s *openapi.Schema) { s.Enum = []*openapi.Any{{Yaml: "y"}} },
+		},
+		{
+			name: "named entries merged by name",
+			src: &openapi.Schema{Properties: &openapi.Properties{AdditionalProperties: []*openapi.NamedSchemaOrReference{
+				{Name: "a", Value: &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{Title: "A"}}}},
+				{Name: "b", Value: schema("integer")},
+			}}},
+			want: func(s *openapi.Schema) {
+				s.Properties.AdditionalProperties[0].Value.GetSchema().Title = "A"
+				s.Properties.AdditionalProperties = append(s.Properties.AdditionalProperties,
+					&openapi.NamedSchemaOrReference{Name: "b", Value: schema("integer")})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generated()
+			var src proto.Message
+			if tt.src != nil {
+				src = tt.src
+			}
+			MergeOptionMessage(got, src)
+			want := generated()
+			tt.want(want)
+			if !proto.Equal(got, want) {
+				t.Errorf("MergeOptionMessage() = %v, want %v", got, want)
+			}
+		})
+	}
+}
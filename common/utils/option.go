@@ -0,0 +1,391 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+// optionEnums lists the values allowed by the OpenAPI specification for
+// string fields of the openapi model, keyed by "<Type>.<field>".
+var optionEnums = map[string][]string{
+	"Parameter.in":         {"query", "header", "path", "cookie"},
+	"Parameter.style":      {"matrix", "label", "form", "simple", "spaceDelimited", "pipeDelimited", "deepObject"},
+	"Header.style":         {"simple"},
+	"Encoding.style":       {"form", "spaceDelimited", "pipeDelimited", "deepObject"},
+	"Schema.type":          {"string", "number", "integer", "boolean", "array", "object"},
+	"SecurityScheme.type":  {"apiKey", "http", "oauth2", "openIdConnect"},
+	"SecurityScheme._type": {"apiKey", "http", "oauth2", "openIdConnect"},
+	"SecurityScheme.in":    {"query", "header", "cookie"},
+	"SecurityScheme._in":   {"query", "header", "cookie"},
+}
+
+// OptionIssue is a single problem found in an annotation payload.
+type OptionIssue struct {
+	// Path is the dotted path of the offending key, e.g. "items.schema.type".
+	Path    string
+	Message string
+}
+
+func (i *OptionIssue) Error() string {
+	if i.Path == "" {
+		return i.Message
+	}
+	return i.Path + ": " + i.Message
+}
+
+// OptionError lists every problem found in an annotation payload.
+type OptionError struct {
+	Issues []*OptionIssue
+}
+
+func (e *OptionError) Error() string {
+	msgs := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		msgs = append(msgs, issue.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func optionError(issues []*OptionIssue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	return &OptionError{Issues: issues}
+}
+
+// CheckOptionValue validates an annotation payload against the openapi model
+// type obj points to. The payload is a tree of map[string]interface{},
+// []interface{} and scalar leaves, as produced by decoding JSON or the Thrift
+// annotation syntax. String leaves are accepted for numeric and boolean fields
+// if they parse as such, since Thrift annotation values are untyped. Unknown
+// keys, type mismatches and values outside the ones allowed by the OpenAPI
+// specification are reported in an *OptionError.
+func CheckOptionValue(value, obj interface{}) error {
+	var issues []*OptionIssue
+	checkValue(&issues, "", "", value, reflect.TypeOf(obj))
+	return optionError(issues)
+}
+
+func checkValue(issues *[]*OptionIssue, path, enumKey string, value interface{}, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	report := func(format string, v ...interface{}) {
+		*issues = append(*issues, &OptionIssue{Path: path, Message: fmt.Sprintf(format, v...)})
+	}
+	if value == nil {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := toStringMap(value)
+		if !ok {
+			report("expected object, got %s", describeValue(value))
+			return
+		}
+		fields := map[string]reflect.StructField{}
+		var names []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := jsonName(f)
+			if name == "" {
+				continue
+			}
+			fields[name] = f
+			names = append(names, name)
+		}
+		for _, k := range sortedKeys(m) {
+			f, ok := fields[k]
+			if !ok {
+				msg := fmt.Sprintf("unknown key %q", k)
+				if s := Suggest(k, names); s != "" {
+					msg += fmt.Sprintf(", did you mean %q?", s)
+				}
+				*issues = append(*issues, &OptionIssue{Path: path, Message: msg})
+				continue
+			}
+			checkValue(issues, joinPath(path, k), t.Name()+"."+k, m[k], f.Type)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			if _, ok := value.(string); !ok {
+				report("expected string, got %s", describeValue(value))
+			}
+			return
+		}
+		_, value, _ = listDirective(value)
+		if value == nil {
+			return
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			report("expected list, got %s", describeValue(value))
+			return
+		}
+		for i, elem := range list {
+			checkValue(issues, fmt.Sprintf("%s[%d]", path, i), enumKey, elem, t.Elem())
+		}
+
+	case reflect.Map:
+		m, ok := toStringMap(value)
+		if !ok {
+			report("expected object, got %s", describeValue(value))
+			return
+		}
+		for _, k := range sortedKeys(m) {
+			checkValue(issues, joinPath(path, k), "", m[k], t.Elem())
+		}
+
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			report("expected string, got %s", describeValue(value))
+			return
+		}
+		if msg := checkEnum(enumKey, s); msg != "" {
+			report("%s", msg)
+		}
+
+	case reflect.Bool:
+		switch v := value.(type) {
+		case bool:
+		case string:
+			if _, err := strconv.ParseBool(v); err != nil {
+				report("expected boolean, got %s", describeValue(value))
+			}
+		default:
+			report("expected boolean, got %s", describeValue(value))
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if f, ok := toFloat(value); !ok || f != math.Trunc(f) {
+			report("expected integer, got %s", describeValue(value))
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if _, ok := toFloat(value); !ok {
+			report("expected number, got %s", describeValue(value))
+		}
+	}
+}
+
+// CheckOptionMessage validates an openapi extension message. protoc already
+// rejects unknown keys and type mismatches in option literals, so this checks
+// what protoc cannot: string values outside the ones allowed by the OpenAPI
+// specification and `yaml` payloads of Any values that are not valid YAML.
+func CheckOptionMessage(m proto.Message) error {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return nil
+	}
+	var issues []*OptionIssue
+	checkMessage(&issues, "", m.ProtoReflect())
+	return optionError(issues)
+}
+
+func checkMessage(issues *[]*OptionIssue, path string, m protoreflect.Message) {
+	desc := m.Descriptor()
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		fieldPath := joinPath(path, string(fd.Name()))
+		enumKey := string(desc.Name()) + "." + string(fd.Name())
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				checkField(issues, fmt.Sprintf("%s[%d]", fieldPath, i), enumKey, fd, list.Get(i))
+			}
+		case fd.IsMap():
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				checkField(issues, joinPath(fieldPath, k.String()), "", fd.MapValue(), mv)
+				return true
+			})
+		default:
+			checkField(issues, fieldPath, enumKey, fd, v)
+		}
+		return true
+	})
+}
+
+func checkField(issues *[]*OptionIssue, path, enumKey string, fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		checkMessage(issues, path, v.Message())
+	case protoreflect.StringKind:
+		s := v.String()
+		if msg := checkEnum(enumKey, s); msg != "" {
+			*issues = append(*issues, &OptionIssue{Path: path, Message: msg})
+		}
+		if enumKey == "Any.yaml" {
+			var node yaml.Node
+			if err := yaml.Unmarshal([]byte(s), &node); err != nil {
+				*issues = append(*issues, &OptionIssue{Path: path, Message: fmt.Sprintf("invalid yaml: %s", err)})
+			}
+		}
+	}
+}
+
+func checkEnum(enumKey, value string) string {
+	allowed, ok := optionEnums[enumKey]
+	if !ok || value == "" || Contains(allowed, value) {
+		return ""
+	}
+	msg := fmt.Sprintf("invalid value %q, expected one of %s", value, strings.Join(allowed, ", "))
+	if s := Suggest(value, allowed); s != "" {
+		msg += fmt.Sprintf(", did you mean %q?", s)
+	}
+	return msg
+}
+
+// Suggest returns the candidate closest to name, or "" if none is close
+// enough to be a likely misspelling. Candidates that only differ in case,
+// underscores or dashes always match, so "maxLength" suggests "max_length".
+func Suggest(name string, candidates []string) string {
+	normalize := func(s string) string {
+		return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(s))
+	}
+	best, bestDist := "", -1
+	for _, c := range candidates {
+		if normalize(c) == normalize(name) {
+			return c
+		}
+		d := editDistance(normalize(name), normalize(c))
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	if bestDist >= 0 && (bestDist <= 2 || bestDist <= len(name)/3) {
+		return best
+	}
+	return ""
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func jsonName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toStringMap(value interface{}) (map[string]interface{}, bool) {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			out[fmt.Sprint(k)] = v
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	case bool, nil:
+		return 0, false
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func describeValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case bool:
+		return fmt.Sprintf("boolean %v", v)
+	case []interface{}:
+		return "list"
+	case map[string]interface{}, map[interface{}]interface{}:
+		return "object"
+	}
+	if _, ok := toFloat(value); ok {
+		return fmt.Sprintf("number %v", value)
+	}
+	return fmt.Sprintf("%T", value)
+}
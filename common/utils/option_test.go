@@ -0,0 +1,158 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	thrift "github.com/hertz-contrib/swagger-generate/idl/thrift"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCheckOptionValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		obj   interface{}
+		want  string
+	}{
+		{name: "nil", value: nil, obj: &thrift.Schema{}},
+		{
+			name:  "valid schema",
+			value: map[string]interface{}{"title": "t", "max_length": "10", "nullable": "true", "type": "string", "required": []interface{}{"a"}},
+			obj:   &thrift.Schema{},
+		},
+		{
+			name:  "unknown key with suggestion",
+			value: map[string]interface{}{"maxLength": 10},
+			obj:   &thrift.Schema{},
+			want:  `unknown key "maxLength", did you mean "max_length"?`,
+		},
+		{
+			name:  "unknown key without suggestion",
+			value: map[string]interface{}{"zzzzzzzz": 1},
+			obj:   &thrift.Schema{},
+			want:  `unknown key "zzzzzzzz"`,
+		},
+		{
+			name:  "invalid enum value",
+			value: map[string]interface{}{"in": "qeury"},
+			obj:   &thrift.Parameter{},
+			want:  `in: invalid value "qeury", expected one of query, header, path, cookie, did you mean "query"?`,
+		},
+		{
+			name:  "type mismatches",
+			value: map[string]interface{}{"max_length": 1.5, "nullable": "yes", "minimum": "low", "title": 3, "required": "a"},
+			obj:   &thrift.Schema{},
+			want: `max_length: expected integer, got number 1.5; minimum: expected number, got string "low"; ` +
+				`nullable: expected boolean, got string "yes"; required: expected list, got string "a"; title: expected string, got number 3`,
+		},
+		{
+			name:  "nested paths",
+			value: map[string]interface{}{"items": map[string]interface{}{"schema_or_reference": []interface{}{map[string]interface{}{"schema": map[string]interface{}{"type": "strnig"}}}}},
+			obj:   &thrift.Schema{},
+			want:  `items.schema_or_reference[0].schema.type: invalid value "strnig", expected one of string, number, integer, boolean, array, object, did you mean "string"?`,
+		},
+		{
+			name:  "list directive",
+			value: map[string]interface{}{"required": map[string]interface{}{"$append": []interface{}{"a"}}},
+			obj:   &thrift.Schema{},
+		},
+		{
+			name:  "not an object",
+			value: []interface{}{1},
+			obj:   &thrift.Schema{},
+			want:  "expected object, got list",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckOptionValue(tt.value, tt.obj)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("CheckOptionValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckOptionMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		m    proto.Message
+		want string
+	}{
+		{name: "nil", m: nil},
+		{name: "valid", m: &openapi.Parameter{In: "query", Schema: &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{Type: "string"}}}}},
+		{
+			name: "invalid enum value",
+			m:    &openapi.Parameter{In: "body"},
+			want: `in: invalid value "body", expected one of query, header, path, cookie`,
+		},
+		{
+			name: "nested invalid enum value",
+			m:    &openapi.Parameter{Schema: &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{Type: "int"}}}},
+			want: `schema.schema.type: invalid value "int", expected one of string, number, integer, boolean, array, object`,
+		},
+		{
+			name: "invalid yaml",
+			m:    &openapi.Schema{Example: &openapi.Any{Yaml: "a: [b"}},
+			want: "example.yaml: invalid yaml: yaml: line 1: did not find expected ',' or ']'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckOptionMessage(tt.m)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("CheckOptionMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"max_length", "min_length", "title", "description"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "maxLength", want: "max_length"},
+		{name: "MAX-LENGTH", want: "max_length"},
+		{name: "titel", want: "title"},
+		{name: "descriptoin", want: "description"},
+		{name: "format", want: ""},
+		{name: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Suggest(tt.name, candidates); got != tt.want {
+				t.Errorf("Suggest(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+	if got := Suggest("title", nil); got != "" {
+		t.Errorf("Suggest() without candidates = %q", got)
+	}
+}
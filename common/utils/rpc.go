@@ -0,0 +1,48 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"strings"
+
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+)
+
+// IsPathStyle reports whether style is a supported path style of RPC methods.
+func IsPathStyle(style string) bool {
+	return style == consts.PathStyleService || style == consts.PathStyleMethod
+}
+
+// IsMetainfoStyle reports whether style is a supported way of carrying the
+// metainfo of RPC methods.
+func IsMetainfoStyle(style string) bool {
+	return style == consts.MetainfoStyleHeader || style == consts.MetainfoStyleQuery
+}
+
+// RPCPath returns the path of the RPC method of service in the path style.
+func RPCPath(style, service, method string) string {
+	if style == consts.PathStyleMethod {
+		return "/" + method
+	}
+	return "/" + service + "/" + method
+}
+
+// RawString returns s as a Go raw string literal, splicing in the back quotes
+// that a raw string can not hold.
+func RawString(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "` + \"`\" + `") + "`"
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+)
+
+// StreamingTryItOutNote explains in the description of a streaming operation
+// why it is left out of "Try it out".
+const StreamingTryItOutNote = `This is a streaming method. It can not be called with "Try it out", which only sends and receives a single message.`
+
+// StreamingMode returns the value of the `x-streaming` extension of a method
+// with the given streaming sides, or "" for unary methods.
+func StreamingMode(clientStreaming, serverStreaming bool) string {
+	switch {
+	case clientStreaming && serverStreaming:
+		return consts.StreamingBidi
+	case clientStreaming:
+		return consts.StreamingClient
+	case serverStreaming:
+		return consts.StreamingServer
+	}
+	return ""
+}
+
+// ThriftStreamingMode returns the value of the `x-streaming` extension for the
+// Kitex `streaming.mode` annotation of a Thrift method.
+func ThriftStreamingMode(mode string) string {
+	switch mode {
+	case "bidirectional":
+		return consts.StreamingBidi
+	case "client":
+		return consts.StreamingClient
+	case "server":
+		return consts.StreamingServer
+	}
+	return ""
+}
+
+// IsStreamContentType reports whether contentType can document a stream of
+// messages.
+func IsStreamContentType(contentType string) bool {
+	return contentType == consts.ContentTypeNDJSON || contentType == consts.ContentTypeEventStream
+}
+
+// StreamBodyDescription describes a body of contentType that holds a stream
+// of messages, each of which follows the schema of the media type.
+func StreamBodyDescription(contentType string) string {
+	if contentType == consts.ContentTypeEventStream {
+		return "A stream of server-sent events, each carrying one JSON encoded message in its `data` field."
+	}
+	return "A stream of JSON encoded messages, one per line."
+}
+
+// AppendParagraph appends paragraph to text, separated by a blank line.
+func AppendParagraph(text, paragraph string) string {
+	if text == "" {
+		return paragraph
+	}
+	return text + "\n\n" + paragraph
+}
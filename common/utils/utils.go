@@ -124,6 +124,9 @@ func MapForm(input []string) (map[string][]string, error) {
 }
 
 // MergeStructs merges non-zero fields from src into dst.
+//
+// Deprecated: use MergeOptionValue, which recurses into nested objects and
+// lists and honours explicit null and zero values.
 func MergeStructs(dst, src interface{}) error {
 	dstVal := reflect.ValueOf(dst)
 	srcVal := reflect.ValueOf(src)
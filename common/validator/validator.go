@@ -0,0 +1,621 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package validator checks a generated OpenAPI 3.0 document before it is
+// written. It works on the serialized YAML rather than on the openapi models,
+// so that the Protobuf and Thrift plugins share the same rules and diagnostics
+// point at the line of openapi.yaml that holds the problem.
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+	parameterIns     = []string{"query", "header", "path", "cookie"}
+	schemaTypes      = []string{"string", "number", "integer", "boolean", "array", "object"}
+	componentKinds   = []string{"schemas", "responses", "parameters", "examples", "requestBodies", "headers", "securitySchemes", "links", "callbacks"}
+
+	componentNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9.\-_]+$`)
+	responseCodeRegexp  = regexp.MustCompile(`^[1-5](?:[0-9]{2}|XX)$`)
+	pathTemplateRegexp  = regexp.MustCompile(`\{([^{}]*)\}`)
+)
+
+// fixedFields lists the fields allowed in the objects the validator walks.
+// Specification extensions ("x-...") are always allowed.
+var fixedFields = map[string][]string{
+	"OpenAPI":     {"openapi", "info", "servers", "paths", "components", "security", "tags", "externalDocs"},
+	"Info":        {"title", "description", "termsOfService", "contact", "license", "version"},
+	"PathItem":    {"$ref", "summary", "description", "get", "put", "post", "delete", "options", "head", "patch", "trace", "servers", "parameters"},
+	"Operation":   {"tags", "summary", "description", "externalDocs", "operationId", "parameters", "requestBody", "responses", "callbacks", "deprecated", "security", "servers"},
+	"Parameter":   {"name", "in", "description", "required", "deprecated", "allowEmptyValue", "style", "explode", "allowReserved", "schema", "example", "examples", "content"},
+	"RequestBody": {"description", "content", "required"},
+	"Response":    {"description", "headers", "content", "links"},
+	"Components":  componentKinds,
+}
+
+// Validate parses the serialized document and reports every violation of the
+// OpenAPI 3.0 structural rules and of reference integrity to diag. Locations
+// carry filename and the position of the offending node, and the JSON pointer
+// of that node as owner.
+func Validate(filename string, content []byte, diag *diagnostics.Collector) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		diag.ErrorfAt(diagnostics.Location{Filename: filename}, "generated document is not valid YAML: %s", err)
+		return
+	}
+	if len(doc.Content) == 0 {
+		diag.ErrorfAt(diagnostics.Location{Filename: filename}, "generated document is empty")
+		return
+	}
+	v := &validator{
+		filename:     filename,
+		diag:         diag,
+		root:         doc.Content[0],
+		operationIDs: map[string]string{},
+	}
+	v.validateDocument()
+}
+
+type validator struct {
+	filename     string
+	diag         *diagnostics.Collector
+	root         *yaml.Node
+	operationIDs map[string]string // operationId -> pointer of the first operation using it
+}
+
+func (v *validator) errorf(n *yaml.Node, pointer, format string, args ...interface{}) {
+	v.diag.ErrorfAt(v.location(n, pointer), format, args...)
+}
+
+func (v *validator) warnf(n *yaml.Node, pointer, format string, args ...interface{}) {
+	v.diag.WarnfAt(v.location(n, pointer), format, args...)
+}
+
+func (v *validator) location(n *yaml.Node, pointer string) diagnostics.Location {
+	loc := diagnostics.Location{Filename: v.filename, Owner: "#" + pointer}
+	if n != nil {
+		loc.Line = n.Line
+		loc.Column = n.Column
+	}
+	return loc
+}
+
+func (v *validator) validateDocument() {
+	root := v.root
+	if !v.expectMapping(root, "") {
+		return
+	}
+	v.checkFields(root, "", "OpenAPI")
+
+	if version := lookup(root, "openapi"); version == nil {
+		v.errorf(root, "", "missing required field %q", "openapi")
+	} else if !strings.HasPrefix(version.Value, "3.0.") {
+		v.errorf(version, "/openapi", "unsupported OpenAPI version %q, expected 3.0.x", version.Value)
+	}
+
+	if info := lookup(root, "info"); info == nil {
+		v.errorf(root, "", "missing required field %q", "info")
+	} else if v.expectMapping(info, "/info") {
+		v.checkFields(info, "/info", "Info")
+		v.requireFields(info, "/info", "title", "version")
+	}
+
+	v.validateTags(lookup(root, "tags"))
+	v.validateComponents(lookup(root, "components"))
+	v.validateSecurity(lookup(root, "security"), "/security")
+
+	if paths := lookup(root, "paths"); paths == nil {
+		v.errorf(root, "", "missing required field %q", "paths")
+	} else {
+		v.validatePaths(paths)
+	}
+
+	v.validateRefs(root, "")
+}
+
+func (v *validator) validateTags(tags *yaml.Node) {
+	if tags == nil || !v.expectSequence(tags, "/tags") {
+		return
+	}
+	seen := map[string]bool{}
+	for i, tag := range tags.Content {
+		pointer := fmt.Sprintf("/tags/%d", i)
+		if !v.expectMapping(tag, pointer) {
+			continue
+		}
+		name := lookup(tag, "name")
+		if name == nil {
+			v.errorf(tag, pointer, "missing required field %q", "name")
+			continue
+		}
+		if seen[name.Value] {
+			v.errorf(name, pointer+"/name", "duplicate tag %q", name.Value)
+		}
+		seen[name.Value] = true
+	}
+}
+
+func (v *validator) validateComponents(components *yaml.Node) {
+	if components == nil || !v.expectMapping(components, "/components") {
+		return
+	}
+	v.checkFields(components, "/components", "Components")
+	forEach(components, func(kind string, _, group *yaml.Node) {
+		pointer := "/components/" + escape(kind)
+		if strings.HasPrefix(kind, "x-") || !v.expectMapping(group, pointer) {
+			return
+		}
+		forEach(group, func(name string, key, value *yaml.Node) {
+			itemPointer := pointer + "/" + escape(name)
+			if !componentNameRegexp.MatchString(name) {
+				v.errorf(key, itemPointer, "invalid component name %q, names must match %s", name, componentNameRegexp.String())
+			}
+			switch kind {
+			case "schemas":
+				v.validateSchema(value, itemPointer)
+			case "parameters":
+				v.validateParameter(value, itemPointer)
+			case "requestBodies":
+				v.validateRequestBody(value, itemPointer)
+			case "responses":
+				v.validateResponse(value, itemPointer)
+			}
+		})
+	})
+}
+
+func (v *validator) validateSecurity(security *yaml.Node, pointer string) {
+	if security == nil || !v.expectSequence(security, pointer) {
+		return
+	}
+	schemes := lookupPath(v.root, "components", "securitySchemes")
+	for i, requirement := range security.Content {
+		itemPointer := fmt.Sprintf("%s/%d", pointer, i)
+		if !v.expectMapping(requirement, itemPointer) {
+			continue
+		}
+		forEach(requirement, func(name string, key, _ *yaml.Node) {
+			if lookup(schemes, name) == nil {
+				v.errorf(key, itemPointer+"/"+escape(name), "security requirement references undefined security scheme %q", name)
+			}
+		})
+	}
+}
+
+func (v *validator) validatePaths(paths *yaml.Node) {
+	if !v.expectMapping(paths, "/paths") {
+		return
+	}
+	templates := map[string]string{}
+	forEach(paths, func(path string, key, item *yaml.Node) {
+		if strings.HasPrefix(path, "x-") {
+			return
+		}
+		pointer := "/paths/" + escape(path)
+		if !strings.HasPrefix(path, "/") {
+			v.errorf(key, pointer, "path %q must begin with a slash", path)
+		}
+		// Paths that only differ in the names of their template parameters
+		// are identical for the specification.
+		template := pathTemplateRegexp.ReplaceAllString(path, "{}")
+		if other, ok := templates[template]; ok {
+			v.errorf(key, pointer, "path %q is equivalent to %q", path, other)
+		} else {
+			templates[template] = path
+		}
+		v.validatePathItem(path, item, pointer)
+	})
+}
+
+func (v *validator) validatePathItem(path string, item *yaml.Node, pointer string) {
+	if !v.expectMapping(item, pointer) {
+		return
+	}
+	v.checkFields(item, pointer, "PathItem")
+	if lookup(item, "$ref") != nil {
+		return
+	}
+
+	var segments []string
+	for _, m := range pathTemplateRegexp.FindAllStringSubmatch(path, -1) {
+		segments = append(segments, m[1])
+	}
+
+	common := v.validateParameters(lookup(item, "parameters"), pointer+"/parameters")
+	for _, method := range operationMethods {
+		op := lookup(item, method)
+		if op == nil {
+			continue
+		}
+		opPointer := pointer + "/" + method
+		params := v.validateOperation(op, opPointer)
+
+		// Operation parameters override the path level ones with the same
+		// name and location.
+		pathParams := map[string]*yaml.Node{}
+		for _, p := range append(common, params...) {
+			if p.in == "path" {
+				pathParams[p.name] = p.node
+			}
+		}
+		for _, name := range segments {
+			if _, ok := pathParams[name]; !ok {
+				v.errorf(op, opPointer, "path segment {%s} has no matching path parameter", name)
+			}
+			delete(pathParams, name)
+		}
+		for _, name := range sortedNodeKeys(pathParams) {
+			v.errorf(pathParams[name], opPointer, "path parameter %q has no matching {%s} segment in %q", name, name, path)
+		}
+	}
+}
+
+func (v *validator) validateOperation(op *yaml.Node, pointer string) []*parameter {
+	if !v.expectMapping(op, pointer) {
+		return nil
+	}
+	v.checkFields(op, pointer, "Operation")
+
+	if id := lookup(op, "operationId"); id != nil {
+		if other, ok := v.operationIDs[id.Value]; ok {
+			v.errorf(id, pointer+"/operationId", "duplicate operationId %q, already used by #%s", id.Value, other)
+		} else {
+			v.operationIDs[id.Value] = pointer
+		}
+	}
+
+	params := v.validateParameters(lookup(op, "parameters"), pointer+"/parameters")
+	if body := lookup(op, "requestBody"); body != nil {
+		v.validateRequestBody(body, pointer+"/requestBody")
+	}
+	v.validateSecurity(lookup(op, "security"), pointer+"/security")
+
+	responses := lookup(op, "responses")
+	if responses == nil {
+		v.errorf(op, pointer, "missing required field %q", "responses")
+		return params
+	}
+	if !v.expectMapping(responses, pointer+"/responses") {
+		return params
+	}
+	if len(responses.Content) == 0 {
+		v.errorf(responses, pointer+"/responses", "responses must contain at least one response code")
+	}
+	forEach(responses, func(code string, key, response *yaml.Node) {
+		if strings.HasPrefix(code, "x-") {
+			return
+		}
+		responsePointer := pointer + "/responses/" + escape(code)
+		if code != "default" && !responseCodeRegexp.MatchString(code) {
+			v.errorf(key, responsePointer, "invalid response code %q", code)
+		}
+		v.validateResponse(response, responsePointer)
+	})
+	return params
+}
+
+type parameter struct {
+	name string
+	in   string
+	node *yaml.Node
+}
+
+// validateParameters checks a list of parameters and returns the ones whose
+// name and location are known, resolving local references.
+func (v *validator) validateParameters(list *yaml.Node, pointer string) []*parameter {
+	if list == nil || !v.expectSequence(list, pointer) {
+		return nil
+	}
+	var params []*parameter
+	seen := map[string]bool{}
+	for i, node := range list.Content {
+		itemPointer := fmt.Sprintf("%s/%d", pointer, i)
+		resolved := node
+		if ref := lookup(node, "$ref"); ref != nil {
+			resolved = v.resolve(ref.Value)
+			if resolved == nil {
+				// Reported by validateRefs.
+				continue
+			}
+		} else {
+			v.validateParameter(node, itemPointer)
+		}
+		name, in := lookup(resolved, "name"), lookup(resolved, "in")
+		if name == nil || in == nil {
+			continue
+		}
+		key := in.Value + ":" + name.Value
+		if seen[key] {
+			v.errorf(node, itemPointer, "duplicate %s parameter %q", in.Value, name.Value)
+		}
+		seen[key] = true
+		params = append(params, &parameter{name: name.Value, in: in.Value, node: node})
+	}
+	return params
+}
+
+func (v *validator) validateParameter(param *yaml.Node, pointer string) {
+	if !v.expectMapping(param, pointer) || lookup(param, "$ref") != nil {
+		return
+	}
+	v.checkFields(param, pointer, "Parameter")
+	v.requireFields(param, pointer, "name", "in")
+	if in := lookup(param, "in"); in != nil {
+		if !contains(parameterIns, in.Value) {
+			v.errorf(in, pointer+"/in", "invalid parameter location %q, expected one of %s", in.Value, strings.Join(parameterIns, ", "))
+		} else if in.Value == "path" {
+			if required := lookup(param, "required"); required == nil || required.Value != "true" {
+				v.errorf(param, pointer, "path parameters must be required")
+			}
+		}
+	}
+	schema, content := lookup(param, "schema"), lookup(param, "content")
+	switch {
+	case schema != nil && content != nil:
+		v.errorf(param, pointer, "a parameter must contain either a schema or a content, not both")
+	case schema == nil && content == nil:
+		v.errorf(param, pointer, "a parameter must contain either a schema or a content")
+	case schema != nil:
+		v.validateSchema(schema, pointer+"/schema")
+	default:
+		v.validateContent(content, pointer+"/content")
+		if len(content.Content) != 2 {
+			v.errorf(content, pointer+"/content", "the content of a parameter must contain exactly one entry")
+		}
+	}
+}
+
+func (v *validator) validateRequestBody(body *yaml.Node, pointer string) {
+	if !v.expectMapping(body, pointer) || lookup(body, "$ref") != nil {
+		return
+	}
+	v.checkFields(body, pointer, "RequestBody")
+	if content := lookup(body, "content"); content == nil {
+		v.errorf(body, pointer, "missing required field %q", "content")
+	} else {
+		v.validateContent(content, pointer+"/content")
+	}
+}
+
+func (v *validator) validateResponse(response *yaml.Node, pointer string) {
+	if !v.expectMapping(response, pointer) || lookup(response, "$ref") != nil {
+		return
+	}
+	v.checkFields(response, pointer, "Response")
+	v.requireFields(response, pointer, "description")
+	if content := lookup(response, "content"); content != nil {
+		v.validateContent(content, pointer+"/content")
+	}
+	if headers := lookup(response, "headers"); headers != nil && v.expectMapping(headers, pointer+"/headers") {
+		forEach(headers, func(name string, _, header *yaml.Node) {
+			if schema := lookup(header, "schema"); schema != nil {
+				v.validateSchema(schema, pointer+"/headers/"+escape(name)+"/schema")
+			}
+		})
+	}
+}
+
+func (v *validator) validateContent(content *yaml.Node, pointer string) {
+	if !v.expectMapping(content, pointer) {
+		return
+	}
+	forEach(content, func(mediaType string, _, media *yaml.Node) {
+		mediaPointer := pointer + "/" + escape(mediaType)
+		if !v.expectMapping(media, mediaPointer) {
+			return
+		}
+		if schema := lookup(media, "schema"); schema != nil {
+			v.validateSchema(schema, mediaPointer+"/schema")
+		}
+	})
+}
+
+func (v *validator) validateSchema(schema *yaml.Node, pointer string) {
+	if !v.expectMapping(schema, pointer) || lookup(schema, "$ref") != nil {
+		return
+	}
+	typ := lookup(schema, "type")
+	if typ != nil && !contains(schemaTypes, typ.Value) {
+		v.errorf(typ, pointer+"/type", "invalid schema type %q, expected one of %s", typ.Value, strings.Join(schemaTypes, ", "))
+	}
+	if typ != nil && typ.Value == "array" && lookup(schema, "items") == nil {
+		v.errorf(schema, pointer, "schemas of type array must define items")
+	}
+
+	properties := lookup(schema, "properties")
+	if required := lookup(schema, "required"); required != nil && v.expectSequence(required, pointer+"/required") {
+		if properties != nil && lookup(schema, "allOf") == nil && lookup(schema, "additionalProperties") == nil {
+			for i, name := range required.Content {
+				if lookup(properties, name.Value) == nil {
+					v.warnf(name, fmt.Sprintf("%s/required/%d", pointer, i), "required property %q is not defined in properties", name.Value)
+				}
+			}
+		}
+	}
+
+	if properties != nil && v.expectMapping(properties, pointer+"/properties") {
+		forEach(properties, func(name string, _, property *yaml.Node) {
+			v.validateSchema(property, pointer+"/properties/"+escape(name))
+		})
+	}
+	if items := lookup(schema, "items"); items != nil {
+		v.validateSchema(items, pointer+"/items")
+	}
+	if additional := lookup(schema, "additionalProperties"); additional != nil && additional.Kind == yaml.MappingNode {
+		v.validateSchema(additional, pointer+"/additionalProperties")
+	}
+	if not := lookup(schema, "not"); not != nil {
+		v.validateSchema(not, pointer+"/not")
+	}
+	for _, composition := range []string{"allOf", "anyOf", "oneOf"} {
+		list := lookup(schema, composition)
+		if list == nil || !v.expectSequence(list, pointer+"/"+composition) {
+			continue
+		}
+		for i, s := range list.Content {
+			v.validateSchema(s, fmt.Sprintf("%s/%s/%d", pointer, composition, i))
+		}
+	}
+}
+
+// validateRefs reports every local $ref of the document that does not resolve.
+// References to other documents are not followed.
+func (v *validator) validateRefs(n *yaml.Node, pointer string) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		forEach(n, func(key string, _, value *yaml.Node) {
+			childPointer := pointer + "/" + escape(key)
+			if key == "$ref" && value.Kind == yaml.ScalarNode {
+				if strings.HasPrefix(value.Value, "#") && v.resolve(value.Value) == nil {
+					v.errorf(value, childPointer, "dangling reference %q", value.Value)
+				}
+				return
+			}
+			v.validateRefs(value, childPointer)
+		})
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			v.validateRefs(item, fmt.Sprintf("%s/%d", pointer, i))
+		}
+	}
+}
+
+// resolve follows a local reference such as "#/components/schemas/Foo".
+func (v *validator) resolve(ref string) *yaml.Node {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	n := v.root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = unescape(token)
+		switch n.Kind {
+		case yaml.MappingNode:
+			n = lookup(n, token)
+		case yaml.SequenceNode:
+			var i int
+			if _, err := fmt.Sscanf(token, "%d", &i); err != nil || i < 0 || i >= len(n.Content) {
+				return nil
+			}
+			n = n.Content[i]
+		default:
+			return nil
+		}
+		if n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// checkFields reports the fields of n that are not allowed in objects of the
+// given kind.
+func (v *validator) checkFields(n *yaml.Node, pointer, kind string) {
+	allowed := fixedFields[kind]
+	forEach(n, func(key string, keyNode, _ *yaml.Node) {
+		if !strings.HasPrefix(key, "x-") && !contains(allowed, key) {
+			v.errorf(keyNode, pointer+"/"+escape(key), "unknown field %q in %s object", key, kind)
+		}
+	})
+}
+
+func (v *validator) requireFields(n *yaml.Node, pointer string, fields ...string) {
+	for _, field := range fields {
+		if value := lookup(n, field); value == nil || (value.Kind == yaml.ScalarNode && value.Value == "") {
+			v.errorf(n, pointer, "missing required field %q", field)
+		}
+	}
+}
+
+func (v *validator) expectMapping(n *yaml.Node, pointer string) bool {
+	if n.Kind == yaml.MappingNode {
+		return true
+	}
+	v.errorf(n, pointer, "expected an object")
+	return false
+}
+
+func (v *validator) expectSequence(n *yaml.Node, pointer string) bool {
+	if n.Kind == yaml.SequenceNode {
+		return true
+	}
+	v.errorf(n, pointer, "expected a list")
+	return false
+}
+
+// lookup returns the value of key in the mapping n, or nil.
+func lookup(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func lookupPath(n *yaml.Node, keys ...string) *yaml.Node {
+	for _, key := range keys {
+		n = lookup(n, key)
+	}
+	return n
+}
+
+// forEach calls fn for every entry of the mapping n, in document order.
+func forEach(n *yaml.Node, fn func(key string, keyNode, value *yaml.Node)) {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		fn(n.Content[i].Value, n.Content[i], n.Content[i+1])
+	}
+}
+
+func sortedNodeKeys(m map[string]*yaml.Node) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// escape encodes a JSON pointer reference token.
+func escape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func unescape(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+)
+
+const validDocument = `openapi: 3.0.3
+info:
+    title: API
+    version: 0.0.1
+tags:
+    - name: Hello
+paths:
+    /hello/{id}:
+        get:
+            tags:
+                - Hello
+            operationId: Hello_Get
+            parameters:
+                - name: id
+                  in: path
+                  required: true
+                  schema:
+                    type: string
+            responses:
+                "200":
+                    description: OK
+                    content:
+                        application/json:
+                            schema:
+                                $ref: '#/components/schemas/HelloResp'
+components:
+    schemas:
+        HelloResp:
+            type: object
+            required:
+                - message
+            properties:
+                message:
+                    type: string
+`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "valid", content: validDocument},
+		{name: "empty", content: ``, want: []string{"openapi.yaml: error: generated document is empty"}},
+		{name: "invalid YAML", content: "a: [b", want: []string{"openapi.yaml: error: generated document is not valid YAML: yaml: line 1: did not find expected ',' or ']'"}},
+		{
+			name:    "root fields",
+			content: "openapi: 2.0.0\ninfo:\n    title: API\nx-extra: 1\nextra: 1\n",
+			want: []string{
+				`openapi.yaml:5:1: error: #/extra: unknown field "extra" in OpenAPI object`,
+				`openapi.yaml:1:10: error: #/openapi: unsupported OpenAPI version "2.0.0", expected 3.0.x`,
+				`openapi.yaml:3:5: error: #/info: missing required field "version"`,
+				`openapi.yaml:1:1: error: #: missing required field "paths"`,
+			},
+		},
+		{
+			name: "paths",
+			content: `openapi: 3.0.3
+info: {title: API, version: "1"}
+paths:
+    hello: {}
+    /a/{id}:
+        get:
+            operationId: Op
+            parameters:
+                - {name: other, in: path, required: true, schema: {type: string}}
+                - {name: q, in: body, schema: {type: string}}
+            responses:
+                "600": {description: bad}
+    /a/{name}:
+        post:
+            operationId: Op
+            responses: {}
+`,
+			want: []string{
+				`openapi.yaml:4:5: error: #/paths/hello: path "hello" must begin with a slash`,
+				`openapi.yaml:10:33: error: #/paths/~1a~1{id}/get/parameters/1/in: invalid parameter location "body", expected one of query, header, path, cookie`,
+				`openapi.yaml:12:17: error: #/paths/~1a~1{id}/get/responses/600: invalid response code "600"`,
+				`openapi.yaml:7:13: error: #/paths/~1a~1{id}/get: path segment {id} has no matching path parameter`,
+				`openapi.yaml:9:19: error: #/paths/~1a~1{id}/get: path parameter "other" has no matching {other} segment in "/a/{id}"`,
+				`openapi.yaml:13:5: error: #/paths/~1a~1{name}: path "/a/{name}" is equivalent to "/a/{id}"`,
+				`openapi.yaml:15:26: error: #/paths/~1a~1{name}/post/operationId: duplicate operationId "Op", already used by #/paths/~1a~1{id}/get`,
+				`openapi.yaml:16:24: error: #/paths/~1a~1{name}/post/responses: responses must contain at least one response code`,
+				`openapi.yaml:15:13: error: #/paths/~1a~1{name}/post: path segment {name} has no matching path parameter`,
+			},
+		},
+		{
+			name: "schemas",
+			content: `openapi: 3.0.3
+info: {title: API, version: "1"}
+paths: {}
+components:
+    schemas:
+        bad name: {type: object}
+        List: {type: array}
+        Typo: {type: strnig}
+        Missing:
+            type: object
+            required: [a]
+            properties:
+                b: {$ref: '#/components/schemas/Nowhere'}
+`,
+			want: []string{
+				`openapi.yaml:6:9: error: #/components/schemas/bad name: invalid component name "bad name", names must match ^[a-zA-Z0-9.\-_]+$`,
+				`openapi.yaml:7:15: error: #/components/schemas/List: schemas of type array must define items`,
+				`openapi.yaml:8:22: error: #/components/schemas/Typo/type: invalid schema type "strnig", expected one of string, number, integer, boolean, array, object`,
+				`openapi.yaml:11:24: warning: #/components/schemas/Missing/required/0: required property "a" is not defined in properties`,
+				`openapi.yaml:13:27: error: #/components/schemas/Missing/properties/b/$ref: dangling reference "#/components/schemas/Nowhere"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diag := diagnostics.NewCollector()
+			Validate("openapi.yaml", []byte(tt.content), diag)
+			var got []string
+			for _, d := range diag.Diagnostics() {
+				got = append(got, d.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() =\n%s\nwant\n%s", join(got), join(tt.want))
+			}
+		})
+	}
+}
+
+func join(lines []string) string {
+	return strings.Join(lines, "\n")
+}
+
+func TestValidateFailsGeneration(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantErr  bool
+		wantWarn int
+	}{
+		{name: "valid", content: validDocument},
+		{name: "warnings only", content: strings.Replace(validDocument, "- message", "- missing", 1), wantWarn: 1},
+		{name: "dangling reference", content: strings.Replace(validDocument, "schemas/HelloResp'", "schemas/Nowhere'", 1), wantErr: true},
+		{name: "not YAML", content: "\t", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diag := diagnostics.NewCollector()
+			Validate("openapi.yaml", []byte(tt.content), diag)
+			if err := diag.Err(); (err != nil) != tt.wantErr {
+				t.Errorf("Err() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := diag.Count(diagnostics.SeverityWarning); got != tt.wantWarn {
+				t.Errorf("Count(warning) = %d, want %d", got, tt.wantWarn)
+			}
+		})
+	}
+}
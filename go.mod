@@ -4,6 +4,7 @@ go 1.18
 
 require (
 	github.com/apache/thrift v0.13.0
+	github.com/cloudwego/thriftgo v0.3.15
 	github.com/google/gnostic-models v0.6.8
 	google.golang.org/protobuf v1.34.2
 	gopkg.in/yaml.v3 v3.0.1
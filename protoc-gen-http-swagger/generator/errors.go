@@ -0,0 +1,187 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/pbgen"
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	wk "github.com/hertz-contrib/swagger-generate/protoc-gen-http-swagger/generator/wellknown"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	errorSchemaSuffix   = "Error"
+	googleRpcStatusName = "google.rpc.Status"
+)
+
+// addErrorResponses adds the error responses declared by the error enum of
+// method, set by `openapi.error_enum` on the method or
+// `openapi.service_error_enum` on its service. Every distinct
+// `(api.http_code)` of the enum values becomes one response, whose body is
+// the shared error schema of the enum and whose description lists the values
+// that map to the code.
+func (g *OpenAPIGenerator) addErrorResponses(d *openapi.Document, service *protogen.Service, method *protogen.Method, op *openapi.Operation) {
+	enum := pbgen.ErrorEnum(g.plugin.Files, service, method, g.diag)
+	if enum == nil {
+		return
+	}
+
+	codes := pbgen.ErrorCodes(enum, g.diag)
+	if len(codes) == 0 {
+		return
+	}
+	sorted := make([]int, 0, len(codes))
+	for code := range codes {
+		sorted = append(sorted, code)
+	}
+	sort.Ints(sorted)
+
+	ref := g.addErrorSchema(d, enum)
+	if op.Responses == nil {
+		op.Responses = &openapi.Responses{}
+	}
+	for _, code := range sorted {
+		name := strconv.Itoa(code)
+		if pbgen.HasResponse(op.Responses, name) {
+			continue
+		}
+		op.Responses.ResponseOrReference = append(op.Responses.ResponseOrReference, &openapi.NamedResponseOrReference{
+			Name: name,
+			Value: &openapi.ResponseOrReference{
+				Oneof: &openapi.ResponseOrReference_Response{
+					Response: &openapi.Response{
+						Description: pbgen.ErrorResponseDescription(code, codes[code]),
+						Content: &openapi.MediaTypes{
+							AdditionalProperties: []*openapi.NamedMediaType{
+								{
+									Name: consts.ContentTypeJSON,
+									Value: &openapi.MediaType{
+										Schema: &openapi.SchemaOrReference{
+											Oneof: &openapi.SchemaOrReference_Reference{
+												Reference: &openapi.Reference{XRef: ref},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+}
+
+// addErrorSchema adds the error schema of enum to the document and returns
+// its reference. The schema holds the enum value as `code` and a `message`,
+// and is named after the enum with a single `Error` suffix.
+func (g *OpenAPIGenerator) addErrorSchema(d *openapi.Document, enum *protogen.Enum) string {
+	name := strings.TrimSuffix(string(enum.Desc.Name()), errorSchemaSuffix) + errorSchemaSuffix
+	if parent, ok := enum.Desc.Parent().(protoreflect.MessageDescriptor); ok {
+		name = g.reflect.getMessageName(parent) + "_" + name
+	}
+	g.addSchemaToDocument(d, &openapi.NamedSchemaOrReference{
+		Name: name,
+		Value: &openapi.SchemaOrReference{
+			Oneof: &openapi.SchemaOrReference_Schema{
+				Schema: &openapi.Schema{
+					Type:        consts.SchemaObjectType,
+					Description: g.filterCommentString(enum.Comments.Leading),
+					Properties: &openapi.Properties{
+						AdditionalProperties: []*openapi.NamedSchemaOrReference{
+							{Name: "code", Value: wk.NewEnumDescriptorSchema(g.conf.EnumType, enum.Desc)},
+							{Name: "message", Value: wk.NewStringSchema()},
+						},
+					},
+				},
+			},
+		},
+	})
+	return consts.ComponentSchemaPrefix + name
+}
+
+// buildDefaultResponse returns the response named by the `default_response`
+// option, or nil if it is not set. google.rpc.Status does not need to be
+// imported, since its schema is built in.
+func (g *OpenAPIGenerator) buildDefaultResponse(d *openapi.Document) *openapi.Response {
+	name := strings.TrimPrefix(*g.conf.DefaultResponse, ".")
+	if name == "" {
+		return nil
+	}
+
+	var schema *openapi.SchemaOrReference
+	description := consts.DefaultErrorResponseDesc
+	if message := pbgen.FindMessage(g.plugin.Files, protoreflect.FullName(name)); message != nil {
+		schema = g.reflect.schemaOrReferenceForMessage(message.Desc)
+		if comment := g.filterCommentString(message.Comments.Leading); comment != "" {
+			description = comment
+		}
+	} else if name == googleRpcStatusName {
+		schemaName := "Status"
+		if *g.conf.FQSchemaNaming {
+			schemaName = name
+		}
+		anySchemaName := g.reflect.formatMessageName(anyProtoDesc)
+		g.addSchemaToDocument(d, wk.NewGoogleProtobufAnySchema(anySchemaName))
+		g.addSchemaToDocument(d, wk.NewGoogleRpcStatusSchema(schemaName, anySchemaName))
+		schema = &openapi.SchemaOrReference{
+			Oneof: &openapi.SchemaOrReference_Reference{
+				Reference: &openapi.Reference{XRef: consts.ComponentSchemaPrefix + schemaName},
+			},
+		}
+	} else {
+		g.diag.Errorf("default_response: message %q not found", name)
+		return nil
+	}
+
+	response := &openapi.Response{Description: description}
+	if schema != nil {
+		response.Content = &openapi.MediaTypes{
+			AdditionalProperties: []*openapi.NamedMediaType{
+				{Name: consts.ContentTypeJSON, Value: &openapi.MediaType{Schema: schema}},
+			},
+		}
+	}
+	return response
+}
+
+// addDefaultResponse adds the response of the `default_response` option as
+// the `default` response of op, unless the method sets
+// `openapi.skip_default_response`.
+func (g *OpenAPIGenerator) addDefaultResponse(method *protogen.Method, op *openapi.Operation) {
+	if g.defaultResponse == nil || proto.GetExtension(method.Desc.Options(), openapi.E_SkipDefaultResponse).(bool) {
+		return
+	}
+	if op.Responses == nil {
+		op.Responses = &openapi.Responses{}
+	}
+	if op.Responses.Default != nil {
+		return
+	}
+	op.Responses.Default = &openapi.ResponseOrReference{
+		Oneof: &openapi.ResponseOrReference_Response{
+			Response: proto.Clone(g.defaultResponse).(*openapi.Response),
+		},
+	}
+}
@@ -0,0 +1,523 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"github.com/hertz-contrib/swagger-generate/common/pbgen"
+	common "github.com/hertz-contrib/swagger-generate/common/utils"
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/api"
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	wk "github.com/hertz-contrib/swagger-generate/protoc-gen-http-swagger/generator/wellknown"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+)
+
+// httpRuleBindings returns the `google.api.http` rule of a method followed by
+// its additional bindings, or nil if the method has no such option.
+func httpRuleBindings(method *protogen.Method) []*annotations.HttpRule {
+	if !proto.HasExtension(method.Desc.Options(), annotations.E_Http) {
+		return nil
+	}
+	rule, ok := proto.GetExtension(method.Desc.Options(), annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return nil
+	}
+	// Additional bindings cannot be nested, so one level is enough.
+	return append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...)
+}
+
+// httpRuleMethod returns the HTTP method and the path template of a rule.
+func httpRuleMethod(rule *annotations.HttpRule) (string, string, error) {
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return consts.HttpMethodGet, pattern.Get, nil
+	case *annotations.HttpRule_Put:
+		return consts.HttpMethodPut, pattern.Put, nil
+	case *annotations.HttpRule_Post:
+		return consts.HttpMethodPost, pattern.Post, nil
+	case *annotations.HttpRule_Delete:
+		return consts.HttpMethodDelete, pattern.Delete, nil
+	case *annotations.HttpRule_Patch:
+		return consts.HttpMethodPatch, pattern.Patch, nil
+	case *annotations.HttpRule_Custom:
+		switch kind := strings.ToUpper(pattern.Custom.GetKind()); kind {
+		case consts.HttpMethodHead, consts.HttpMethodOptions:
+			return kind, pattern.Custom.GetPath(), nil
+		default:
+			return "", "", fmt.Errorf("custom HTTP method %q cannot be described in OpenAPI", pattern.Custom.GetKind())
+		}
+	}
+	return "", "", fmt.Errorf("HTTP rule has no pattern")
+}
+
+// pathTemplateVariable is a `{field.path=pattern}` variable of an HttpRule
+// path template.
+type pathTemplateVariable struct {
+	fieldPath string
+	pattern   string
+	// params holds the names of the path parameters the variable was
+	// expanded to.
+	params []string
+}
+
+// multiSegment reports whether the last parameter of the variable matches
+// several path segments, as its pattern ends with `**`.
+func (v *pathTemplateVariable) multiSegment() bool {
+	return v.pattern == "**" || strings.HasSuffix(v.pattern, "/**")
+}
+
+// parsePathTemplate converts an HttpRule path template to an OpenAPI path.
+// A variable without pattern, or whose pattern is a single wildcard, becomes a
+// `{field.path}` segment. Otherwise the literal segments of its pattern are
+// kept and its wildcards become parameters numbered from 1 after the field
+// path, so `{name=shelves/*/books/*}` becomes
+// `shelves/{name_1}/books/{name_2}`. As `**` matches several segments, it is
+// only accepted as the last segment of the last variable. A custom verb such
+// as `:cancel` is kept as a literal suffix.
+func parsePathTemplate(template string) (string, []*pathTemplateVariable, error) {
+	if !strings.HasPrefix(template, "/") {
+		return "", nil, fmt.Errorf("path template %q must begin with a slash", template)
+	}
+	var sb strings.Builder
+	var variables []*pathTemplateVariable
+	used := map[string]bool{}
+	unique := func(name string) string {
+		candidate := name
+		for i := 2; used[candidate]; i++ {
+			candidate = fmt.Sprintf("%s%d", name, i)
+		}
+		used[candidate] = true
+		return candidate
+	}
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		if c == '}' {
+			return "", nil, fmt.Errorf("unbalanced '}' in path template %q", template)
+		}
+		if c != '{' {
+			sb.WriteByte(c)
+			continue
+		}
+		end := strings.IndexByte(template[i:], '}')
+		if end < 0 {
+			return "", nil, fmt.Errorf("unterminated variable in path template %q", template)
+		}
+		variable := template[i+1 : i+end]
+		i += end
+		fieldPath, pattern := variable, "*"
+		if eq := strings.IndexByte(variable, '='); eq >= 0 {
+			fieldPath, pattern = variable[:eq], variable[eq+1:]
+		}
+		if fieldPath == "" || pattern == "" || strings.ContainsAny(fieldPath, "{/") {
+			return "", nil, fmt.Errorf("invalid variable %q in path template %q", variable, template)
+		}
+		v := &pathTemplateVariable{fieldPath: fieldPath, pattern: pattern}
+		variables = append(variables, v)
+		segments := strings.Split(pattern, "/")
+		for j, segment := range segments {
+			if segment == "**" && (j != len(segments)-1 || !lastSegment(template[i+1:])) {
+				return "", nil, fmt.Errorf("'**' must be the last segment of path template %q", template)
+			}
+		}
+		if pattern == "*" || pattern == "**" {
+			v.params = append(v.params, unique(fieldPath))
+			sb.WriteString("{" + v.params[0] + "}")
+			continue
+		}
+		for j, segment := range segments {
+			if segment != "*" && segment != "**" {
+				continue
+			}
+			name := unique(fmt.Sprintf("%s_%d", fieldPath, len(v.params)+1))
+			v.params = append(v.params, name)
+			segments[j] = "{" + name + "}"
+		}
+		sb.WriteString(strings.Join(segments, "/"))
+	}
+	return sb.String(), variables, nil
+}
+
+// lastSegment reports whether rest, the part of a path template after a
+// variable, ends the path: it is empty or a custom verb.
+func lastSegment(rest string) bool {
+	return rest == "" || strings.HasPrefix(rest, ":")
+}
+
+// resolveFieldPath returns the fields designated by a dotted path of proto
+// field names, starting from message.
+func resolveFieldPath(message *protogen.Message, fieldPath string) ([]*protogen.Field, error) {
+	var fields []*protogen.Field
+	for _, name := range strings.Split(fieldPath, ".") {
+		if message == nil {
+			return nil, fmt.Errorf("field path %q goes through a non-message field", fieldPath)
+		}
+		var found *protogen.Field
+		for _, field := range message.Fields {
+			if string(field.Desc.Name()) == name {
+				found = field
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("no field %q in message %s", name, message.Desc.FullName())
+		}
+		fields = append(fields, found)
+		if (found.Desc.IsList() || found.Desc.IsMap()) && len(fields) < strings.Count(fieldPath, ".")+1 {
+			return nil, fmt.Errorf("field path %q goes through repeated field %q", fieldPath, name)
+		}
+		message = found.Message
+	}
+	return fields, nil
+}
+
+// buildHttpRuleOperation builds the operation for one binding of a
+// `google.api.http` rule. Fields bound in the path template become path
+// parameters, the `body` selector decides what goes in the request body and
+// every other field becomes a query parameter, as in grpc-gateway.
+func (g *OpenAPIGenerator) buildHttpRuleOperation(
+	method *protogen.Method,
+	rule *annotations.HttpRule,
+	operationID string,
+	tagName string,
+	description string,
+	defaultHost string,
+) (*openapi.Operation, string, string, error) {
+	// Requests and responses use the schemas of their direction.
+	g.reflect.direction = directionInput
+	defer func() { g.reflect.direction = "" }()
+
+	methodName, template, err := httpRuleMethod(rule)
+	if err != nil {
+		return nil, "", "", err
+	}
+	path, variables, err := parsePathTemplate(template)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	var parameters []*openapi.ParameterOrReference
+	// Top level fields that are entirely bound to the path or the body, and
+	// nested fields bound to the path.
+	bound := map[string]bool{}
+	for _, variable := range variables {
+		fields, err := resolveFieldPath(method.Input, variable.fieldPath)
+		if err != nil {
+			return nil, "", "", err
+		}
+		bound[variable.fieldPath] = true
+		leaf := fields[len(fields)-1]
+		if leaf.Desc.IsList() {
+			return nil, "", "", fmt.Errorf("repeated field %q cannot be bound to the path", variable.fieldPath)
+		}
+		paramDesc := pbgen.FieldDescription(leaf)
+		if variable.pattern == "*" || variable.pattern == "**" {
+			if variable.multiSegment() {
+				paramDesc = common.AppendParagraph(paramDesc, consts.MultiSegmentParameterDesc)
+			}
+			parameters = append(parameters, g.httpRuleParameter(leaf, variable.params[0], consts.ParameterInPath, paramDesc, true))
+			continue
+		}
+		// The field is built from several segments of the path.
+		paramDesc = strings.TrimSpace(fmt.Sprintf("%s\n\nPart of `%s`, which has the format `%s`.", paramDesc, variable.fieldPath, variable.pattern))
+		for j, name := range variable.params {
+			desc := paramDesc
+			if j == len(variable.params)-1 && variable.multiSegment() {
+				desc = common.AppendParagraph(desc, consts.MultiSegmentParameterDesc)
+			}
+			parameters = append(parameters, &openapi.ParameterOrReference{
+				Oneof: &openapi.ParameterOrReference_Parameter{Parameter: &openapi.Parameter{
+					Name:        name,
+					In:          consts.ParameterInPath,
+					Description: desc,
+					Required:    true,
+					Deprecated:  pbgen.IsDeprecated(leaf.Desc, leaf.Comments),
+					Schema:      wk.NewStringSchema(),
+				}},
+			})
+		}
+	}
+
+	var requestBody *openapi.RequestBodyOrReference
+	switch body := rule.GetBody(); body {
+	case "":
+	case "*":
+		requestBody = g.httpRuleRequestBody(method.Input, g.httpRuleBodySchema(method.Input, len(variables) > 0, bound))
+		for _, field := range method.Input.Fields {
+			bound[string(field.Desc.Name())] = true
+		}
+	default:
+		fields, err := resolveFieldPath(method.Input, body)
+		if err != nil {
+			return nil, "", "", err
+		}
+		if len(fields) != 1 {
+			return nil, "", "", fmt.Errorf("body %q must be a top level field", body)
+		}
+		requestBody = g.httpRuleRequestBody(method.Input, g.reflect.schemaOrReferenceForField(fields[0].Desc))
+		bound[body] = true
+	}
+
+	for _, field := range method.Input.Fields {
+		parameters = append(parameters, g.httpRuleQueryParameters(field, "", "", bound, map[string]bool{string(method.Input.Desc.FullName()): true})...)
+	}
+
+	g.reflect.direction = directionOutput
+	responses, err := g.httpRuleResponses(method.Output, rule.GetResponseBody())
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	// The first sentence of the method comment is the summary.
+	summary, description := common.SplitSummary(description)
+	op := &openapi.Operation{
+		Tags:        []string{tagName},
+		Summary:     summary,
+		Description: description,
+		OperationId: operationID,
+		Parameters:  parameters,
+		RequestBody: requestBody,
+		Responses:   responses,
+	}
+	if defaultHost != "" {
+		if !strings.HasPrefix(defaultHost, consts.URLDefaultPrefixHTTP) && !strings.HasPrefix(defaultHost, consts.URLDefaultPrefixHTTPS) {
+			defaultHost = consts.URLDefaultPrefixHTTP + defaultHost
+		}
+		op.Servers = append(op.Servers, &openapi.Server{Url: defaultHost})
+	}
+	return op, path, methodName, nil
+}
+
+func (g *OpenAPIGenerator) httpRuleParameter(field *protogen.Field, name, in, description string, required bool) *openapi.ParameterOrReference {
+	fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
+	if schema, ok := fieldSchema.GetOneof().(*openapi.SchemaOrReference_Schema); ok {
+		// Merge any `Property` annotations with the current
+		extProperty := proto.GetExtension(field.Desc.Options(), openapi.E_Property)
+		g.checkOption(field.Desc, openapi.E_Property, extProperty)
+		if extProperty != nil {
+			common.MergeOptionMessage(schema.Schema, extProperty.(*openapi.Schema))
+		}
+	}
+	parameter := &openapi.Parameter{
+		Name:        name,
+		In:          in,
+		Description: description,
+		Required:    required,
+		Deprecated:  pbgen.IsDeprecated(field.Desc, field.Comments),
+		Example:     pbgen.FieldExample(field),
+		Schema:      fieldSchema,
+	}
+	extParameter := proto.GetExtension(field.Desc.Options(), openapi.E_Parameter)
+	g.checkOption(field.Desc, openapi.E_Parameter, extParameter)
+	if extParameter != nil {
+		common.MergeOptionMessage(parameter, extParameter.(*openapi.Parameter))
+	}
+	return &openapi.ParameterOrReference{
+		Oneof: &openapi.ParameterOrReference_Parameter{Parameter: parameter},
+	}
+}
+
+// httpRuleQueryParameters returns the query parameters of a field that is not
+// bound to the path or the body. Message fields are expanded into one
+// parameter per nested field, named by their dotted path; maps and repeated
+// messages cannot be expressed in a query string and are skipped. Fields
+// whose path of proto names is in bound are skipped too. seen holds the
+// messages being expanded, to stop on recursive types.
+func (g *OpenAPIGenerator) httpRuleQueryParameters(field *protogen.Field, prefix, protoPrefix string, bound, seen map[string]bool) []*openapi.ParameterOrReference {
+	name := prefix + g.reflect.formatFieldName(field.Desc)
+	protoPath := protoPrefix + string(field.Desc.Name())
+	if field.Desc.IsMap() || bound[protoPath] || g.reflect.hiddenField(field.Desc) {
+		return nil
+	}
+	if field.Message == nil {
+		return []*openapi.ParameterOrReference{
+			g.httpRuleParameter(field, name, consts.ParameterInQuery, pbgen.FieldDescription(field), pbgen.FieldRequired(field.Desc)),
+		}
+	}
+	// Well-known types with a scalar JSON representation are a single
+	// parameter, the others cannot be expressed in a query string.
+	if schema, ok := g.reflect.wellKnownSchemaForMessage(field.Message.Desc); ok {
+		if schema.GetSchema() == nil || schema.GetSchema().Type == consts.SchemaObjectType {
+			return nil
+		}
+		return []*openapi.ParameterOrReference{
+			g.httpRuleParameter(field, name, consts.ParameterInQuery, pbgen.FieldDescription(field), pbgen.FieldRequired(field.Desc)),
+		}
+	}
+	fullName := string(field.Message.Desc.FullName())
+	if field.Desc.IsList() || seen[fullName] {
+		return nil
+	}
+	seen[fullName] = true
+	defer delete(seen, fullName)
+	var parameters []*openapi.ParameterOrReference
+	for _, nested := range field.Message.Fields {
+		parameters = append(parameters, g.httpRuleQueryParameters(nested, name+".", protoPath+".", bound, seen)...)
+	}
+	return parameters
+}
+
+// httpRuleBodySchema returns the schema of a `body: "*"` request. It is the
+// message itself unless some of its fields are bound to the path, in which case
+// the remaining top level fields are described inline.
+func (g *OpenAPIGenerator) httpRuleBodySchema(message *protogen.Message, hasPathFields bool, bound map[string]bool) *openapi.SchemaOrReference {
+	if !hasPathFields {
+		return g.reflect.schemaOrReferenceForMessage(message.Desc)
+	}
+	properties := &openapi.Properties{}
+	var required []string
+	for _, field := range message.Fields {
+		if g.reflect.hiddenField(field.Desc) {
+			continue
+		}
+		if bound[string(field.Desc.Name())] {
+			continue
+		}
+		fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
+		if fieldSchema == nil {
+			continue
+		}
+		if pbgen.FieldRequired(field.Desc) {
+			required = append(required, g.reflect.formatFieldName(field.Desc))
+		}
+		properties.AdditionalProperties = append(properties.AdditionalProperties, &openapi.NamedSchemaOrReference{
+			Name:  g.reflect.formatFieldName(field.Desc),
+			Value: fieldSchema,
+		})
+	}
+	return &openapi.SchemaOrReference{
+		Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{
+			Type:       consts.SchemaObjectType,
+			Properties: properties,
+			Required:   required,
+		}},
+	}
+}
+
+func (g *OpenAPIGenerator) httpRuleRequestBody(message *protogen.Message, schema *openapi.SchemaOrReference) *openapi.RequestBodyOrReference {
+	if schema == nil {
+		return nil
+	}
+	return &openapi.RequestBodyOrReference{
+		Oneof: &openapi.RequestBodyOrReference_RequestBody{
+			RequestBody: &openapi.RequestBody{
+				Description: g.filterCommentString(message.Comments.Leading),
+				Required:    true,
+				Content: &openapi.MediaTypes{
+					AdditionalProperties: []*openapi.NamedMediaType{
+						{
+							Name:  consts.ContentTypeJSON,
+							Value: &openapi.MediaType{Schema: schema},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// httpRuleResponses describes the output message, or the field selected by
+// `response_body`, as the successful response.
+func (g *OpenAPIGenerator) httpRuleResponses(message *protogen.Message, responseBody string) (*openapi.Responses, error) {
+	desc := g.filterCommentString(message.Comments.Leading)
+	if desc == "" {
+		desc = consts.DefaultResponseDesc
+	}
+	schema := g.reflect.schemaOrReferenceForMessage(message.Desc)
+	if schema == nil && message.Desc.FullName() == emptyFullName {
+		// google.protobuf.Empty is returned as an empty JSON object.
+		schema = wk.NewGoogleProtobufEmptySchema()
+	}
+	if responseBody != "" {
+		fields, err := resolveFieldPath(message, responseBody)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) != 1 {
+			return nil, fmt.Errorf("response_body %q must be a top level field", responseBody)
+		}
+		schema = g.reflect.schemaOrReferenceForField(fields[0].Desc)
+	}
+	response := &openapi.Response{Description: desc}
+	if schema != nil {
+		response.Content = &openapi.MediaTypes{
+			AdditionalProperties: []*openapi.NamedMediaType{
+				{
+					Name:  consts.ContentTypeJSON,
+					Value: &openapi.MediaType{Schema: schema},
+				},
+			},
+		}
+	}
+	return &openapi.Responses{
+		ResponseOrReference: []*openapi.NamedResponseOrReference{
+			{
+				Name: consts.StatusOK,
+				Value: &openapi.ResponseOrReference{
+					Oneof: &openapi.ResponseOrReference_Response{Response: response},
+				},
+			},
+		},
+	}, nil
+}
+
+// addHttpRuleOperations adds one operation per binding of the `google.api.http`
+// rule of a method and returns how many were added. first is the number of
+// operations already added for the method by the hz annotations; every
+// operation after the first one gets a numbered operationId, since
+// operationIds must be unique.
+func (g *OpenAPIGenerator) addHttpRuleOperations(d *openapi.Document, service *protogen.Service, method *protogen.Method, first int, host string) int {
+	count := 0
+	for i, rule := range httpRuleBindings(method) {
+		operationID := service.GoName + "_" + method.GoName
+		if n := first + i; n > 0 {
+			operationID = fmt.Sprintf("%s_%d", operationID, n)
+		}
+		op, path, methodName, err := g.buildHttpRuleOperation(method, rule, operationID, service.GoName, g.filterCommentString(method.Comments.Leading), host)
+		if err != nil {
+			g.diag.ErrorfAt(diagnostics.ProtoLocation(method.Desc, annotations.E_Http), "Error parsing google.api.http option: %s", err)
+			continue
+		}
+		g.addErrorResponses(d, service, method, op)
+		g.addDefaultResponse(method, op)
+		pbgen.ApplyStreaming(method, op, pbgen.LookupStreamContentType(*g.conf.StreamContentType, g.diag), *g.conf.DisableStreamingTryItOut)
+		pbgen.ApplyDeprecation(service, method, op)
+		// Merge any `Operation` annotations with the current
+		extOperation := proto.GetExtension(method.Desc.Options(), openapi.E_Operation)
+		g.checkOption(method.Desc, openapi.E_Operation, extOperation)
+		if extOperation != nil {
+			common.MergeOptionMessage(op, extOperation.(*openapi.Operation))
+		}
+		g.addOperationToDocument(d, op, path, methodName)
+		count++
+	}
+	return count
+}
+
+// methodHost returns the server of a method, set by `api.baseurl` on the
+// method or `api.base_domain` on its service.
+func methodHost(service *protogen.Service, method *protogen.Method) string {
+	if host := proto.GetExtension(method.Desc.Options(), api.E_Baseurl).(string); host != "" {
+		return host
+	}
+	return proto.GetExtension(service.Desc.Options(), api.E_BaseDomain).(string)
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestParsePathTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+		// params holds the path parameters of each variable.
+		params  [][]string
+		wantErr bool
+	}{
+		{template: "/v1/shelves", want: "/v1/shelves"},
+		{template: "/v1/shelves/{id}", want: "/v1/shelves/{id}", params: [][]string{{"id"}}},
+		{template: "/v1/shelves/{id=*}", want: "/v1/shelves/{id}", params: [][]string{{"id"}}},
+		{template: "/v1/files/{path=**}", want: "/v1/files/{path}", params: [][]string{{"path"}}},
+		{template: "/v1/{name=shelves/*}", want: "/v1/shelves/{name_1}", params: [][]string{{"name_1"}}},
+		{template: "/v1/{name=policies/*}", want: "/v1/policies/{name_1}", params: [][]string{{"name_1"}}},
+		{
+			template: "/v1/{name=shelves/*/books/*}",
+			want:     "/v1/shelves/{name_1}/books/{name_2}",
+			params:   [][]string{{"name_1", "name_2"}},
+		},
+		{
+			template: "/v1/{book.name=shelves/*/books/*}",
+			want:     "/v1/shelves/{book.name_1}/books/{book.name_2}",
+			params:   [][]string{{"book.name_1", "book.name_2"}},
+		},
+		{template: "/v1/{name=*/books/*}", want: "/v1/{name_1}/books/{name_2}", params: [][]string{{"name_1", "name_2"}}},
+		{template: "/v1/{name=files/**}", want: "/v1/files/{name_1}", params: [][]string{{"name_1"}}},
+		{
+			template: "/v1/{name=shelves/*/shelves/*}",
+			want:     "/v1/shelves/{name_1}/shelves/{name_2}",
+			params:   [][]string{{"name_1", "name_2"}},
+		},
+		{
+			template: "/v1/{parent=shelves/*}/{id}",
+			want:     "/v1/shelves/{parent_1}/{id}",
+			params:   [][]string{{"parent_1"}, {"id"}},
+		},
+		{template: "/v1/{name=shelves/*}:cancel", want: "/v1/shelves/{name_1}:cancel", params: [][]string{{"name_1"}}},
+		{template: "/v1/shelves/{id}:undelete", want: "/v1/shelves/{id}:undelete", params: [][]string{{"id"}}},
+		{template: "/v1/shelves:batchGet", want: "/v1/shelves:batchGet"},
+		{template: "v1/shelves", wantErr: true},
+		{template: "/v1/{id", wantErr: true},
+		{template: "/v1/id}", wantErr: true},
+		{template: "/v1/{=*}", wantErr: true},
+		{template: "/v1/{id=}", wantErr: true},
+		{template: "/v1/{a/b}", wantErr: true},
+		{template: "/v1/files/{path=**}:download", want: "/v1/files/{path}:download", params: [][]string{{"path"}}},
+		{template: "/v1/{a=x/*}/{a_1}", want: "/v1/x/{a_1}/{a_12}", params: [][]string{{"a_1"}, {"a_12"}}},
+		{template: "/v1/{name=files/**/raw}", wantErr: true},
+		{template: "/v1/files/{path=**}/raw", wantErr: true},
+		{template: "/v1/{path=**}/{id}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got, variables, err := parsePathTemplate(tt.template)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePathTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parsePathTemplate() = %q, want %q", got, tt.want)
+			}
+			var params [][]string
+			for _, v := range variables {
+				params = append(params, v.params)
+			}
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("parsePathTemplate() params = %v, want %v", params, tt.params)
+			}
+		})
+	}
+}
+
+// testMethod builds the method of a service whose input message has a string
+// field named after each of fields.
+func testMethod(t *testing.T, fields []string) *protogen.Method {
+	req := &descriptorpb.DescriptorProto{Name: proto.String("Req")}
+	for i, name := range fields {
+		req.Field = append(req.Field, &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(int32(i + 1)),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		})
+	}
+	return testFile(t, req).Services[0].Methods[0]
+}
+
+// testFile builds a file holding message and a service whose method takes and
+// returns it.
+func testFile(t *testing.T, message *descriptorpb.DescriptorProto, messages ...*descriptorpb.DescriptorProto) *protogen.File {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("test.proto"),
+		Package:     proto.String("test"),
+		Syntax:      proto.String("proto3"),
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test")},
+		MessageType: append([]*descriptorpb.DescriptorProto{message}, messages...),
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Svc"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("Get"),
+				InputType:  proto.String(".test." + message.GetName()),
+				OutputType: proto.String(".test." + message.GetName()),
+			}},
+		}},
+	}
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"test.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plugin.Files[0]
+}
+
+// testGenerator returns a generator with the default configuration.
+func testGenerator(diag *diagnostics.Collector) *OpenAPIGenerator {
+	str := func(s string) *string { return &s }
+	return NewOpenAPIGenerator(nil, Configuration{
+		Naming:         str("json"),
+		FQSchemaNaming: new(bool),
+		EnumType:       str("integer"),
+		OneofStyle:     str(""),
+		SchemaFile:     str(""),
+		SplitIOSchemas: new(bool),
+	}, nil, diag)
+}
+
+func TestBuildHttpRuleOperationPathParameters(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []string
+		template string
+		want     []string
+		// multiSegment holds the parameters documented as spanning several
+		// segments.
+		multiSegment []string
+	}{
+		{name: "field", fields: []string{"id"}, template: "/v1/shelves/{id}", want: []string{"id"}},
+		{name: "pattern", fields: []string{"name"}, template: "/v1/{name=shelves/*/books/*}", want: []string{"name_1", "name_2"}},
+		{name: "renamed wildcard", fields: []string{"a", "a_1"}, template: "/v1/{a=x/*}/{a_1}", want: []string{"a_1", "a_12"}},
+		{name: "rest of the path", fields: []string{"path"}, template: "/v1/files/{path=**}", want: []string{"path"}, multiSegment: []string{"path"}},
+		{name: "rest of a pattern", fields: []string{"name"}, template: "/v1/{name=files/*/**}", want: []string{"name_1", "name_2"}, multiSegment: []string{"name_2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := testGenerator(diagnostics.NewCollector())
+			rule := &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: tt.template}}
+			op, path, _, err := g.buildHttpRuleOperation(testMethod(t, tt.fields), rule, "Svc_Get", "Svc", "", "")
+			if err != nil {
+				t.Fatalf("buildHttpRuleOperation() error = %v", err)
+			}
+			var got, multiSegment []string
+			for _, parameter := range op.Parameters {
+				p := parameter.GetParameter()
+				if p.In != consts.ParameterInPath {
+					continue
+				}
+				if !strings.Contains(path, "{"+p.Name+"}") {
+					t.Errorf("parameter %s is not in path %s", p.Name, path)
+				}
+				got = append(got, p.Name)
+				if strings.Contains(p.Description, consts.MultiSegmentParameterDesc) {
+					multiSegment = append(multiSegment, p.Name)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("path parameters = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(multiSegment, tt.multiSegment) {
+				t.Errorf("multi-segment parameters = %v, want %v", multiSegment, tt.multiSegment)
+			}
+		})
+	}
+}
@@ -39,27 +39,37 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/cloudwego/hertz/cmd/hz/util/logs"
 	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"github.com/hertz-contrib/swagger-generate/common/pbgen"
 	common "github.com/hertz-contrib/swagger-generate/common/utils"
+	"github.com/hertz-contrib/swagger-generate/common/validator"
 	"github.com/hertz-contrib/swagger-generate/idl/protobuf/api"
 	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
 	wk "github.com/hertz-contrib/swagger-generate/protoc-gen-http-swagger/generator/wellknown"
 	"google.golang.org/genproto/googleapis/api/annotations"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/runtime/protoimpl"
 	any_pb "google.golang.org/protobuf/types/known/anypb"
 )
 
 type Configuration struct {
-	Version        *string
-	Title          *string
-	Description    *string
-	Naming         *string
-	FQSchemaNaming *bool
-	EnumType       *string
-	OutputMode     *string
+	Version                  *string
+	Title                    *string
+	Description              *string
+	Naming                   *string
+	FQSchemaNaming           *bool
+	EnumType                 *string
+	OutputMode               *string
+	OneofStyle               *string
+	DefaultResponse          *string
+	SchemaFile               *string
+	SplitIOSchemas           *bool
+	StreamContentType        *string
+	DisableStreamingTryItOut *bool
+	Strict                   *bool
 }
 
 // In order to dynamically add google.rpc.Status responses we need
@@ -74,28 +84,33 @@ type OpenAPIGenerator struct {
 	conf             Configuration
 	plugin           *protogen.Plugin
 	inputFiles       []*protogen.File
+	diag             *diagnostics.Collector
 	reflect          *OpenAPIReflector
-	generatedSchemas []string // Names of schemas that have already been generated.
+	generatedSchemas []string          // Names of schemas that have already been generated.
+	defaultResponse  *openapi.Response // Response added as `default` to every operation.
 }
 
 // NewOpenAPIGenerator creates a new generator for a protoc plugin invocation.
-func NewOpenAPIGenerator(plugin *protogen.Plugin, conf Configuration, inputFiles []*protogen.File) *OpenAPIGenerator {
+func NewOpenAPIGenerator(plugin *protogen.Plugin, conf Configuration, inputFiles []*protogen.File, diag *diagnostics.Collector) *OpenAPIGenerator {
 	return &OpenAPIGenerator{
 		conf:             conf,
 		plugin:           plugin,
 		inputFiles:       inputFiles,
-		reflect:          NewOpenAPIReflector(conf),
+		diag:             diag,
+		reflect:          NewOpenAPIReflector(conf, diag),
 		generatedSchemas: make([]string, 0),
 	}
 }
 
-// Run runs the generator.
-func (g *OpenAPIGenerator) Run(outputFile *protogen.GeneratedFile) error {
+// Run runs the generator and writes the document to outputFile, which is
+// named filename in the diagnostics of the document validation.
+func (g *OpenAPIGenerator) Run(outputFile *protogen.GeneratedFile, filename string) error {
 	d := g.buildDocument()
 	bytes, err := d.YAMLValue("Generated with " + consts.PluginNameProtocHttpSwagger + "\n" + consts.InfoURL + "blob/main/" + consts.PluginNameProtocHttpSwagger)
 	if err != nil {
 		return fmt.Errorf("failed to marshal yaml: %s", err.Error())
 	}
+	validator.Validate(filename, bytes, g.diag)
 	if _, err = outputFile.Write(bytes); err != nil {
 		return fmt.Errorf("failed to write yaml: %s", err.Error())
 	}
@@ -119,6 +134,7 @@ func (g *OpenAPIGenerator) buildDocument() *openapi.Document {
 			AdditionalProperties: []*openapi.NamedSchemaOrReference{},
 		},
 	}
+	g.defaultResponse = g.buildDefaultResponse(d)
 
 	// Go through the files and add the services to the documents, keeping
 	// track of which schemas are referenced in the response so we can
@@ -127,11 +143,12 @@ func (g *OpenAPIGenerator) buildDocument() *openapi.Document {
 		if file.Generate {
 			// Merge any `Document` annotations with the current
 			extDocument := proto.GetExtension(file.Desc.Options(), openapi.E_Document)
+			g.checkOption(file.Desc, openapi.E_Document, extDocument)
 			if extDocument != nil {
 				if doc, ok := extDocument.(*openapi.Document); ok {
-					proto.Merge(d, doc)
+					common.MergeOptionMessage(d, doc)
 				} else {
-					logs.Errorf("unexpected type for Document: %T", extDocument)
+					g.diag.ErrorfAt(diagnostics.ProtoLocation(file.Desc, openapi.E_Document), "unexpected type for Document: %T", extDocument)
 				}
 			}
 			g.addPathsToDocument(d, file.Services)
@@ -249,19 +266,15 @@ func (g *OpenAPIGenerator) buildDocument() *openapi.Document {
 	return d
 }
 
-// filterCommentString removes linter rules from comments.
+// filterCommentString removes linter rules and directives from comments.
 func (g *OpenAPIGenerator) filterCommentString(c protogen.Comments) string {
-	comment := regexp.MustCompile(consts.LinterRulePatternRegexp).ReplaceAllString(string(c), "")
-	return strings.TrimSpace(comment)
+	return pbgen.FilterComment(c)
 }
 
 func (g *OpenAPIGenerator) getSchemaByOption(inputMessage *protogen.Message, bodyType *protoimpl.ExtensionInfo) *openapi.Schema {
-	// Build an array holding the fields of the message.
-	definitionProperties := &openapi.Properties{
-		AdditionalProperties: make([]*openapi.NamedSchemaOrReference, 0),
-	}
 	// Merge any `Schema` annotations with the current
 	extSchema := proto.GetExtension(inputMessage.Desc.Options(), openapi.E_Schema)
+	g.checkOption(inputMessage.Desc, openapi.E_Schema, extSchema)
 	var allRequired []string
 	if extSchema != nil {
 		if schema, ok := extSchema.(*openapi.Schema); ok && schema != nil {
@@ -270,91 +283,133 @@ func (g *OpenAPIGenerator) getSchemaByOption(inputMessage *protogen.Message, bod
 			}
 		}
 	}
-	var required []string
-	for _, field := range inputMessage.Fields {
-		if ext := proto.GetExtension(field.Desc.Options(), bodyType); ext != "" {
-			if common.Contains(allRequired, ext.(string)) {
-				required = append(required, ext.(string))
-			}
+	// Only the fields bound to bodyType are documented, under their binding.
+	definitionProperties, required, oneofs := g.fieldProperties(inputMessage, func(field *protogen.Field) string {
+		return proto.GetExtension(field.Desc.Options(), bodyType).(string)
+	}, allRequired)
 
-			// Get the field description from the comments.
-			description := g.filterCommentString(field.Comments.Leading)
-			// Check the field annotations to see if this is a readonly or writeonly field.
-			inputOnly := false
-			outputOnly := false
-			extension := proto.GetExtension(field.Desc.Options(), annotations.E_FieldBehavior)
-			if extension != nil {
-				switch v := extension.(type) {
-				case []annotations.FieldBehavior:
-					for _, vv := range v {
-						switch vv {
-						case annotations.FieldBehavior_OUTPUT_ONLY:
-							outputOnly = true
-						case annotations.FieldBehavior_INPUT_ONLY:
-							inputOnly = true
-						case annotations.FieldBehavior_REQUIRED:
-							required = append(required, g.reflect.formatFieldName(field.Desc))
-						}
-					}
-				default:
-					logs.Error("unsupported extension type %T", extension)
-				}
-			}
+	schema := &openapi.Schema{
+		Type:       consts.SchemaObjectType,
+		Properties: definitionProperties,
+		Deprecated: pbgen.IsDeprecated(inputMessage.Desc, inputMessage.Comments),
+	}
 
-			// The field is either described by a reference or a schema.
-			fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
-			if fieldSchema == nil {
-				continue
-			}
+	// Merge any `Schema` annotations with the current
+	if extSchema != nil {
+		common.MergeOptionMessage(schema, extSchema.(*openapi.Schema))
+	}
 
-			// If this field has siblings and is a $ref now, create a new schema use `allOf` to wrap it
-			wrapperNeeded := inputOnly || outputOnly || description != ""
-			if wrapperNeeded {
-				if _, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Reference); ok {
-					fieldSchema = &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{
-						AllOf: []*openapi.SchemaOrReference{fieldSchema},
-					}}}
-				}
-			}
+	schema.Required = required
+	oneofs.Apply(schema)
+	return schema
+}
 
-			if schema, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Schema); ok {
-				schema.Schema.Description = description
-				schema.Schema.ReadOnly = outputOnly
-				schema.Schema.WriteOnly = inputOnly
+// fieldProperties builds the properties of the fields of message that name
+// gives a name to, skipping the hidden ones, and returns them with the names
+// of the required ones and the oneof groups to apply to the message schema.
+// The fields whose name is in requiredNames are required, as well as the ones
+// required by their behavior or label.
+func (g *OpenAPIGenerator) fieldProperties(message *protogen.Message, name func(*protogen.Field) string, requiredNames []string) (*openapi.Properties, []string, *pbgen.OneofGroups) {
+	properties := &openapi.Properties{
+		AdditionalProperties: make([]*openapi.NamedSchemaOrReference, 0),
+	}
+	var required []string
+	oneofs := pbgen.NewOneofGroups(*g.conf.OneofStyle)
+	for _, field := range message.Fields {
+		if g.reflect.hiddenField(field.Desc) {
+			continue
+		}
+		propertyName := name(field)
+		if propertyName == "" {
+			continue
+		}
+		if common.Contains(requiredNames, propertyName) {
+			required = common.AppendUnique(required, propertyName)
+		}
+
+		fieldSchema, fieldRequired := g.fieldSchema(field)
+		if fieldRequired {
+			required = common.AppendUnique(required, g.reflect.formatFieldName(field.Desc))
+		}
+		if fieldSchema == nil {
+			continue
+		}
+		property := &openapi.NamedSchemaOrReference{
+			Name:  propertyName,
+			Value: fieldSchema,
+		}
+		if !oneofs.Add(field, property) {
+			properties.AdditionalProperties = append(properties.AdditionalProperties, property)
+		}
+	}
+	return properties, required, oneofs
+}
 
-				// Merge any `Property` annotations with the current
-				extProperty := proto.GetExtension(field.Desc.Options(), openapi.E_Property)
-				if extProperty != nil {
-					proto.Merge(schema.Schema, extProperty.(*openapi.Schema))
+// fieldSchema returns the schema of field, holding its description, example,
+// deprecation, field behaviors and `Property` annotations, or nil if the field
+// has no schema. It also reports whether the field is required by its
+// behavior or its label.
+func (g *OpenAPIGenerator) fieldSchema(field *protogen.Field) (*openapi.SchemaOrReference, bool) {
+	// Get the field description from the comments.
+	description := pbgen.FieldDescription(field)
+	example := pbgen.FieldExample(field)
+	// Check the field annotations to see if this is a readonly or writeonly field.
+	inputOnly := false
+	outputOnly := false
+	required := pbgen.FieldRequired(field.Desc)
+	extension := proto.GetExtension(field.Desc.Options(), annotations.E_FieldBehavior)
+	if extension != nil {
+		switch v := extension.(type) {
+		case []annotations.FieldBehavior:
+			for _, vv := range v {
+				switch vv {
+				case annotations.FieldBehavior_OUTPUT_ONLY:
+					outputOnly = true
+				case annotations.FieldBehavior_INPUT_ONLY:
+					inputOnly = true
+				case annotations.FieldBehavior_REQUIRED:
+					required = true
 				}
 			}
-			extName := proto.GetExtension(field.Desc.Options(), bodyType).(string)
-			if extName == "" {
-				extName = g.reflect.formatFieldName(field.Desc)
-			}
-			definitionProperties.AdditionalProperties = append(
-				definitionProperties.AdditionalProperties,
-				&openapi.NamedSchemaOrReference{
-					Name:  extName,
-					Value: fieldSchema,
-				},
-			)
+		default:
+			g.diag.ErrorfAt(diagnostics.ProtoLocation(field.Desc, annotations.E_FieldBehavior), "unsupported extension type %T", extension)
 		}
 	}
 
-	schema := &openapi.Schema{
-		Type:       consts.SchemaObjectType,
-		Properties: definitionProperties,
+	// The field is either described by a reference or a schema.
+	fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
+	if fieldSchema == nil {
+		return nil, required
 	}
 
-	// Merge any `Schema` annotations with the current
-	extSchema = proto.GetExtension(inputMessage.Desc.Options(), openapi.E_Schema)
-	if extSchema != nil {
-		proto.Merge(schema, extSchema.(*openapi.Schema))
+	// If this field has siblings and is a $ref now, create a new schema use `allOf` to wrap it
+	deprecated := pbgen.IsDeprecated(field.Desc, field.Comments)
+	wrapperNeeded := inputOnly || outputOnly || deprecated || example != nil || description != ""
+	if wrapperNeeded {
+		if _, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Reference); ok {
+			fieldSchema = &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{
+				AllOf: []*openapi.SchemaOrReference{fieldSchema},
+			}}}
+		}
 	}
 
-	schema.Required = required
-	return schema
+	if schema, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Schema); ok {
+		schema.Schema.Description = description
+		schema.Schema.ReadOnly = outputOnly
+		schema.Schema.WriteOnly = inputOnly
+		schema.Schema.Deprecated = deprecated
+		if example != nil {
+			schema.Schema.Example = example
+		}
+
+		// Merge any `Property` annotations with the current
+		extProperty := proto.GetExtension(field.Desc.Options(), openapi.E_Property)
+		g.checkOption(field.Desc, openapi.E_Property, extProperty)
+		if extProperty != nil {
+			common.MergeOptionMessage(schema.Schema, extProperty.(*openapi.Schema))
+		}
+	}
+	return fieldSchema, required
 }
 
 func (g *OpenAPIGenerator) buildOperation(
@@ -368,6 +423,10 @@ func (g *OpenAPIGenerator) buildOperation(
 	inputMessage *protogen.Message,
 	outputMessage *protogen.Message,
 ) (*openapi.Operation, string) {
+	// Requests and responses use the schemas of their direction.
+	g.reflect.direction = directionInput
+	defer func() { g.reflect.direction = "" }()
+
 	// Parameters array to hold all parameter objects
 	var parameters []*openapi.ParameterOrReference
 
@@ -376,37 +435,42 @@ func (g *OpenAPIGenerator) buildOperation(
 	if inputMessage != nil {
 		// Iterate through each field in the input message
 		for _, field := range inputMessage.Fields {
+			if g.reflect.hiddenField(field.Desc) {
+				continue
+			}
 			var paramName, paramIn, paramDesc string
 			var fieldSchema *openapi.SchemaOrReference
-			required := false
+			required := pbgen.FieldRequired(field.Desc)
 			var ext any
 			// Check for each type of extension (query, path, cookie, header)
 			if ext = proto.GetExtension(field.Desc.Options(), api.E_Query); ext != "" {
 				paramName = proto.GetExtension(field.Desc.Options(), api.E_Query).(string)
 				paramIn = consts.ParameterInQuery
-				paramDesc = g.filterCommentString(field.Comments.Leading)
+				paramDesc = pbgen.FieldDescription(field)
 				fieldSchema = g.reflect.schemaOrReferenceForField(field.Desc)
 				if schema, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Schema); ok {
 					// Merge any `Property` annotations with the current
 					extProperty := proto.GetExtension(field.Desc.Options(), openapi.E_Property)
+					g.checkOption(field.Desc, openapi.E_Property, extProperty)
 					if extProperty != nil {
 						if property, ok := extProperty.(*openapi.Schema); ok {
-							proto.Merge(schema.Schema, property)
+							common.MergeOptionMessage(schema.Schema, property)
 						} else {
-							logs.Errorf("unexpected type for Property: %T", extProperty)
+							g.diag.ErrorfAt(diagnostics.ProtoLocation(field.Desc, openapi.E_Property), "unexpected type for Property: %T", extProperty)
 						}
 					}
 				}
 			} else if ext = proto.GetExtension(field.Desc.Options(), api.E_Path); ext != "" {
 				paramName = proto.GetExtension(field.Desc.Options(), api.E_Path).(string)
 				paramIn = consts.ParameterInPath
-				paramDesc = g.filterCommentString(field.Comments.Leading)
+				paramDesc = pbgen.FieldDescription(field)
 				fieldSchema = g.reflect.schemaOrReferenceForField(field.Desc)
 				if schema, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Schema); ok {
 					// Merge any `Property` annotations with the current
 					extProperty := proto.GetExtension(field.Desc.Options(), openapi.E_Property)
+					g.checkOption(field.Desc, openapi.E_Property, extProperty)
 					if extProperty != nil {
-						proto.Merge(schema.Schema, extProperty.(*openapi.Schema))
+						common.MergeOptionMessage(schema.Schema, extProperty.(*openapi.Schema))
 					}
 				}
 				// According to the OpenAPI specification, if a path parameter exists, it must be required.
@@ -414,25 +478,27 @@ func (g *OpenAPIGenerator) buildOperation(
 			} else if ext = proto.GetExtension(field.Desc.Options(), api.E_Cookie); ext != "" {
 				paramName = proto.GetExtension(field.Desc.Options(), api.E_Cookie).(string)
 				paramIn = consts.ParameterInCookie
-				paramDesc = g.filterCommentString(field.Comments.Leading)
+				paramDesc = pbgen.FieldDescription(field)
 				fieldSchema = g.reflect.schemaOrReferenceForField(field.Desc)
 				if schema, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Schema); ok {
 					// Merge any `Property` annotations with the current
 					extProperty := proto.GetExtension(field.Desc.Options(), openapi.E_Property)
+					g.checkOption(field.Desc, openapi.E_Property, extProperty)
 					if extProperty != nil {
-						proto.Merge(schema.Schema, extProperty.(*openapi.Schema))
+						common.MergeOptionMessage(schema.Schema, extProperty.(*openapi.Schema))
 					}
 				}
 			} else if ext = proto.GetExtension(field.Desc.Options(), api.E_Header); ext != "" {
 				paramName = proto.GetExtension(field.Desc.Options(), api.E_Header).(string)
 				paramIn = consts.ParameterInHeader
-				paramDesc = g.filterCommentString(field.Comments.Leading)
+				paramDesc = pbgen.FieldDescription(field)
 				fieldSchema = g.reflect.schemaOrReferenceForField(field.Desc)
 				if schema, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Schema); ok {
 					// Merge any `Property` annotations with the current
 					extProperty := proto.GetExtension(field.Desc.Options(), openapi.E_Property)
+					g.checkOption(field.Desc, openapi.E_Property, extProperty)
 					if extProperty != nil {
-						proto.Merge(schema.Schema, extProperty.(*openapi.Schema))
+						common.MergeOptionMessage(schema.Schema, extProperty.(*openapi.Schema))
 					}
 				}
 			}
@@ -441,14 +507,17 @@ func (g *OpenAPIGenerator) buildOperation(
 				In:          paramIn,
 				Description: paramDesc,
 				Required:    required,
+				Deprecated:  pbgen.IsDeprecated(field.Desc, field.Comments),
+				Example:     pbgen.FieldExample(field),
 				Schema:      fieldSchema,
 			}
 			extParameter := proto.GetExtension(field.Desc.Options(), openapi.E_Parameter)
+			g.checkOption(field.Desc, openapi.E_Parameter, extParameter)
 			if extParameter != nil {
 				if parameterExt, ok := extParameter.(*openapi.Parameter); ok {
-					proto.Merge(parameter, parameterExt)
+					common.MergeOptionMessage(parameter, parameterExt)
 				} else {
-					logs.Errorf("unexpected type for Parameter: %T", extParameter)
+					g.diag.ErrorfAt(diagnostics.ProtoLocation(field.Desc, openapi.E_Parameter), "unexpected type for Parameter: %T", extParameter)
 				}
 			}
 
@@ -467,14 +536,14 @@ func (g *OpenAPIGenerator) buildOperation(
 
 			bodySchema := g.getSchemaByOption(inputMessage, api.E_Body)
 
-			if bodySchema != nil && bodySchema.Properties != nil && len(bodySchema.Properties.AdditionalProperties) > 0 {
+			if pbgen.HasProperties(bodySchema) {
 
 				bodyRefSchema := &openapi.NamedSchemaOrReference{
-					Name:  g.reflect.formatMessageName(inputMessage.Desc) + consts.ComponentSchemaSuffixBody,
+					Name:  g.reflect.schemaNameForMessage(inputMessage.Desc) + consts.ComponentSchemaSuffixBody,
 					Value: &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: bodySchema}},
 				}
 
-				bodyRef := consts.ComponentSchemaPrefix + g.reflect.formatMessageName(inputMessage.Desc) + consts.ComponentSchemaSuffixBody
+				bodyRef := consts.ComponentSchemaPrefix + g.reflect.schemaNameForMessage(inputMessage.Desc) + consts.ComponentSchemaSuffixBody
 
 				g.addSchemaToDocument(d, bodyRefSchema)
 
@@ -492,13 +561,13 @@ func (g *OpenAPIGenerator) buildOperation(
 
 			formSchema := g.getSchemaByOption(inputMessage, api.E_Form)
 
-			if formSchema != nil && formSchema.Properties != nil && len(formSchema.Properties.AdditionalProperties) > 0 {
+			if pbgen.HasProperties(formSchema) {
 				formRefSchema := &openapi.NamedSchemaOrReference{
-					Name:  g.reflect.formatMessageName(inputMessage.Desc) + consts.ComponentSchemaSuffixForm,
+					Name:  g.reflect.schemaNameForMessage(inputMessage.Desc) + consts.ComponentSchemaSuffixForm,
 					Value: &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: formSchema}},
 				}
 
-				formRef := consts.ComponentSchemaPrefix + g.reflect.formatMessageName(inputMessage.Desc) + consts.ComponentSchemaSuffixForm
+				formRef := consts.ComponentSchemaPrefix + g.reflect.schemaNameForMessage(inputMessage.Desc) + consts.ComponentSchemaSuffixForm
 
 				g.addSchemaToDocument(d, formRefSchema)
 
@@ -527,13 +596,13 @@ func (g *OpenAPIGenerator) buildOperation(
 
 			rawBodySchema := g.getSchemaByOption(inputMessage, api.E_RawBody)
 
-			if rawBodySchema != nil && rawBodySchema.Properties != nil && len(rawBodySchema.Properties.AdditionalProperties) > 0 {
+			if pbgen.HasProperties(rawBodySchema) {
 				rawBodyRefSchema := &openapi.NamedSchemaOrReference{
-					Name:  g.reflect.formatMessageName(inputMessage.Desc) + consts.ComponentSchemaSuffixRawBody,
+					Name:  g.reflect.schemaNameForMessage(inputMessage.Desc) + consts.ComponentSchemaSuffixRawBody,
 					Value: &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: rawBodySchema}},
 				}
 
-				rawBodyRef := consts.ComponentSchemaPrefix + g.reflect.formatMessageName(inputMessage.Desc) + consts.ComponentSchemaSuffixRawBody
+				rawBodyRef := consts.ComponentSchemaPrefix + g.reflect.schemaNameForMessage(inputMessage.Desc) + consts.ComponentSchemaSuffixRawBody
 
 				g.addSchemaToDocument(d, rawBodyRefSchema)
 
@@ -564,6 +633,7 @@ func (g *OpenAPIGenerator) buildOperation(
 		}
 	}
 
+	g.reflect.direction = directionOutput
 	var responses *openapi.Responses
 
 	if outputMessage != nil {
@@ -607,8 +677,11 @@ func (g *OpenAPIGenerator) buildOperation(
 	re := regexp.MustCompile(`:(\w+)`)
 	path = re.ReplaceAllString(path, `{$1}`)
 
+	// The first sentence of the method comment is the summary.
+	summary, description := common.SplitSummary(description)
 	op := &openapi.Operation{
 		Tags:        []string{tagName},
+		Summary:     summary,
 		Description: description,
 		OperationId: operationID,
 		Parameters:  parameters,
@@ -629,10 +702,15 @@ func (g *OpenAPIGenerator) getResponseForMessage(d *openapi.Document, message *p
 	headers := &openapi.HeadersOrReferences{AdditionalProperties: []*openapi.NamedHeaderOrReference{}}
 
 	for _, field := range message.Fields {
+		if g.reflect.hiddenField(field.Desc) {
+			continue
+		}
 		if ext := proto.GetExtension(field.Desc.Options(), api.E_Header); ext != "" {
 			headerName := proto.GetExtension(field.Desc.Options(), api.E_Header).(string)
 			header := &openapi.Header{
-				Description: g.filterCommentString(field.Comments.Leading),
+				Description: pbgen.FieldDescription(field),
+				Deprecated:  pbgen.IsDeprecated(field.Desc, field.Comments),
+				Example:     pbgen.FieldExample(field),
 				Schema:      g.reflect.schemaOrReferenceForField(field.Desc),
 			}
 			headers.AdditionalProperties = append(headers.AdditionalProperties, &openapi.NamedHeaderOrReference{
@@ -652,12 +730,12 @@ func (g *OpenAPIGenerator) getResponseForMessage(d *openapi.Document, message *p
 
 	var additionalProperties []*openapi.NamedMediaType
 
-	if bodySchema != nil && bodySchema.Properties != nil && len(bodySchema.Properties.AdditionalProperties) > 0 {
+	if pbgen.HasProperties(bodySchema) {
 		refSchema := &openapi.NamedSchemaOrReference{
-			Name:  g.reflect.formatMessageName(message.Desc) + consts.ComponentSchemaSuffixBody,
+			Name:  g.reflect.schemaNameForMessage(message.Desc) + consts.ComponentSchemaSuffixBody,
 			Value: &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: bodySchema}},
 		}
-		ref := consts.ComponentSchemaPrefix + g.reflect.formatMessageName(message.Desc) + consts.ComponentSchemaSuffixBody
+		ref := consts.ComponentSchemaPrefix + g.reflect.schemaNameForMessage(message.Desc) + consts.ComponentSchemaSuffixBody
 		g.addSchemaToDocument(d, refSchema)
 		additionalProperties = append(additionalProperties, &openapi.NamedMediaType{
 			Name: consts.ContentTypeJSON,
@@ -671,12 +749,12 @@ func (g *OpenAPIGenerator) getResponseForMessage(d *openapi.Document, message *p
 		})
 	}
 
-	if rawBodySchema != nil && rawBodySchema.Properties != nil && len(rawBodySchema.Properties.AdditionalProperties) > 0 {
+	if pbgen.HasProperties(rawBodySchema) {
 		refSchema := &openapi.NamedSchemaOrReference{
-			Name:  g.reflect.formatMessageName(message.Desc) + consts.ComponentSchemaSuffixRawBody,
+			Name:  g.reflect.schemaNameForMessage(message.Desc) + consts.ComponentSchemaSuffixRawBody,
 			Value: &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: rawBodySchema}},
 		}
-		ref := consts.ComponentSchemaPrefix + g.reflect.formatMessageName(message.Desc) + consts.ComponentSchemaSuffixRawBody
+		ref := consts.ComponentSchemaPrefix + g.reflect.schemaNameForMessage(message.Desc) + consts.ComponentSchemaSuffixRawBody
 		g.addSchemaToDocument(d, refSchema)
 		additionalProperties = append(additionalProperties, &openapi.NamedMediaType{
 			Name: consts.ContentTypeRawBody,
@@ -690,6 +768,14 @@ func (g *OpenAPIGenerator) getResponseForMessage(d *openapi.Document, message *p
 		})
 	}
 
+	// google.protobuf.Empty is returned as an empty JSON object.
+	if len(additionalProperties) == 0 && message.Desc.FullName() == emptyFullName {
+		additionalProperties = append(additionalProperties, &openapi.NamedMediaType{
+			Name:  consts.ContentTypeJSON,
+			Value: &openapi.MediaType{Schema: wk.NewGoogleProtobufEmptySchema()},
+		})
+	}
+
 	content := &openapi.MediaTypes{
 		AdditionalProperties: additionalProperties,
 	}
@@ -739,26 +825,27 @@ func (g *OpenAPIGenerator) addPathsToDocument(d *openapi.Document, services []*p
 			inputMessage := method.Input
 			outputMessage := method.Output
 			operationID := service.GoName + "_" + method.GoName
+			host := methodHost(service, method)
 			rs := api.GetAllOptions(api.HttpMethodOptions, method.Desc.Options())
 			for methodName, path := range rs {
 				if methodName != "" {
 					annotationsCount++
-					var host string
-					host = proto.GetExtension(method.Desc.Options(), api.E_Baseurl).(string)
-
-					if host == "" {
-						host = proto.GetExtension(service.Desc.Options(), api.E_BaseDomain).(string)
-					}
 					op, path2 := g.buildOperation(d, methodName, operationID, service.GoName, comment, host, path.(string), inputMessage, outputMessage)
+					g.addErrorResponses(d, service, method, op)
+					g.addDefaultResponse(method, op)
+					pbgen.ApplyStreaming(method, op, pbgen.LookupStreamContentType(*g.conf.StreamContentType, g.diag), *g.conf.DisableStreamingTryItOut)
+					pbgen.ApplyDeprecation(service, method, op)
 					// Merge any `Operation` annotations with the current
 					extOperation := proto.GetExtension(method.Desc.Options(), openapi.E_Operation)
+					g.checkOption(method.Desc, openapi.E_Operation, extOperation)
 
 					if extOperation != nil {
-						proto.Merge(op, extOperation.(*openapi.Operation))
+						common.MergeOptionMessage(op, extOperation.(*openapi.Operation))
 					}
 					g.addOperationToDocument(d, op, path2, methodName)
 				}
 			}
+			annotationsCount += g.addHttpRuleOperations(d, service, method, len(rs), host)
 		}
 		if annotationsCount > 0 {
 			comment := g.filterCommentString(service.Comments.Leading)
@@ -784,136 +871,92 @@ func (g *OpenAPIGenerator) addSchemasForMessagesToDocument(d *openapi.Document,
 			g.addSchemasForMessagesToDocument(d, message.Messages)
 		}
 
-		schemaName := g.reflect.formatMessageName(message.Desc)
+		// Messages split by direction have a schema per direction.
+		for _, direction := range []string{"", directionInput, directionOutput} {
+			schemaName := g.reflect.formatMessageName(message.Desc) + direction
 
-		// Only generate this if we need it and haven't already generated it.
-		if !common.Contains(g.reflect.requiredSchemas, schemaName) ||
-			common.Contains(g.generatedSchemas, schemaName) {
-			continue
-		}
-
-		typeName := g.reflect.fullMessageTypeName(message.Desc)
-		messageDescription := g.filterCommentString(message.Comments.Leading)
-
-		// `google.protobuf.Value` and `google.protobuf.Any` have special JSON transcoding
-		// so we can't just reflect on the message descriptor.
-		if typeName == ".google.protobuf.Value" {
-			g.addSchemaToDocument(d, wk.NewGoogleProtobufValueSchema(schemaName))
-			continue
-		} else if typeName == ".google.protobuf.Any" {
-			g.addSchemaToDocument(d, wk.NewGoogleProtobufAnySchema(schemaName))
-			continue
-		} else if typeName == ".google.rpc.Status" {
-			anySchemaName := g.reflect.formatMessageName(anyProtoDesc)
-			g.addSchemaToDocument(d, wk.NewGoogleProtobufAnySchema(anySchemaName))
-			g.addSchemaToDocument(d, wk.NewGoogleRpcStatusSchema(schemaName, anySchemaName))
-			continue
-		}
-
-		// Build an array holding the fields of the message.
-		definitionProperties := &openapi.Properties{
-			AdditionalProperties: make([]*openapi.NamedSchemaOrReference, 0),
-		}
-
-		var required []string
-		for _, field := range message.Fields {
-			// Get the field description from the comments.
-			description := g.filterCommentString(field.Comments.Leading)
-			// Check the field annotations to see if this is a readonly or writeonly field.
-			inputOnly := false
-			outputOnly := false
-			extension := proto.GetExtension(field.Desc.Options(), annotations.E_FieldBehavior)
-			if extension != nil {
-				switch v := extension.(type) {
-				case []annotations.FieldBehavior:
-					for _, vv := range v {
-						switch vv {
-						case annotations.FieldBehavior_OUTPUT_ONLY:
-							outputOnly = true
-						case annotations.FieldBehavior_INPUT_ONLY:
-							inputOnly = true
-						case annotations.FieldBehavior_REQUIRED:
-							required = append(required, g.reflect.formatFieldName(field.Desc))
-						}
-					}
-				default:
-					logs.Error("unsupported extension type %T", extension)
-				}
-			}
-
-			// The field is either described by a reference or a schema.
-			fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
-			if fieldSchema == nil {
+			// Only generate this if we need it and haven't already generated it.
+			if !common.Contains(g.reflect.requiredSchemas, schemaName) ||
+				common.Contains(g.generatedSchemas, schemaName) {
 				continue
 			}
+			g.reflect.direction = direction
+			g.addSchemaForMessage(d, message, schemaName)
+			g.reflect.direction = ""
+		}
+	}
+}
 
-			// If this field has siblings and is a $ref now, create a new schema use `allOf` to wrap it
-			wrapperNeeded := inputOnly || outputOnly || description != ""
-			if wrapperNeeded {
-				if _, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Reference); ok {
-					fieldSchema = &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{
-						AllOf: []*openapi.SchemaOrReference{fieldSchema},
-					}}}
-				}
-			}
+// addSchemaForMessage adds the schema of message named schemaName, built for
+// the current direction of the reflector.
+func (g *OpenAPIGenerator) addSchemaForMessage(d *openapi.Document, message *protogen.Message, schemaName string) {
+	typeName := g.reflect.fullMessageTypeName(message.Desc)
+	messageDescription := g.filterCommentString(message.Comments.Leading)
 
-			if schema, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Schema); ok {
-				schema.Schema.Description = description
-				schema.Schema.ReadOnly = outputOnly
-				schema.Schema.WriteOnly = inputOnly
+	// `google.protobuf.Value` and `google.protobuf.Any` have special JSON transcoding
+	// so we can't just reflect on the message descriptor.
+	if typeName == ".google.protobuf.Value" {
+		g.addSchemaToDocument(d, wk.NewGoogleProtobufValueSchema(schemaName))
+		return
+	} else if typeName == ".google.protobuf.Any" {
+		g.addSchemaToDocument(d, wk.NewGoogleProtobufAnySchema(schemaName))
+		return
+	} else if typeName == ".google.rpc.Status" {
+		anySchemaName := g.reflect.formatMessageName(anyProtoDesc)
+		g.addSchemaToDocument(d, wk.NewGoogleProtobufAnySchema(anySchemaName))
+		g.addSchemaToDocument(d, wk.NewGoogleRpcStatusSchema(schemaName, anySchemaName))
+		return
+	}
 
-				// Merge any `Property` annotations with the current
-				extProperty := proto.GetExtension(field.Desc.Options(), openapi.E_Property)
-				if extProperty != nil {
-					proto.Merge(schema.Schema, extProperty.(*openapi.Schema))
-				}
-			}
-			var name string
-			if ext := proto.GetExtension(field.Desc.Options(), api.E_Header); ext != "" {
-				name = proto.GetExtension(field.Desc.Options(), api.E_Header).(string)
+	// The fields are named after their binding, if any.
+	definitionProperties, required, oneofs := g.fieldProperties(message, func(field *protogen.Field) string {
+		var name string
+		for _, binding := range []*protoimpl.ExtensionInfo{api.E_Header, api.E_Body, api.E_Form, api.E_RawBody} {
+			if ext := proto.GetExtension(field.Desc.Options(), binding).(string); ext != "" {
+				name = ext
 			}
-			if ext := proto.GetExtension(field.Desc.Options(), api.E_Body); ext != "" {
-				name = proto.GetExtension(field.Desc.Options(), api.E_Body).(string)
-			}
-			if ext := proto.GetExtension(field.Desc.Options(), api.E_Form); ext != "" {
-				name = proto.GetExtension(field.Desc.Options(), api.E_Form).(string)
-			}
-			if ext := proto.GetExtension(field.Desc.Options(), api.E_RawBody); ext != "" {
-				name = proto.GetExtension(field.Desc.Options(), api.E_RawBody).(string)
-			}
-			if name == "" {
-				name = g.reflect.formatFieldName(field.Desc)
-			}
-			definitionProperties.AdditionalProperties = append(
-				definitionProperties.AdditionalProperties,
-				&openapi.NamedSchemaOrReference{
-					Name:  name,
-					Value: fieldSchema,
-				},
-			)
 		}
-
-		schema := &openapi.Schema{
-			Type:        consts.SchemaObjectType,
-			Description: messageDescription,
-			Properties:  definitionProperties,
-			Required:    required,
+		if name == "" {
+			name = g.reflect.formatFieldName(field.Desc)
 		}
+		return name
+	}, nil)
 
-		// Merge any `Schema` annotations with the current
-		extSchema := proto.GetExtension(message.Desc.Options(), openapi.E_Schema)
-		if extSchema != nil {
-			proto.Merge(schema, extSchema.(*openapi.Schema))
-		}
+	schema := &openapi.Schema{
+		Type:        consts.SchemaObjectType,
+		Description: messageDescription,
+		Properties:  definitionProperties,
+		Required:    required,
+		Deprecated:  pbgen.IsDeprecated(message.Desc, message.Comments),
+	}
 
-		// Add the schema to the components.schema list.
-		g.addSchemaToDocument(d, &openapi.NamedSchemaOrReference{
-			Name: schemaName,
-			Value: &openapi.SchemaOrReference{
-				Oneof: &openapi.SchemaOrReference_Schema{
-					Schema: schema,
-				},
+	// Merge any `Schema` annotations with the current
+	extSchema := proto.GetExtension(message.Desc.Options(), openapi.E_Schema)
+	g.checkOption(message.Desc, openapi.E_Schema, extSchema)
+	if extSchema != nil {
+		common.MergeOptionMessage(schema, extSchema.(*openapi.Schema))
+	}
+	oneofs.Apply(schema)
+
+	// Add the schema to the components.schema list.
+	g.addSchemaToDocument(d, &openapi.NamedSchemaOrReference{
+		Name: schemaName,
+		Value: &openapi.SchemaOrReference{
+			Oneof: &openapi.SchemaOrReference_Schema{
+				Schema: schema,
 			},
-		})
+		},
+	})
+}
+
+// checkOption reports the problems common.CheckOptionMessage finds in the
+// value of the openapi extension ext set on desc.
+func (g *OpenAPIGenerator) checkOption(desc protoreflect.Descriptor, ext protoreflect.ExtensionType, value interface{}) {
+	m, ok := value.(proto.Message)
+	if !ok {
+		return
+	}
+	if err := common.CheckOptionMessage(m); err != nil {
+		g.diag.ErrorfAt(diagnostics.ProtoLocation(desc, ext), "Error parsing %s option: %s", ext.TypeDescriptor().FullName(), err)
 	}
 }
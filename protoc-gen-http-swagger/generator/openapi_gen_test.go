@@ -0,0 +1,122 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/api"
+	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// testFieldsMessage builds a message whose fields are bound to the body under
+// another name, not bound, required by their behavior and output only.
+func testFieldsMessage() *descriptorpb.DescriptorProto {
+	field := func(number int32, name, body string, behaviors ...annotations.FieldBehavior) *descriptorpb.FieldDescriptorProto {
+		options := &descriptorpb.FieldOptions{}
+		if body != "" {
+			proto.SetExtension(options, api.E_Body, body)
+		}
+		if len(behaviors) > 0 {
+			proto.SetExtension(options, annotations.E_FieldBehavior, behaviors)
+		}
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Options:  options,
+		}
+	}
+	options := &descriptorpb.MessageOptions{}
+	proto.SetExtension(options, openapi.E_Schema, &openapi.Schema{Required: []string{"bound"}})
+	return &descriptorpb.DescriptorProto{
+		Name: proto.String("Req"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			field(1, "a", "bound"),
+			field(2, "b", ""),
+			field(3, "c", "c", annotations.FieldBehavior_REQUIRED),
+			field(4, "d", "d", annotations.FieldBehavior_OUTPUT_ONLY),
+		},
+		Options: options,
+	}
+}
+
+func propertyNames(schema *openapi.Schema) []string {
+	var names []string
+	for _, property := range schema.Properties.AdditionalProperties {
+		names = append(names, property.Name)
+	}
+	return names
+}
+
+func TestFieldProperties(t *testing.T) {
+	message := testFile(t, testFieldsMessage()).Messages[0]
+	tests := []struct {
+		name           string
+		direction      string
+		schema         func(g *OpenAPIGenerator) *openapi.Schema
+		wantProperties []string
+		wantRequired   []string
+	}{
+		{
+			name:           "body",
+			schema:         func(g *OpenAPIGenerator) *openapi.Schema { return g.getSchemaByOption(message, api.E_Body) },
+			wantProperties: []string{"bound", "c", "d"},
+			wantRequired:   []string{"bound", "c"},
+		},
+		{
+			name:           "input body",
+			direction:      directionInput,
+			schema:         func(g *OpenAPIGenerator) *openapi.Schema { return g.getSchemaByOption(message, api.E_Body) },
+			wantProperties: []string{"bound", "c"},
+			wantRequired:   []string{"bound", "c"},
+		},
+		{
+			name: "message",
+			schema: func(g *OpenAPIGenerator) *openapi.Schema {
+				d := &openapi.Document{Components: &openapi.Components{Schemas: &openapi.SchemasOrReferences{}}}
+				g.addSchemaForMessage(d, message, "Req")
+				return d.Components.Schemas.AdditionalProperties[0].Value.GetSchema()
+			},
+			wantProperties: []string{"bound", "b", "c", "d"},
+			// The required fields of the annotation are merged after the
+			// generated ones.
+			wantRequired: []string{"c", "bound"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := testGenerator(diagnostics.NewCollector())
+			*g.conf.SplitIOSchemas = true
+			g.reflect.direction = tt.direction
+			schema := tt.schema(g)
+			if got := propertyNames(schema); !reflect.DeepEqual(got, tt.wantProperties) {
+				t.Errorf("properties = %v, want %v", got, tt.wantProperties)
+			}
+			if !reflect.DeepEqual(schema.Required, tt.wantRequired) {
+				t.Errorf("required = %v, want %v", schema.Required, tt.wantRequired)
+			}
+		})
+	}
+}
@@ -34,27 +34,55 @@
 package generator
 
 import (
-	"log"
 	"strings"
 
 	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"github.com/hertz-contrib/swagger-generate/common/pbgen"
 	common "github.com/hertz-contrib/swagger-generate/common/utils"
 	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
 	wk "github.com/hertz-contrib/swagger-generate/protoc-gen-http-swagger/generator/wellknown"
 	"github.com/hertz-contrib/swagger-generate/protoc-gen-http-swagger/utils"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
+const (
+	// nullValueFullName is the enum whose values are all serialized as null.
+	nullValueFullName = "google.protobuf.NullValue"
+	// emptyFullName is the message serialized as an empty object.
+	emptyFullName = "google.protobuf.Empty"
+)
+
+// Directions of the schemas split by the `split_io_schemas` option, used as
+// suffix of their names.
+const (
+	directionInput  = "Input"
+	directionOutput = "Output"
+)
+
 type OpenAPIReflector struct {
 	conf            Configuration
-	requiredSchemas []string // Names of schemas which are used through references.
+	diag            *diagnostics.Collector
+	requiredSchemas []string                       // Names of schemas which are used through references.
+	customSchemas   map[string]*openapi.Schema     // Schemas of the `schema_file` option, by full message name.
+	direction       string                         // Direction of the schemas being built, for `split_io_schemas`.
+	directional     map[protoreflect.FullName]bool // Whether messages reach input or output only fields, by full name.
 }
 
 // NewOpenAPIReflector creates a new reflector.
-func NewOpenAPIReflector(conf Configuration) *OpenAPIReflector {
+func NewOpenAPIReflector(conf Configuration, diag *diagnostics.Collector) *OpenAPIReflector {
+	customSchemas, err := pbgen.LoadSchemaFile(*conf.SchemaFile)
+	if err != nil {
+		diag.Errorf("schema_file: %s", err)
+	}
 	return &OpenAPIReflector{
 		conf:            conf,
+		diag:            diag,
 		requiredSchemas: make([]string, 0),
+		customSchemas:   customSchemas,
+		directional:     map[protoreflect.FullName]bool{},
 	}
 }
 
@@ -112,8 +140,85 @@ func (r *OpenAPIReflector) fullMessageTypeName(message protoreflect.MessageDescr
 	return "." + string(message.ParentFile().Package()) + "." + name
 }
 
+// schemaNameForMessage returns the name of the schema of message. Messages
+// split by direction get the suffix of the current direction.
+func (r *OpenAPIReflector) schemaNameForMessage(message protoreflect.MessageDescriptor) string {
+	name := r.formatMessageName(message)
+	if r.direction != "" && r.splitsByDirection(message) {
+		name += r.direction
+	}
+	return name
+}
+
+// splitsByDirection reports whether message gets separate Input and Output
+// schemas, because it has input only or output only fields, directly or
+// through the messages it uses.
+func (r *OpenAPIReflector) splitsByDirection(message protoreflect.MessageDescriptor) bool {
+	if !*r.conf.SplitIOSchemas {
+		return false
+	}
+	split, ok := r.directional[message.FullName()]
+	if !ok {
+		split = r.hasDirectionalFields(message, map[protoreflect.FullName]bool{})
+		r.directional[message.FullName()] = split
+	}
+	return split
+}
+
+// hasDirectionalFields walks the messages used by message that are not
+// visited yet. The messages found to have directional fields are cached on
+// the way, but not the others: a message whose walk is cut by a cycle may
+// still reach directional fields through the message that started it.
+func (r *OpenAPIReflector) hasDirectionalFields(message protoreflect.MessageDescriptor, visited map[protoreflect.FullName]bool) bool {
+	if split, ok := r.directional[message.FullName()]; ok {
+		return split
+	}
+	if visited[message.FullName()] {
+		return false
+	}
+	visited[message.FullName()] = true
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if field.IsMap() {
+			field = field.MapValue()
+		}
+		inputOnly, outputOnly := fieldBehavior(fields.Get(i))
+		if inputOnly || outputOnly || field.Message() != nil && r.hasDirectionalFields(field.Message(), visited) {
+			r.directional[message.FullName()] = true
+			return true
+		}
+	}
+	return false
+}
+
+// hiddenField reports whether field is left out of the schemas being built,
+// because it is only visible in the other direction.
+func (r *OpenAPIReflector) hiddenField(field protoreflect.FieldDescriptor) bool {
+	if !*r.conf.SplitIOSchemas || r.direction == "" {
+		return false
+	}
+	inputOnly, outputOnly := fieldBehavior(field)
+	return r.direction == directionInput && outputOnly || r.direction == directionOutput && inputOnly
+}
+
+// fieldBehavior returns whether field is marked INPUT_ONLY or OUTPUT_ONLY by
+// `google.api.field_behavior`.
+func fieldBehavior(field protoreflect.FieldDescriptor) (inputOnly, outputOnly bool) {
+	behaviors, _ := proto.GetExtension(field.Options(), annotations.E_FieldBehavior).([]annotations.FieldBehavior)
+	for _, behavior := range behaviors {
+		switch behavior {
+		case annotations.FieldBehavior_INPUT_ONLY:
+			inputOnly = true
+		case annotations.FieldBehavior_OUTPUT_ONLY:
+			outputOnly = true
+		}
+	}
+	return inputOnly, outputOnly
+}
+
 func (r *OpenAPIReflector) schemaReferenceForMessage(message protoreflect.MessageDescriptor) string {
-	schemaName := r.formatMessageName(message)
+	schemaName := r.schemaNameForMessage(message)
 	if !common.Contains(r.requiredSchemas, schemaName) {
 		r.requiredSchemas = append(r.requiredSchemas, schemaName)
 	}
@@ -123,57 +228,95 @@ func (r *OpenAPIReflector) schemaReferenceForMessage(message protoreflect.Messag
 // Returns a full schema for simple types, and a schema reference for complex types that reference
 // the definition in `#/components/schemas/`
 func (r *OpenAPIReflector) schemaOrReferenceForMessage(message protoreflect.MessageDescriptor) *openapi.SchemaOrReference {
+	if schema, ok := r.customSchemas[string(message.FullName())]; ok {
+		return &openapi.SchemaOrReference{
+			Oneof: &openapi.SchemaOrReference_Schema{Schema: proto.Clone(schema).(*openapi.Schema)},
+		}
+	}
+	if schema, ok := r.wellKnownSchemaForMessage(message); ok {
+		return schema
+	}
+	ref := r.schemaReferenceForMessage(message)
+	return &openapi.SchemaOrReference{
+		Oneof: &openapi.SchemaOrReference_Reference{
+			Reference: &openapi.Reference{XRef: ref},
+		},
+	}
+}
+
+// wellKnownSchemaForMessage returns the schema of the well-known types that
+// have a special JSON mapping. The schema is nil for types that should be
+// ignored, such as google.protobuf.Empty.
+func (r *OpenAPIReflector) wellKnownSchemaForMessage(message protoreflect.MessageDescriptor) (*openapi.SchemaOrReference, bool) {
 	typeName := r.fullMessageTypeName(message)
 
 	switch typeName {
 
 	case ".google.api.HttpBody":
-		return wk.NewGoogleApiHttpBodySchema()
+		return wk.NewGoogleApiHttpBodySchema(), true
 
 	case ".google.protobuf.Timestamp":
-		return wk.NewGoogleProtobufTimestampSchema()
+		return wk.NewGoogleProtobufTimestampSchema(), true
 
 	case ".google.protobuf.Duration":
-		return wk.NewGoogleProtobufDurationSchema()
+		return wk.NewGoogleProtobufDurationSchema(), true
 
 	case ".google.type.Date":
-		return wk.NewGoogleTypeDateSchema()
+		return wk.NewGoogleTypeDateSchema(), true
 
 	case ".google.type.DateTime":
-		return wk.NewGoogleTypeDateTimeSchema()
+		return wk.NewGoogleTypeDateTimeSchema(), true
+
+	case ".google.type.TimeOfDay":
+		return wk.NewGoogleTypeTimeOfDaySchema(), true
+
+	case ".google.type.Interval":
+		return wk.NewGoogleTypeIntervalSchema(), true
+
+	case ".google.type.Money":
+		return wk.NewGoogleTypeMoneySchema(), true
+
+	case ".google.type.LatLng":
+		return wk.NewGoogleTypeLatLngSchema(), true
+
+	case ".google.type.Color":
+		return wk.NewGoogleTypeColorSchema(), true
+
+	case ".google.type.PostalAddress":
+		return wk.NewGoogleTypePostalAddressSchema(), true
 
 	case ".google.protobuf.FieldMask":
-		return wk.NewGoogleProtobufFieldMaskSchema()
+		return wk.NewGoogleProtobufFieldMaskSchema(), true
 
 	case ".google.protobuf.Struct":
-		return wk.NewGoogleProtobufStructSchema()
+		return wk.NewGoogleProtobufStructSchema(), true
+
+	case ".google.protobuf.ListValue":
+		// The values are google.protobuf.Value, which has its own schema.
+		value := message.Fields().ByName("values").Message()
+		return wk.NewGoogleProtobufListValueSchema(r.schemaReferenceForMessage(value)), true
 
 	case ".google.protobuf.Empty":
 		// Empty is closer to JSON undefined than null, so ignore this field
-		return nil //&v3.SchemaOrReference{Oneof: &v3.SchemaOrReference_Schema{Schema: &v3.Schema{Type: "null"}}}
+		return nil, true //&v3.SchemaOrReference{Oneof: &v3.SchemaOrReference_Schema{Schema: &v3.Schema{Type: "null"}}}
 
 	case ".google.protobuf.BoolValue":
-		return wk.NewBooleanSchema()
+		return wk.NewBooleanSchema(), true
 
 	case ".google.protobuf.BytesValue":
-		return wk.NewBytesSchema()
+		return wk.NewBytesSchema(), true
 
 	case ".google.protobuf.Int32Value", ".google.protobuf.UInt32Value":
-		return wk.NewIntegerSchema(utils.GetValueKind(message))
+		return wk.NewIntegerSchema(utils.GetValueKind(message)), true
 
 	case ".google.protobuf.StringValue", ".google.protobuf.Int64Value", ".google.protobuf.UInt64Value":
-		return wk.NewStringSchema()
+		return wk.NewStringSchema(), true
 
 	case ".google.protobuf.FloatValue", ".google.protobuf.DoubleValue":
-		return wk.NewNumberSchema(utils.GetValueKind(message))
+		return wk.NewNumberSchema(utils.GetValueKind(message)), true
 
 	default:
-		ref := r.schemaReferenceForMessage(message)
-		return &openapi.SchemaOrReference{
-			Oneof: &openapi.SchemaOrReference_Reference{
-				Reference: &openapi.Reference{XRef: ref},
-			},
-		}
+		return nil, false
 	}
 }
 
@@ -216,7 +359,11 @@ func (r *OpenAPIReflector) schemaOrReferenceForField(field protoreflect.FieldDes
 		kindSchema = wk.NewStringSchema()
 
 	case protoreflect.EnumKind:
-		kindSchema = wk.NewEnumSchema(*&r.conf.EnumType, field)
+		if field.Enum().FullName() == nullValueFullName {
+			kindSchema = wk.NewGoogleProtobufNullValueSchema()
+		} else {
+			kindSchema = wk.NewEnumSchema(*&r.conf.EnumType, field)
+		}
 
 	case protoreflect.BoolKind:
 		kindSchema = wk.NewBooleanSchema()
@@ -228,11 +375,19 @@ func (r *OpenAPIReflector) schemaOrReferenceForField(field protoreflect.FieldDes
 		kindSchema = wk.NewBytesSchema()
 
 	default:
-		log.Printf("(TODO) Unsupported field type: %+v", r.fullMessageTypeName(field.Message()))
+		r.diag.ErrorfAt(diagnostics.ProtoLocation(field, nil), "unsupported field type %s", kind)
 	}
 
 	if field.IsList() {
 		kindSchema = wk.NewListSchema(kindSchema)
+	} else if schema, ok := kindSchema.GetOneof().(*openapi.SchemaOrReference_Schema); ok {
+		// Scalars document their proto2 or Editions default and presence.
+		if d := pbgen.DefaultForField(field, *r.conf.EnumType); d != nil {
+			schema.Schema.Default = d
+		}
+		if pbgen.FieldNullable(field) {
+			schema.Schema.Nullable = true
+		}
 	}
 
 	return kindSchema
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestSplitsByDirection(t *testing.T) {
+	field := func(number int32, name, message string, behaviors ...annotations.FieldBehavior) *descriptorpb.FieldDescriptorProto {
+		options := &descriptorpb.FieldOptions{}
+		if len(behaviors) > 0 {
+			proto.SetExtension(options, annotations.E_FieldBehavior, behaviors)
+		}
+		field := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Options:  options,
+		}
+		if message != "" {
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+			field.TypeName = proto.String(".test." + message)
+		}
+		return field
+	}
+	message := func(name string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+		return &descriptorpb.DescriptorProto{Name: proto.String(name), Field: fields}
+	}
+	// A and B use each other, and A reaches the output only field of C; D
+	// only uses itself.
+	file := testFile(t,
+		message("A", field(1, "b", "B"), field(2, "c", "C")),
+		message("B", field(1, "a", "A")),
+		message("C", field(1, "id", "", annotations.FieldBehavior_OUTPUT_ONLY)),
+		message("D", field(1, "d", "D"), field(2, "name", "")),
+	)
+	want := map[string]bool{"A": true, "B": true, "C": true, "D": false}
+
+	split := true
+	r := NewOpenAPIReflector(Configuration{SchemaFile: new(string), SplitIOSchemas: &split}, diagnostics.NewCollector())
+	// The walk of A cuts B at the cycle before reaching C, which must not be
+	// cached as B having no directional fields.
+	for _, order := range [][]int{{0, 1, 2, 3}, {3, 2, 1, 0}} {
+		for _, i := range order {
+			message := file.Messages[i]
+			if got := r.splitsByDirection(message.Desc); got != want[message.GoIdent.GoName] {
+				t.Errorf("splitsByDirection(%s) = %v, want %v", message.GoIdent.GoName, got, want[message.GoIdent.GoName])
+			}
+		}
+	}
+
+	split = false
+	if r.splitsByDirection(file.Messages[0].Desc) {
+		t.Errorf("splitsByDirection(A) = true without split_io_schemas")
+	}
+}
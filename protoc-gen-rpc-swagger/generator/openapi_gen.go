@@ -39,26 +39,38 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/cloudwego/hertz/cmd/hz/util/logs"
 	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"github.com/hertz-contrib/swagger-generate/common/pbgen"
 	common "github.com/hertz-contrib/swagger-generate/common/utils"
+	"github.com/hertz-contrib/swagger-generate/common/validator"
 	"github.com/hertz-contrib/swagger-generate/idl/protobuf/api"
 	"github.com/hertz-contrib/swagger-generate/idl/protobuf/openapi"
 	wk "github.com/hertz-contrib/swagger-generate/protoc-gen-rpc-swagger/generator/wellknown"
 	"google.golang.org/genproto/googleapis/api/annotations"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	any_pb "google.golang.org/protobuf/types/known/anypb"
 )
 
 type Configuration struct {
-	Version        *string
-	Title          *string
-	Description    *string
-	Naming         *string
-	FQSchemaNaming *bool
-	EnumType       *string
-	OutputMode     *string
+	Version                  *string
+	Title                    *string
+	Description              *string
+	Naming                   *string
+	FQSchemaNaming           *bool
+	EnumType                 *string
+	OutputMode               *string
+	OneofStyle               *string
+	DefaultResponse          *string
+	SchemaFile               *string
+	SplitIOSchemas           *bool
+	StreamContentType        *string
+	PathStyle                *string
+	MetainfoStyle            *string
+	DisableStreamingTryItOut *bool
+	Strict                   *bool
 }
 
 // In order to dynamically add google.rpc.Status responses we need
@@ -70,33 +82,38 @@ var (
 
 // OpenAPIGenerator holds internal state needed to generate an OpenAPIv3 document for a transcoded Protocol Buffer service.
 type OpenAPIGenerator struct {
-	conf              Configuration
-	plugin            *protogen.Plugin
-	inputFiles        []*protogen.File
-	reflect           *OpenAPIReflector
-	generatedSchemas  []string // Names of schemas that have already been generated.
-	linterRulePattern *regexp.Regexp
+	conf             Configuration
+	plugin           *protogen.Plugin
+	inputFiles       []*protogen.File
+	diag             *diagnostics.Collector
+	reflect          *OpenAPIReflector
+	generatedSchemas []string          // Names of schemas that have already been generated.
+	defaultResponse  *openapi.Response // Response added as `default` to every operation.
+	errorStatuses    map[string]map[int32]int
 }
 
 // NewOpenAPIGenerator creates a new generator for a protoc plugin invocation.
-func NewOpenAPIGenerator(plugin *protogen.Plugin, conf Configuration, inputFiles []*protogen.File) *OpenAPIGenerator {
+func NewOpenAPIGenerator(plugin *protogen.Plugin, conf Configuration, inputFiles []*protogen.File, diag *diagnostics.Collector) *OpenAPIGenerator {
 	return &OpenAPIGenerator{
-		conf:              conf,
-		plugin:            plugin,
-		inputFiles:        inputFiles,
-		reflect:           NewOpenAPIReflector(conf),
-		generatedSchemas:  make([]string, 0),
-		linterRulePattern: regexp.MustCompile(`\(-- .* --\)`),
+		conf:             conf,
+		plugin:           plugin,
+		inputFiles:       inputFiles,
+		diag:             diag,
+		reflect:          NewOpenAPIReflector(conf, diag),
+		generatedSchemas: make([]string, 0),
+		errorStatuses:    map[string]map[int32]int{},
 	}
 }
 
-// Run runs the generator.
-func (g *OpenAPIGenerator) Run(outputFile *protogen.GeneratedFile) error {
+// Run runs the generator and writes the document to outputFile, which is
+// named filename in the diagnostics of the document validation.
+func (g *OpenAPIGenerator) Run(outputFile *protogen.GeneratedFile, filename string) error {
 	d := g.buildDocument()
 	bytes, err := d.YAMLValue("Generated with " + consts.PluginNameProtocRpcSwagger + "\n" + consts.InfoURL + "blob/main/" + consts.PluginNameProtocRpcSwagger)
 	if err != nil {
 		return fmt.Errorf("failed to marshal yaml: %s", err.Error())
 	}
+	validator.Validate(filename, bytes, g.diag)
 	if _, err = outputFile.Write(bytes); err != nil {
 		return fmt.Errorf("failed to write yaml: %s", err.Error())
 	}
@@ -120,6 +137,7 @@ func (g *OpenAPIGenerator) buildDocument() *openapi.Document {
 			AdditionalProperties: []*openapi.NamedSchemaOrReference{},
 		},
 	}
+	g.defaultResponse = g.buildDefaultResponse(d)
 
 	// Go through the files and add the services to the documents, keeping
 	// track of which schemas are referenced in the response so we can
@@ -128,11 +146,12 @@ func (g *OpenAPIGenerator) buildDocument() *openapi.Document {
 		if file.Generate {
 			// Merge any `Document` annotations with the current
 			extDocument := proto.GetExtension(file.Desc.Options(), openapi.E_Document)
+			g.checkOption(file.Desc, openapi.E_Document, extDocument)
 			if extDocument != nil {
 				if doc, ok := extDocument.(*openapi.Document); ok {
-					proto.Merge(d, doc)
+					common.MergeOptionMessage(d, doc)
 				} else {
-					logs.Errorf("unexpected type for Document: %T", extDocument)
+					g.diag.ErrorfAt(diagnostics.ProtoLocation(file.Desc, openapi.E_Document), "unexpected type for Document: %T", extDocument)
 				}
 			}
 			g.addPathsToDocument(d, file.Services)
@@ -228,10 +247,9 @@ func (g *OpenAPIGenerator) buildDocument() *openapi.Document {
 	return d
 }
 
-// filterCommentString removes linter rules from comments.
+// filterCommentString removes linter rules and directives from comments.
 func (g *OpenAPIGenerator) filterCommentString(c protogen.Comments) string {
-	comment := g.linterRulePattern.ReplaceAllString(string(c), "")
-	return strings.TrimSpace(comment)
+	return pbgen.FilterComment(c)
 }
 
 func (g *OpenAPIGenerator) getSchemaByOption(inputMessage *protogen.Message) *openapi.Schema {
@@ -241,6 +259,7 @@ func (g *OpenAPIGenerator) getSchemaByOption(inputMessage *protogen.Message) *op
 	}
 	// Merge any `Schema` annotations with the current
 	extSchema := proto.GetExtension(inputMessage.Desc.Options(), openapi.E_Schema)
+	g.checkOption(inputMessage.Desc, openapi.E_Schema, extSchema)
 	var allRequired []string
 	if extSchema != nil {
 		if schema, ok := extSchema.(*openapi.Schema); ok && schema != nil {
@@ -250,13 +269,18 @@ func (g *OpenAPIGenerator) getSchemaByOption(inputMessage *protogen.Message) *op
 		}
 	}
 	var required []string
+	oneofs := pbgen.NewOneofGroups(*g.conf.OneofStyle)
 	for _, field := range inputMessage.Fields {
+		if g.reflect.hiddenField(field.Desc) {
+			continue
+		}
 		extName := g.reflect.formatFieldName(field.Desc)
 		if common.Contains(allRequired, extName) {
 			required = append(required, extName)
 		}
 		// Get the field description from the comments.
-		description := g.filterCommentString(field.Comments.Leading)
+		description := pbgen.FieldDescription(field)
+		example := pbgen.FieldExample(field)
 		// Check the field annotations to see if this is a readonly or writeonly field.
 		inputOnly := false
 		outputOnly := false
@@ -275,10 +299,14 @@ func (g *OpenAPIGenerator) getSchemaByOption(inputMessage *protogen.Message) *op
 					}
 				}
 			default:
-				logs.Errorf("unsupported extension type %T", extension)
+				g.diag.ErrorfAt(diagnostics.ProtoLocation(field.Desc, annotations.E_FieldBehavior), "unsupported extension type %T", extension)
 			}
 		}
 
+		if pbgen.FieldRequired(field.Desc) {
+			required = common.AppendUnique(required, g.reflect.formatFieldName(field.Desc))
+		}
+
 		// The field is either described by a reference or a schema.
 		fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
 		if fieldSchema == nil {
@@ -286,7 +314,8 @@ func (g *OpenAPIGenerator) getSchemaByOption(inputMessage *protogen.Message) *op
 		}
 
 		// If this field has siblings and is a $ref now, create a new schema use `allOf` to wrap it
-		wrapperNeeded := inputOnly || outputOnly || description != ""
+		deprecated := pbgen.IsDeprecated(field.Desc, field.Comments)
+		wrapperNeeded := inputOnly || outputOnly || deprecated || example != nil || description != ""
 		if wrapperNeeded {
 			if _, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Reference); ok {
 				fieldSchema = &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{
@@ -299,35 +328,43 @@ func (g *OpenAPIGenerator) getSchemaByOption(inputMessage *protogen.Message) *op
 			schema.Schema.Description = description
 			schema.Schema.ReadOnly = outputOnly
 			schema.Schema.WriteOnly = inputOnly
+			schema.Schema.Deprecated = deprecated
+			if example != nil {
+				schema.Schema.Example = example
+			}
 
 			// Merge any `Property` annotations with the current
 			extProperty := proto.GetExtension(field.Desc.Options(), openapi.E_Property)
+			g.checkOption(field.Desc, openapi.E_Property, extProperty)
 			if extProperty != nil {
-				proto.Merge(schema.Schema, extProperty.(*openapi.Schema))
+				common.MergeOptionMessage(schema.Schema, extProperty.(*openapi.Schema))
 			}
 		}
 
-		definitionProperties.AdditionalProperties = append(
-			definitionProperties.AdditionalProperties,
-			&openapi.NamedSchemaOrReference{
-				Name:  extName,
-				Value: fieldSchema,
-			},
-		)
+		property := &openapi.NamedSchemaOrReference{
+			Name:  extName,
+			Value: fieldSchema,
+		}
+		if !oneofs.Add(field, property) {
+			definitionProperties.AdditionalProperties = append(definitionProperties.AdditionalProperties, property)
+		}
 	}
 
 	schema := &openapi.Schema{
 		Type:       consts.SchemaObjectType,
 		Properties: definitionProperties,
+		Deprecated: pbgen.IsDeprecated(inputMessage.Desc, inputMessage.Comments),
 	}
 
 	// Merge any `Schema` annotations with the current
 	extSchema = proto.GetExtension(inputMessage.Desc.Options(), openapi.E_Schema)
+	g.checkOption(inputMessage.Desc, openapi.E_Schema, extSchema)
 	if extSchema != nil {
-		proto.Merge(schema, extSchema.(*openapi.Schema))
+		common.MergeOptionMessage(schema, extSchema.(*openapi.Schema))
 	}
 
 	schema.Required = required
+	oneofs.Apply(schema)
 	return schema
 }
 
@@ -341,29 +378,12 @@ func (g *OpenAPIGenerator) buildOperation(
 	inputMessage *protogen.Message,
 	outputMessage *protogen.Message,
 ) (*openapi.Operation, string) {
-	// Parameters array to hold all parameter objects
-	var parameters []*openapi.ParameterOrReference
+	// Requests and responses use the schemas of their direction.
+	g.reflect.direction = directionInput
+	defer func() { g.reflect.direction = "" }()
 
-	fieldSchema := &openapi.SchemaOrReference{
-		Oneof: &openapi.SchemaOrReference_Schema{
-			Schema: &openapi.Schema{
-				Type: consts.SchemaObjectType,
-			},
-		},
-	}
-	parameter := &openapi.Parameter{
-		Name:        consts.ParameterNameTTHeader,
-		In:          consts.ParameterInQuery,
-		Description: consts.ParameterDescription,
-		Required:    false,
-		Schema:      fieldSchema,
-	}
-
-	parameters = append(parameters, &openapi.ParameterOrReference{
-		Oneof: &openapi.ParameterOrReference_Parameter{
-			Parameter: parameter,
-		},
-	})
+	// Parameters array to hold all parameter objects
+	parameters := g.metainfoParameters()
 
 	var RequestBody *openapi.RequestBodyOrReference
 	var additionalProperties []*openapi.NamedMediaType
@@ -371,13 +391,13 @@ func (g *OpenAPIGenerator) buildOperation(
 	if inputMessage != nil {
 		bodySchema := g.getSchemaByOption(inputMessage)
 
-		if bodySchema != nil && bodySchema.Properties != nil && len(bodySchema.Properties.AdditionalProperties) > 0 {
+		if pbgen.HasProperties(bodySchema) {
 			refSchema := &openapi.NamedSchemaOrReference{
-				Name:  g.reflect.formatMessageName(inputMessage.Desc),
+				Name:  g.reflect.schemaNameForMessage(inputMessage.Desc),
 				Value: &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: bodySchema}},
 			}
 
-			ref := consts.ComponentSchemaPrefix + g.reflect.formatMessageName(inputMessage.Desc)
+			ref := consts.ComponentSchemaPrefix + g.reflect.schemaNameForMessage(inputMessage.Desc)
 
 			g.addSchemaToDocument(d, refSchema)
 
@@ -407,6 +427,7 @@ func (g *OpenAPIGenerator) buildOperation(
 		}
 	}
 
+	g.reflect.direction = directionOutput
 	var responses *openapi.Responses
 
 	if outputMessage != nil {
@@ -444,13 +465,18 @@ func (g *OpenAPIGenerator) buildOperation(
 	re := regexp.MustCompile(`:(\w+)`)
 	path = re.ReplaceAllString(path, `{$1}`)
 
+	// The first sentence of the method comment is the summary.
+	summary, description := common.SplitSummary(description)
 	op := &openapi.Operation{
 		Tags:        []string{tagName},
+		Summary:     summary,
 		Description: description,
 		OperationId: operationID,
 		Parameters:  parameters,
 		Responses:   responses,
 		RequestBody: RequestBody,
+
+		SpecificationExtension: g.metainfoExtension(),
 	}
 	if defaultHost != "" {
 		if !strings.HasPrefix(defaultHost, consts.URLDefaultPrefixHTTP) && !strings.HasPrefix(defaultHost, consts.URLDefaultPrefixHTTPS) {
@@ -467,12 +493,12 @@ func (g *OpenAPIGenerator) getResponseForMessage(d *openapi.Document, message *p
 
 	var additionalProperties []*openapi.NamedMediaType
 
-	if bodySchema != nil && bodySchema.Properties != nil && len(bodySchema.Properties.AdditionalProperties) > 0 {
+	if pbgen.HasProperties(bodySchema) {
 		refSchema := &openapi.NamedSchemaOrReference{
-			Name:  g.reflect.formatMessageName(message.Desc),
+			Name:  g.reflect.schemaNameForMessage(message.Desc),
 			Value: &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: bodySchema}},
 		}
-		ref := consts.ComponentSchemaPrefix + g.reflect.formatMessageName(message.Desc)
+		ref := consts.ComponentSchemaPrefix + g.reflect.schemaNameForMessage(message.Desc)
 		g.addSchemaToDocument(d, refSchema)
 		additionalProperties = append(additionalProperties, &openapi.NamedMediaType{
 			Name: consts.ContentTypeJSON,
@@ -486,6 +512,14 @@ func (g *OpenAPIGenerator) getResponseForMessage(d *openapi.Document, message *p
 		})
 	}
 
+	// google.protobuf.Empty is returned as an empty JSON object.
+	if len(additionalProperties) == 0 && message.Desc.FullName() == emptyFullName {
+		additionalProperties = append(additionalProperties, &openapi.NamedMediaType{
+			Name:  consts.ContentTypeJSON,
+			Value: &openapi.MediaType{Schema: wk.NewGoogleProtobufEmptySchema()},
+		})
+	}
+
 	content := &openapi.MediaTypes{
 		AdditionalProperties: additionalProperties,
 	}
@@ -493,6 +527,77 @@ func (g *OpenAPIGenerator) getResponseForMessage(d *openapi.Document, message *p
 	return consts.StatusOK, content
 }
 
+// pathStyle returns the configured path style of the RPC methods, falling
+// back to `/{Service}/{Method}` paths if it is not supported.
+func (g *OpenAPIGenerator) pathStyle() string {
+	style := *g.conf.PathStyle
+	if !common.IsPathStyle(style) {
+		g.diag.Errorf("path_style: unsupported path style %q, using %q", style, consts.PathStyleService)
+		*g.conf.PathStyle = consts.PathStyleService
+		return consts.PathStyleService
+	}
+	return style
+}
+
+// metainfoStyle returns the configured way of carrying metainfo, falling back
+// to headers if it is not supported.
+func (g *OpenAPIGenerator) metainfoStyle() string {
+	style := *g.conf.MetainfoStyle
+	if !common.IsMetainfoStyle(style) {
+		g.diag.Errorf("metainfo_style: unsupported metainfo style %q, using %q", style, consts.MetainfoStyleHeader)
+		*g.conf.MetainfoStyle = consts.MetainfoStyleHeader
+		return consts.MetainfoStyleHeader
+	}
+	return style
+}
+
+// metainfoParameters documents how the metainfo of a request is passed with
+// the "query" metainfo style: a `ttheader` object query parameter.
+func (g *OpenAPIGenerator) metainfoParameters() []*openapi.ParameterOrReference {
+	if g.metainfoStyle() != consts.MetainfoStyleQuery {
+		return nil
+	}
+	return []*openapi.ParameterOrReference{{
+		Oneof: &openapi.ParameterOrReference_Parameter{
+			Parameter: &openapi.Parameter{
+				Name:        consts.ParameterNameTTHeader,
+				In:          consts.ParameterInQuery,
+				Description: consts.ParameterDescription,
+				Required:    false,
+				Schema: &openapi.SchemaOrReference{
+					Oneof: &openapi.SchemaOrReference_Schema{
+						Schema: &openapi.Schema{
+							Type: consts.SchemaObjectType,
+						},
+					},
+				},
+			},
+		},
+	}}
+}
+
+// metainfoExtension documents the X-Metainfo-{Key} headers carrying metainfo
+// with the "header" metainfo style.
+func (g *OpenAPIGenerator) metainfoExtension() []*openapi.NamedAny {
+	if g.metainfoStyle() == consts.MetainfoStyleQuery {
+		return nil
+	}
+	return []*openapi.NamedAny{{
+		Name:  consts.MetainfoExtensionName,
+		Value: &openapi.Any{Yaml: consts.MetainfoExtension},
+	}}
+}
+
+// hasOperation reports whether an operation is already documented at path.
+func (g *OpenAPIGenerator) hasOperation(d *openapi.Document, path string) bool {
+	for _, namedPathItem := range d.Paths.Path {
+		if namedPathItem.Name == path && namedPathItem.Value.Post != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // addOperationToDocument adds an operation to the specified path/method.
 func (g *OpenAPIGenerator) addOperationToDocument(d *openapi.Document, op *openapi.Operation, path string) {
 	var selectedPathItem *openapi.NamedPathItem
@@ -512,6 +617,7 @@ func (g *OpenAPIGenerator) addOperationToDocument(d *openapi.Document, op *opena
 }
 
 func (g *OpenAPIGenerator) addPathsToDocument(d *openapi.Document, services []*protogen.Service) {
+	pathStyle := g.pathStyle()
 	for _, service := range services {
 		annotationsCount := 0
 
@@ -520,7 +626,7 @@ func (g *OpenAPIGenerator) addPathsToDocument(d *openapi.Document, services []*p
 			inputMessage := method.Input
 			outputMessage := method.Output
 			operationID := string(service.Desc.Name()) + "_" + string(method.Desc.Name())
-			path := "/" + string(method.Desc.Name())
+			path := common.RPCPath(pathStyle, string(service.Desc.Name()), string(method.Desc.Name()))
 
 			annotationsCount++
 			var host string
@@ -530,11 +636,19 @@ func (g *OpenAPIGenerator) addPathsToDocument(d *openapi.Document, services []*p
 				host = proto.GetExtension(service.Desc.Options(), api.E_BaseDomain).(string)
 			}
 			op, path2 := g.buildOperation(d, operationID, string(service.Desc.Name()), comment, host, path, inputMessage, outputMessage)
+			g.addErrorResponses(d, service, method, op)
+			g.addDefaultResponse(method, op)
+			pbgen.ApplyStreaming(method, op, pbgen.LookupStreamContentType(*g.conf.StreamContentType, g.diag), *g.conf.DisableStreamingTryItOut)
+			pbgen.ApplyDeprecation(service, method, op)
 			// Merge any `Operation` annotations with the current
 			extOperation := proto.GetExtension(method.Desc.Options(), openapi.E_Operation)
+			g.checkOption(method.Desc, openapi.E_Operation, extOperation)
 
 			if extOperation != nil {
-				proto.Merge(op, extOperation.(*openapi.Operation))
+				common.MergeOptionMessage(op, extOperation.(*openapi.Operation))
+			}
+			if g.hasOperation(d, path2) {
+				g.diag.WarnfAt(diagnostics.ProtoLocation(method.Desc, nil), "path %s of method %s is already used by another service, use the %q path style to tell them apart", path2, operationID, consts.PathStyleService)
 			}
 			g.addOperationToDocument(d, op, path2)
 		}
@@ -562,123 +676,163 @@ func (g *OpenAPIGenerator) addSchemasForMessagesToDocument(d *openapi.Document,
 			g.addSchemasForMessagesToDocument(d, message.Messages)
 		}
 
-		schemaName := g.reflect.formatMessageName(message.Desc)
+		// Messages split by direction have a schema per direction.
+		for _, direction := range []string{"", directionInput, directionOutput} {
+			schemaName := g.reflect.formatMessageName(message.Desc) + direction
 
-		// Only generate this if we need it and haven't already generated it.
-		if !common.Contains(g.reflect.requiredSchemas, schemaName) ||
-			common.Contains(g.generatedSchemas, schemaName) {
-			continue
+			// Only generate this if we need it and haven't already generated it.
+			if !common.Contains(g.reflect.requiredSchemas, schemaName) ||
+				common.Contains(g.generatedSchemas, schemaName) {
+				continue
+			}
+			g.reflect.direction = direction
+			g.addSchemaForMessage(d, message, schemaName)
+			g.reflect.direction = ""
 		}
+	}
+}
 
-		typeName := g.reflect.fullMessageTypeName(message.Desc)
-		messageDescription := g.filterCommentString(message.Comments.Leading)
+// addSchemaForMessage adds the schema of message named schemaName, built for
+// the current direction of the reflector.
+func (g *OpenAPIGenerator) addSchemaForMessage(d *openapi.Document, message *protogen.Message, schemaName string) {
+	typeName := g.reflect.fullMessageTypeName(message.Desc)
+	messageDescription := g.filterCommentString(message.Comments.Leading)
 
-		// `google.protobuf.Value` and `google.protobuf.Any` have special JSON transcoding
-		// so we can't just reflect on the message descriptor.
-		if typeName == ".google.protobuf.Value" {
-			g.addSchemaToDocument(d, wk.NewGoogleProtobufValueSchema(schemaName))
-			continue
-		} else if typeName == ".google.protobuf.Any" {
-			g.addSchemaToDocument(d, wk.NewGoogleProtobufAnySchema(schemaName))
-			continue
-		} else if typeName == ".google.rpc.Status" {
-			anySchemaName := g.reflect.formatMessageName(anyProtoDesc)
-			g.addSchemaToDocument(d, wk.NewGoogleProtobufAnySchema(anySchemaName))
-			g.addSchemaToDocument(d, wk.NewGoogleRpcStatusSchema(schemaName, anySchemaName))
+	// `google.protobuf.Value` and `google.protobuf.Any` have special JSON transcoding
+	// so we can't just reflect on the message descriptor.
+	if typeName == ".google.protobuf.Value" {
+		g.addSchemaToDocument(d, wk.NewGoogleProtobufValueSchema(schemaName))
+		return
+	} else if typeName == ".google.protobuf.Any" {
+		g.addSchemaToDocument(d, wk.NewGoogleProtobufAnySchema(schemaName))
+		return
+	} else if typeName == ".google.rpc.Status" {
+		anySchemaName := g.reflect.formatMessageName(anyProtoDesc)
+		g.addSchemaToDocument(d, wk.NewGoogleProtobufAnySchema(anySchemaName))
+		g.addSchemaToDocument(d, wk.NewGoogleRpcStatusSchema(schemaName, anySchemaName))
+		return
+	}
+
+	// Build an array holding the fields of the message.
+	definitionProperties := &openapi.Properties{
+		AdditionalProperties: make([]*openapi.NamedSchemaOrReference, 0),
+	}
+
+	var required []string
+	oneofs := pbgen.NewOneofGroups(*g.conf.OneofStyle)
+	for _, field := range message.Fields {
+		if g.reflect.hiddenField(field.Desc) {
 			continue
 		}
-
-		// Build an array holding the fields of the message.
-		definitionProperties := &openapi.Properties{
-			AdditionalProperties: make([]*openapi.NamedSchemaOrReference, 0),
-		}
-
-		var required []string
-		for _, field := range message.Fields {
-			// Get the field description from the comments.
-			description := g.filterCommentString(field.Comments.Leading)
-			// Check the field annotations to see if this is a readonly or writeonly field.
-			inputOnly := false
-			outputOnly := false
-			extension := proto.GetExtension(field.Desc.Options(), annotations.E_FieldBehavior)
-			if extension != nil {
-				switch v := extension.(type) {
-				case []annotations.FieldBehavior:
-					for _, vv := range v {
-						switch vv {
-						case annotations.FieldBehavior_OUTPUT_ONLY:
-							outputOnly = true
-						case annotations.FieldBehavior_INPUT_ONLY:
-							inputOnly = true
-						case annotations.FieldBehavior_REQUIRED:
-							required = append(required, g.reflect.formatFieldName(field.Desc))
-						}
+		// Get the field description from the comments.
+		description := pbgen.FieldDescription(field)
+		example := pbgen.FieldExample(field)
+		// Check the field annotations to see if this is a readonly or writeonly field.
+		inputOnly := false
+		outputOnly := false
+		extension := proto.GetExtension(field.Desc.Options(), annotations.E_FieldBehavior)
+		if extension != nil {
+			switch v := extension.(type) {
+			case []annotations.FieldBehavior:
+				for _, vv := range v {
+					switch vv {
+					case annotations.FieldBehavior_OUTPUT_ONLY:
+						outputOnly = true
+					case annotations.FieldBehavior_INPUT_ONLY:
+						inputOnly = true
+					case annotations.FieldBehavior_REQUIRED:
+						required = append(required, g.reflect.formatFieldName(field.Desc))
 					}
-				default:
-					logs.Errorf("unsupported extension type %T", extension)
 				}
+			default:
+				g.diag.ErrorfAt(diagnostics.ProtoLocation(field.Desc, annotations.E_FieldBehavior), "unsupported extension type %T", extension)
 			}
+		}
 
-			// The field is either described by a reference or a schema.
-			fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
-			if fieldSchema == nil {
-				continue
-			}
+		if pbgen.FieldRequired(field.Desc) {
+			required = common.AppendUnique(required, g.reflect.formatFieldName(field.Desc))
+		}
 
-			// If this field has siblings and is a $ref now, create a new schema use `allOf` to wrap it
-			wrapperNeeded := inputOnly || outputOnly || description != ""
-			if wrapperNeeded {
-				if _, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Reference); ok {
-					fieldSchema = &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{
-						AllOf: []*openapi.SchemaOrReference{fieldSchema},
-					}}}
-				}
+		// The field is either described by a reference or a schema.
+		fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
+		if fieldSchema == nil {
+			continue
+		}
+
+		// If this field has siblings and is a $ref now, create a new schema use `allOf` to wrap it
+		deprecated := pbgen.IsDeprecated(field.Desc, field.Comments)
+		wrapperNeeded := inputOnly || outputOnly || deprecated || example != nil || description != ""
+		if wrapperNeeded {
+			if _, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Reference); ok {
+				fieldSchema = &openapi.SchemaOrReference{Oneof: &openapi.SchemaOrReference_Schema{Schema: &openapi.Schema{
+					AllOf: []*openapi.SchemaOrReference{fieldSchema},
+				}}}
 			}
+		}
 
-			if schema, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Schema); ok {
-				schema.Schema.Description = description
-				schema.Schema.ReadOnly = outputOnly
-				schema.Schema.WriteOnly = inputOnly
+		if schema, ok := fieldSchema.Oneof.(*openapi.SchemaOrReference_Schema); ok {
+			schema.Schema.Description = description
+			schema.Schema.ReadOnly = outputOnly
+			schema.Schema.WriteOnly = inputOnly
+			schema.Schema.Deprecated = deprecated
+			if example != nil {
+				schema.Schema.Example = example
+			}
 
-				// Merge any `Property` annotations with the current
-				extProperty := proto.GetExtension(field.Desc.Options(), openapi.E_Property)
-				if extProperty != nil {
-					proto.Merge(schema.Schema, extProperty.(*openapi.Schema))
-				}
+			// Merge any `Property` annotations with the current
+			extProperty := proto.GetExtension(field.Desc.Options(), openapi.E_Property)
+			g.checkOption(field.Desc, openapi.E_Property, extProperty)
+			if extProperty != nil {
+				common.MergeOptionMessage(schema.Schema, extProperty.(*openapi.Schema))
 			}
+		}
 
-			name := g.reflect.formatFieldName(field.Desc)
+		name := g.reflect.formatFieldName(field.Desc)
 
-			definitionProperties.AdditionalProperties = append(
-				definitionProperties.AdditionalProperties,
-				&openapi.NamedSchemaOrReference{
-					Name:  name,
-					Value: fieldSchema,
-				},
-			)
+		property := &openapi.NamedSchemaOrReference{
+			Name:  name,
+			Value: fieldSchema,
 		}
-
-		schema := &openapi.Schema{
-			Type:        consts.SchemaObjectType,
-			Description: messageDescription,
-			Properties:  definitionProperties,
-			Required:    required,
+		if !oneofs.Add(field, property) {
+			definitionProperties.AdditionalProperties = append(definitionProperties.AdditionalProperties, property)
 		}
+	}
 
-		// Merge any `Schema` annotations with the current
-		extSchema := proto.GetExtension(message.Desc.Options(), openapi.E_Schema)
-		if extSchema != nil {
-			proto.Merge(schema, extSchema.(*openapi.Schema))
-		}
+	schema := &openapi.Schema{
+		Type:        consts.SchemaObjectType,
+		Description: messageDescription,
+		Properties:  definitionProperties,
+		Required:    required,
+		Deprecated:  pbgen.IsDeprecated(message.Desc, message.Comments),
+	}
 
-		// Add the schema to the components.schema list.
-		g.addSchemaToDocument(d, &openapi.NamedSchemaOrReference{
-			Name: schemaName,
-			Value: &openapi.SchemaOrReference{
-				Oneof: &openapi.SchemaOrReference_Schema{
-					Schema: schema,
-				},
+	// Merge any `Schema` annotations with the current
+	extSchema := proto.GetExtension(message.Desc.Options(), openapi.E_Schema)
+	g.checkOption(message.Desc, openapi.E_Schema, extSchema)
+	if extSchema != nil {
+		common.MergeOptionMessage(schema, extSchema.(*openapi.Schema))
+	}
+	oneofs.Apply(schema)
+
+	// Add the schema to the components.schema list.
+	g.addSchemaToDocument(d, &openapi.NamedSchemaOrReference{
+		Name: schemaName,
+		Value: &openapi.SchemaOrReference{
+			Oneof: &openapi.SchemaOrReference_Schema{
+				Schema: schema,
 			},
-		})
+		},
+	})
+}
+
+// checkOption reports the problems common.CheckOptionMessage finds in the
+// value of the openapi extension ext set on desc.
+func (g *OpenAPIGenerator) checkOption(desc protoreflect.Descriptor, ext protoreflect.ExtensionType, value interface{}) {
+	m, ok := value.(proto.Message)
+	if !ok {
+		return
+	}
+	if err := common.CheckOptionMessage(m); err != nil {
+		g.diag.ErrorfAt(diagnostics.ProtoLocation(desc, ext), "Error parsing %s option: %s", ext.TypeDescriptor().FullName(), err)
 	}
 }
@@ -23,25 +23,69 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 
 	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
 	"github.com/hertz-contrib/swagger-generate/common/tpl"
 	"github.com/hertz-contrib/swagger-generate/common/utils"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoprint"
 	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 type ServerConfiguration struct {
-	KitexAddr *string
+	HertzAddr     *string
+	KitexAddr     *string
+	PathStyle     *string
+	MetainfoStyle *string
+	ImportPaths   *[]string
 }
 
 type ServerGenerator struct {
-	IdlPath   string
-	KitexAddr string
+	IdlPath       string
+	HertzAddr     string
+	KitexAddr     string
+	PathStyle     string
+	MetainfoStyle string
+	ImportPaths   []string
+	Services      []*ServiceInfo
+	IdlFiles      []*IdlFile
+	ErrorStatuses []*MethodErrorStatuses
+
+	diag *diagnostics.Collector
+}
+
+// MethodErrorStatuses holds the HTTP status of the biz error codes of a
+// method, keyed by `Service/Method`.
+type MethodErrorStatuses struct {
+	Method string
+	Codes  []*ErrorStatus
+}
+
+type ErrorStatus struct {
+	Code   int32
+	Status int
+}
+
+// IdlFile is a proto file embedded in the generated server.
+type IdlFile struct {
+	Path    string // Path of the file, relative to its import root.
+	Literal string // Content of the file as a Go string literal.
+}
+
+// ServiceInfo describes a service of the IDL, which the proxy calls through a
+// generic client of its own.
+type ServiceInfo struct {
+	Name    string
+	File    string // Path of the proto file of the service, relative to its import root.
+	Methods []string
 }
 
-func NewServerGenerator(conf ServerConfiguration, inputFiles []*protogen.File) (*ServerGenerator, error) {
+func NewServerGenerator(conf ServerConfiguration, inputFiles []*protogen.File, diag *diagnostics.Collector) (*ServerGenerator, error) {
 	kitexAddr := conf.KitexAddr
 	if kitexAddr == nil {
 		*kitexAddr = consts.DefaultKitexAddr
@@ -54,24 +98,113 @@ func NewServerGenerator(conf ServerConfiguration, inputFiles []*protogen.File) (
 			genFiles = append(genFiles, f)
 		}
 	}
-	if len(genFiles) > 1 {
-		return nil, errors.New("only one .proto file is supported for generation swagger")
-	} else if len(genFiles) == 1 {
-		idlPath = genFiles[0].Desc.Path()
-	} else {
+	if len(genFiles) == 0 {
 		return nil, errors.New("no .proto files marked for generation")
 	}
+	idlPath = genFiles[0].Desc.Path()
+	hertzAddr := consts.DefaultHertzAddr
+	if conf.HertzAddr != nil {
+		hertzAddr = *conf.HertzAddr
+	}
 	// Check if Hertz and Kitex addresses are valid (basic validation)
+	if err := validateAddress(hertzAddr); err != nil {
+		return nil, fmt.Errorf("invalid Hertz address: %w", err)
+	}
 	if err := validateAddress(*kitexAddr); err != nil {
 		return nil, fmt.Errorf("invalid Kitex address: %w", err)
 	}
 
+	pathStyle := consts.PathStyleService
+	if conf.PathStyle != nil && utils.IsPathStyle(*conf.PathStyle) {
+		pathStyle = *conf.PathStyle
+	}
+	metainfoStyle := consts.MetainfoStyleHeader
+	if conf.MetainfoStyle != nil && utils.IsMetainfoStyle(*conf.MetainfoStyle) {
+		metainfoStyle = *conf.MetainfoStyle
+	}
+
+	var importPaths []string
+	if conf.ImportPaths != nil {
+		for _, p := range *conf.ImportPaths {
+			importPaths = utils.AppendUnique(importPaths, filepath.ToSlash(p))
+		}
+	}
+
+	var services []*ServiceInfo
+	for _, f := range genFiles {
+		for _, s := range f.Services {
+			service := &ServiceInfo{Name: string(s.Desc.Name()), File: f.Desc.Path()}
+			for _, m := range s.Methods {
+				service.Methods = append(service.Methods, string(m.Desc.Name()))
+			}
+			services = append(services, service)
+		}
+	}
+
+	idlFiles, err := collectIdlFiles(inputFiles, genFiles)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ServerGenerator{
-		IdlPath:   idlPath,
-		KitexAddr: *kitexAddr,
+		IdlPath:       idlPath,
+		HertzAddr:     hertzAddr,
+		KitexAddr:     *kitexAddr,
+		PathStyle:     pathStyle,
+		MetainfoStyle: metainfoStyle,
+		ImportPaths:   importPaths,
+		Services:      services,
+		IdlFiles:      idlFiles,
+		diag:          diag,
 	}, nil
 }
 
+// collectIdlFiles prints the generated files and, transitively, the files
+// they import, sorted by path. The well-known types are left out, as the
+// proxy parser has them built in.
+func collectIdlFiles(inputFiles, genFiles []*protogen.File) ([]*IdlFile, error) {
+	fdps := make([]*descriptorpb.FileDescriptorProto, 0, len(inputFiles))
+	for _, f := range inputFiles {
+		fdps = append(fdps, f.Proto)
+	}
+	fds, err := desc.CreateFileDescriptors(fdps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file descriptors: %w", err)
+	}
+
+	paths := map[string]bool{}
+	var walk func(fd *desc.FileDescriptor)
+	walk = func(fd *desc.FileDescriptor) {
+		if paths[fd.GetName()] || strings.HasPrefix(fd.GetName(), "google/protobuf/") {
+			return
+		}
+		paths[fd.GetName()] = true
+		for _, dep := range fd.GetDependencies() {
+			walk(dep)
+		}
+	}
+	for _, f := range genFiles {
+		walk(fds[f.Desc.Path()])
+	}
+
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	var printer protoprint.Printer
+	files := make([]*IdlFile, 0, len(sorted))
+	for _, path := range sorted {
+		content, err := printer.PrintProtoToString(fds[path])
+		if err != nil {
+			return nil, fmt.Errorf("failed to print %s: %w", path, err)
+		}
+		files = append(files, &IdlFile{Path: path, Literal: utils.RawString(content)})
+	}
+	return files, nil
+}
+
 func validateAddress(addr string) error {
 	if addr == "" {
 		return errors.New("address cannot be empty")
@@ -82,46 +215,104 @@ func validateAddress(addr string) error {
 	return nil
 }
 
+// Generate writes the settings of the server: the existing ones updated or,
+// if there are none or the file still holds the proxy, new ones.
 func (g *ServerGenerator) Generate(outputFile *protogen.GeneratedFile) error {
 	filePath := filepath.Join(filepath.Dir(g.IdlPath), consts.DefaultOutputSwaggerFile)
+	content, err := g.settings(filePath)
+	if err != nil {
+		return err
+	}
+	if _, err = outputFile.Write([]byte(content)); err != nil {
+		return fmt.Errorf("failed to write output file: %v", err)
+	}
+	return nil
+}
+
+func (g *ServerGenerator) settings(filePath string) (string, error) {
 	if utils.FileExists(filePath) {
-		updatedContent, err := updateVariables(filePath, g.KitexAddr, g.IdlPath)
+		content, err := ioutil.ReadFile(filePath)
 		if err != nil {
-			return errors.New("failed to update variables in the existing file")
-		}
-		if _, err = outputFile.Write([]byte(updatedContent)); err != nil {
-			return errors.New("failed to write output file")
+			return "", fmt.Errorf("failed to read file: %v", err)
 		}
-	} else {
-		tmpl, err := template.New("server").Delims("{{", "}}").Parse(consts.CodeGenerationCommentPbRpc + "\n" + tpl.ServerTemplateRpcPb)
-		if err != nil {
-			return fmt.Errorf("failed to parse template: %w", err)
+		if !tpl.LegacyServerPattern.Match(content) {
+			return g.updateVariables(string(content))
 		}
+		g.diag.Warnf("%s holds the proxy of an earlier version and is replaced by the settings of the server, the proxy being generated in %s", filePath, consts.DefaultOutputProxyFile)
+	}
+	return g.execute(consts.CodeGenerationCommentPbRpc + "\n" + tpl.SettingsTemplateRpcPb)
+}
 
-		var buf bytes.Buffer
-		err = tmpl.Execute(&buf, g)
-		if err != nil {
-			return fmt.Errorf("failed to execute template: %w", err)
-		}
+// GenerateProxy writes the proxy of the server, which is regenerated on every
+// run.
+func (g *ServerGenerator) GenerateProxy(outputFile *protogen.GeneratedFile) error {
+	content, err := g.execute(consts.CodeGenerationCommentPbRpc + consts.DoNotEditComment + "\n" + tpl.ServerTemplateRpcPb)
+	if err != nil {
+		return err
+	}
+	if _, err = outputFile.Write([]byte(content)); err != nil {
+		return fmt.Errorf("failed to write output file: %v", err)
+	}
+	return nil
+}
 
-		if _, err = outputFile.Write(buf.Bytes()); err != nil {
-			return fmt.Errorf("failed to write output file: %v", err)
+// AddErrorStatuses adds the HTTP status of the error codes of the methods
+// documented by an OpenAPI generator.
+func (g *ServerGenerator) AddErrorStatuses(statuses map[string]map[int32]int) {
+	for method, codes := range statuses {
+		m := &MethodErrorStatuses{Method: method}
+		for code, status := range codes {
+			m.Codes = append(m.Codes, &ErrorStatus{Code: code, Status: status})
 		}
+		sort.Slice(m.Codes, func(i, j int) bool { return m.Codes[i].Code < m.Codes[j].Code })
+		g.ErrorStatuses = append(g.ErrorStatuses, m)
+	}
+	sort.Slice(g.ErrorStatuses, func(i, j int) bool { return g.ErrorStatuses[i].Method < g.ErrorStatuses[j].Method })
+}
+
+// GenerateIdl writes the IDL embedded in the generated server, its services
+// and the status of the error codes of its methods, which are regenerated on
+// every run.
+func (g *ServerGenerator) GenerateIdl(outputFile *protogen.GeneratedFile) error {
+	content, err := g.execute(consts.CodeGenerationCommentPbRpc + consts.DoNotEditComment + "\n" + tpl.IdlTemplate + tpl.ServicesTemplatePb + tpl.ErrorStatusesTemplate)
+	if err != nil {
+		return err
+	}
+	if _, err = outputFile.Write([]byte(content)); err != nil {
+		return fmt.Errorf("failed to write output file: %v", err)
 	}
 	return nil
 }
 
-func updateVariables(filePath, newKitexAddr, newIdlPath string) (string, error) {
-	content, err := ioutil.ReadFile(filePath)
+func (g *ServerGenerator) execute(text string) (string, error) {
+	tmpl, err := template.New("server").Delims("{{", "}}").Parse(text)
 	if err != nil {
-		return "", fmt.Errorf("failed to read file: %v", err)
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, g); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
+	return buf.String(), nil
+}
 
+// updateVariables updates the settings in the content of the existing
+// settings file, leaving the rest of it as customized by the user.
+func (g *ServerGenerator) updateVariables(content string) (string, error) {
+	hertzAddrPattern := regexp.MustCompile(`hertzAddr\s*=\s*"(.*?)"`)
 	kitexAddrPattern := regexp.MustCompile(`kitexAddr\s*=\s*"(.*?)"`)
-	idlPathPattern := regexp.MustCompile(`idlFile\s*=\s*"(.*?)"`)
+	pathStylePattern := regexp.MustCompile(`pathStyle\s*=\s*"(.*?)"`)
+	metainfoStylePattern := regexp.MustCompile(`metainfoStyle\s*=\s*"(.*?)"`)
 
-	updatedContent := kitexAddrPattern.ReplaceAllString(string(content), fmt.Sprintf(`kitexAddr = "%s"`, newKitexAddr))
-	updatedContent = idlPathPattern.ReplaceAllString(updatedContent, fmt.Sprintf(`idlFile = "%s"`, newIdlPath))
+	updatedContent := hertzAddrPattern.ReplaceAllString(content, fmt.Sprintf(`hertzAddr = "%s"`, g.HertzAddr))
+	updatedContent = kitexAddrPattern.ReplaceAllString(updatedContent, fmt.Sprintf(`kitexAddr = "%s"`, g.KitexAddr))
+	updatedContent = pathStylePattern.ReplaceAllString(updatedContent, fmt.Sprintf(`pathStyle = "%s"`, g.PathStyle))
+	updatedContent = metainfoStylePattern.ReplaceAllString(updatedContent, fmt.Sprintf(`metainfoStyle = "%s"`, g.MetainfoStyle))
 
-	return updatedContent, nil
+	importPaths, err := g.execute(tpl.ImportPathsTemplate)
+	if err != nil {
+		return "", err
+	}
+	return tpl.ImportPathsPattern.ReplaceAllLiteralString(updatedContent, importPaths), nil
 }
@@ -79,13 +79,19 @@ func NewNumberSchema(format string) *v3.SchemaOrReference {
 }
 
 func NewEnumSchema(enum_type *string, field protoreflect.FieldDescriptor) *v3.SchemaOrReference {
+	return NewEnumDescriptorSchema(enum_type, field.Enum())
+}
+
+// NewEnumDescriptorSchema returns the schema of enum, which is a string of
+// the value names if enum_type is "string" and an integer otherwise.
+func NewEnumDescriptorSchema(enum_type *string, enum protoreflect.EnumDescriptor) *v3.SchemaOrReference {
 	schema := &v3.Schema{Format: "enum"}
 	if enum_type != nil && *enum_type == "string" {
 		schema.Type = "string"
-		schema.Enum = make([]*v3.Any, 0, field.Enum().Values().Len())
-		for i := 0; i < field.Enum().Values().Len(); i++ {
+		schema.Enum = make([]*v3.Any, 0, enum.Values().Len())
+		for i := 0; i < enum.Values().Len(); i++ {
 			schema.Enum = append(schema.Enum, &v3.Any{
-				Yaml: string(field.Enum().Values().Get(i).Name()),
+				Yaml: string(enum.Values().Get(i).Name()),
 			})
 		}
 	} else {
@@ -190,6 +196,74 @@ func NewGoogleTypeDateTimeSchema() *v3.SchemaOrReference {
 	}
 }
 
+// google.type.TimeOfDay is serialized as an object of its time fields
+func NewGoogleTypeTimeOfDaySchema() *v3.SchemaOrReference {
+	return newObjectSchema("Represents a time of day. The date and time zone are either not significant or are specified elsewhere.",
+		namedSchema("hours", NewIntegerSchema("int32"), "Hours of day in 24 hour format, from 0 to 23."),
+		namedSchema("minutes", NewIntegerSchema("int32"), "Minutes of hour of day, from 0 to 59."),
+		namedSchema("seconds", NewIntegerSchema("int32"), "Seconds of minutes of the time, normally from 0 to 59."),
+		namedSchema("nanos", NewIntegerSchema("int32"), "Fractions of seconds in nanoseconds, from 0 to 999,999,999."),
+	)
+}
+
+// google.type.Interval is serialized as an object of two timestamps
+func NewGoogleTypeIntervalSchema() *v3.SchemaOrReference {
+	return newObjectSchema("Represents a time interval, encoded as a start time (inclusive) and an end time (exclusive).",
+		namedSchema("startTime", NewGoogleProtobufTimestampSchema(), "Inclusive start of the interval."),
+		namedSchema("endTime", NewGoogleProtobufTimestampSchema(), "Exclusive end of the interval."),
+	)
+}
+
+// google.type.Money is serialized as an object. Like every int64, `units` is
+// a string in JSON.
+func NewGoogleTypeMoneySchema() *v3.SchemaOrReference {
+	return newObjectSchema("Represents an amount of money with its currency type.",
+		namedSchema("currencyCode", NewStringSchema(), "The three-letter currency code defined in ISO 4217."),
+		namedSchema("units", &v3.SchemaOrReference{
+			Oneof: &v3.SchemaOrReference_Schema{
+				Schema: &v3.Schema{Type: "string", Format: "int64"},
+			},
+		}, "The whole units of the amount."),
+		namedSchema("nanos", NewIntegerSchema("int32"), "Number of nano (10^-9) units of the amount, from -999,999,999 to +999,999,999. It has the same sign as `units`."),
+	)
+}
+
+// google.type.LatLng is serialized as an object of two doubles
+func NewGoogleTypeLatLngSchema() *v3.SchemaOrReference {
+	return newObjectSchema("An object that represents a latitude/longitude pair, in degrees, following the WGS84 standard.",
+		namedSchema("latitude", NewNumberSchema("double"), "The latitude in degrees, in the range [-90.0, +90.0]."),
+		namedSchema("longitude", NewNumberSchema("double"), "The longitude in degrees, in the range [-180.0, +180.0]."),
+	)
+}
+
+// google.type.Color is serialized as an object of its RGBA components. Since
+// `alpha` is a google.protobuf.FloatValue, it is a plain number in JSON.
+func NewGoogleTypeColorSchema() *v3.SchemaOrReference {
+	return newObjectSchema("Represents a color in the RGBA color space.",
+		namedSchema("red", NewNumberSchema("float"), "The amount of red in the color as a value in the interval [0, 1]."),
+		namedSchema("green", NewNumberSchema("float"), "The amount of green in the color as a value in the interval [0, 1]."),
+		namedSchema("blue", NewNumberSchema("float"), "The amount of blue in the color as a value in the interval [0, 1]."),
+		namedSchema("alpha", NewNumberSchema("float"), "The fraction of this color that should be applied to the pixel, in the interval [0, 1]. A missing value means a solid color."),
+	)
+}
+
+// google.type.PostalAddress is serialized as an object of its address fields
+func NewGoogleTypePostalAddressSchema() *v3.SchemaOrReference {
+	return newObjectSchema("Represents a postal address, e.g. for postal delivery or payments addresses.",
+		namedSchema("revision", NewIntegerSchema("int32"), "The schema revision of the PostalAddress. All new revisions must be backward compatible with old revisions."),
+		namedSchema("regionCode", NewStringSchema(), "CLDR region code of the country/region of the address, e.g. \"CH\" for Switzerland."),
+		namedSchema("languageCode", NewStringSchema(), "BCP-47 language code of the contents of this address, e.g. \"zh-Hant\"."),
+		namedSchema("postalCode", NewStringSchema(), "Postal code of the address."),
+		namedSchema("sortingCode", NewStringSchema(), "Additional, country-specific, sorting code."),
+		namedSchema("administrativeArea", NewStringSchema(), "Highest administrative subdivision which is used for postal addresses of a country or region."),
+		namedSchema("locality", NewStringSchema(), "The city/town portion of the address."),
+		namedSchema("sublocality", NewStringSchema(), "Sublocality of the address."),
+		namedSchema("addressLines", NewListSchema(NewStringSchema()), "Unstructured address lines describing the lower levels of an address."),
+		namedSchema("recipients", NewListSchema(NewStringSchema()), "The recipient at the address."),
+		namedSchema("organization", NewStringSchema(), "The name of the organization at the address."),
+	)
+}
+
 // google.protobuf.FieldMask masks is serialized as a string
 func NewGoogleProtobufFieldMaskSchema() *v3.SchemaOrReference {
 	return &v3.SchemaOrReference{
@@ -208,6 +282,40 @@ func NewGoogleProtobufStructSchema() *v3.SchemaOrReference {
 	}
 }
 
+// google.protobuf.ListValue is equivalent to a JSON array of values
+func NewGoogleProtobufListValueSchema(value_ref string) *v3.SchemaOrReference {
+	return NewListSchema(&v3.SchemaOrReference{
+		Oneof: &v3.SchemaOrReference_Reference{
+			Reference: &v3.Reference{XRef: value_ref},
+		},
+	})
+}
+
+// google.protobuf.NullValue is serialized as the JSON null, whatever its value
+func NewGoogleProtobufNullValueSchema() *v3.SchemaOrReference {
+	return &v3.SchemaOrReference{
+		Oneof: &v3.SchemaOrReference_Schema{
+			Schema: &v3.Schema{
+				Nullable:    true,
+				Enum:        []*v3.Any{{Yaml: "null"}},
+				Description: "Always the JSON null value.",
+			},
+		},
+	}
+}
+
+// google.protobuf.Empty is serialized as an empty JSON object
+func NewGoogleProtobufEmptySchema() *v3.SchemaOrReference {
+	return &v3.SchemaOrReference{
+		Oneof: &v3.SchemaOrReference_Schema{
+			Schema: &v3.Schema{
+				Type:        "object",
+				Description: "An empty JSON object.",
+			},
+		},
+	}
+}
+
 // google.protobuf.Value is handled specially
 // See here for the details on the JSON mapping:
 //
@@ -345,3 +453,22 @@ func NewGoogleProtobufMapFieldEntrySchema(value_field_schema *v3.SchemaOrReferen
 		},
 	}
 }
+
+// newObjectSchema returns an object schema with the given properties.
+func newObjectSchema(description string, properties ...*v3.NamedSchemaOrReference) *v3.SchemaOrReference {
+	return &v3.SchemaOrReference{
+		Oneof: &v3.SchemaOrReference_Schema{
+			Schema: &v3.Schema{
+				Type:        "object",
+				Description: description,
+				Properties:  &v3.Properties{AdditionalProperties: properties},
+			},
+		},
+	}
+}
+
+// namedSchema names schema and sets its description.
+func namedSchema(name string, schema *v3.SchemaOrReference, description string) *v3.NamedSchemaOrReference {
+	schema.GetSchema().Description = description
+	return &v3.NamedSchemaOrReference{Name: name, Value: schema}
+}
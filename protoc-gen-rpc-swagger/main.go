@@ -35,10 +35,13 @@ package main
 
 import (
 	"flag"
+	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"github.com/hertz-contrib/swagger-generate/common/pbgen"
 	"github.com/hertz-contrib/swagger-generate/protoc-gen-rpc-swagger/generator"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/types/pluginpb"
@@ -48,17 +51,36 @@ var flags flag.FlagSet
 
 func main() {
 	conf := generator.Configuration{
-		Version:        flags.String("version", "3.0.3", "version number text, e.g. 1.2.3"),
-		Title:          flags.String("title", "", "name of the API"),
-		Description:    flags.String("description", "", "description of the API"),
-		Naming:         flags.String("naming", "json", `naming convention. Use "proto" for passing names directly from the proto files`),
-		FQSchemaNaming: flags.Bool("fq_schema_naming", false, `schema naming convention. If "true", generates fully-qualified schema names by prefixing them with the proto message package name`),
-		EnumType:       flags.String("enum_type", "integer", `type for enum serialization. Use "string" for string-based serialization`),
-		OutputMode:     flags.String("output_mode", "merged", `output generation mode. By default, a single openapi.yaml is generated at the out folder. Use "source_relative' to generate a separate '[inputfile].openapi.yaml' next to each '[inputfile].proto'.`),
+		Version:                  flags.String("version", "3.0.3", "version number text, e.g. 1.2.3"),
+		Title:                    flags.String("title", "", "name of the API"),
+		Description:              flags.String("description", "", "description of the API"),
+		Naming:                   flags.String("naming", "json", `naming convention. Use "proto" for passing names directly from the proto files`),
+		FQSchemaNaming:           flags.Bool("fq_schema_naming", false, `schema naming convention. If "true", generates fully-qualified schema names by prefixing them with the proto message package name`),
+		EnumType:                 flags.String("enum_type", "integer", `type for enum serialization. Use "string" for string-based serialization`),
+		OutputMode:               flags.String("output_mode", "merged", `output generation mode. By default, a single openapi.yaml is generated at the out folder. Use "source_relative' to generate a separate '[inputfile].openapi.yaml' next to each '[inputfile].proto'.`),
+		OneofStyle:               flags.String("oneof_style", pbgen.OneofStyleOneOf, `oneof rendering. By default, each oneof becomes a "oneOf" composition of its members. Use "flatten" to keep the members as plain properties listed in an "x-oneof" extension`),
+		DefaultResponse:          flags.String("default_response", "", `full name of a message, e.g. "google.rpc.Status", added as the "default" response of every operation. Use the "openapi.skip_default_response" method option to leave it out of a method`),
+		SchemaFile:               flags.String("schema_file", "", `path of a YAML or JSON file mapping full message names, e.g. "google.type.Money", to the OpenAPI schema used for fields of that type`),
+		SplitIOSchemas:           flags.Bool("split_io_schemas", false, `generate separate "<Message>Input" and "<Message>Output" schemas for messages with INPUT_ONLY or OUTPUT_ONLY fields, leaving out of each the fields of the other direction`),
+		StreamContentType:        flags.String("stream_content_type", consts.ContentTypeNDJSON, `content type of the response streams of server streaming methods. Use "text/event-stream" to document them as server-sent events`),
+		PathStyle:                flags.String("path_style", consts.PathStyleService, `path of the RPC methods. By default, methods are documented at "/{Service}/{Method}". Use "method" for "/{Method}" paths`),
+		MetainfoStyle:            flags.String("metainfo_style", consts.MetainfoStyleHeader, `how the proxy carries metainfo. By default, it is sent and received in "X-Metainfo-*" and "X-Metainfo-Persistent-*" headers. Use "query" to read it from the query parameters, a "p_" prefix marking persistent values, and merge the backward metainfo into the response body`),
+		DisableStreamingTryItOut: flags.Bool("disable_streaming_try_it_out", false, `mark streaming methods with "x-try-it-out: false" and explain in their description that they can not be called with "Try it out"`),
+		Strict:                   flags.Bool("strict", false, `fail the generation if any error is reported. By default, errors are logged and the generation continues`),
 	}
 
+	var importPaths []string
+	flags.Func("import_path", "import root of the IDL, relative to the working directory of the server, used by the proxy besides the roots of the generated files. May be repeated", func(path string) error {
+		importPaths = append(importPaths, path)
+		return nil
+	})
+
 	serverConf := generator.ServerConfiguration{
-		KitexAddr: flags.String("kitex_addr", "127.0.0.1:8888", "kitex server address"),
+		HertzAddr:     flags.String("hertz_addr", consts.DefaultHertzAddr, "address of the standalone debug server started by RunStandalone"),
+		KitexAddr:     flags.String("kitex_addr", "127.0.0.1:8888", "kitex server address"),
+		PathStyle:     conf.PathStyle,
+		MetainfoStyle: conf.MetainfoStyle,
+		ImportPaths:   &importPaths,
 	}
 
 	opts := protogen.Options{
@@ -68,6 +90,8 @@ func main() {
 	opts.Run(func(plugin *protogen.Plugin) error {
 		// Enable "optional" keyword in front of type (e.g. optional string label = 1;)
 		plugin.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		diag := diagnostics.NewCollector()
+		var errorStatuses []map[string]map[int32]int
 		if *conf.OutputMode == "source_relative" {
 			for _, file := range plugin.Files {
 				if !file.Generate {
@@ -75,26 +99,37 @@ func main() {
 				}
 				outfileName := strings.TrimSuffix(file.Desc.Path(), filepath.Ext(file.Desc.Path())) + "." + consts.DefaultOutputYamlFile
 				outputFile := plugin.NewGeneratedFile(outfileName, "")
-				gen := generator.NewOpenAPIGenerator(plugin, conf, []*protogen.File{file})
-				if err := gen.Run(outputFile); err != nil {
+				gen := generator.NewOpenAPIGenerator(plugin, conf, []*protogen.File{file}, diag)
+				if err := gen.Run(outputFile, outfileName); err != nil {
 					return err
 				}
+				errorStatuses = append(errorStatuses, gen.ErrorStatuses())
 			}
 		} else {
 			outputFile := plugin.NewGeneratedFile(consts.DefaultOutputYamlFile, "")
-			gen := generator.NewOpenAPIGenerator(plugin, conf, plugin.Files)
-			if err := gen.Run(outputFile); err != nil {
+			gen := generator.NewOpenAPIGenerator(plugin, conf, plugin.Files, diag)
+			if err := gen.Run(outputFile, consts.DefaultOutputYamlFile); err != nil {
 				return err
 			}
+			errorStatuses = append(errorStatuses, gen.ErrorStatuses())
 		}
 		outputFile := plugin.NewGeneratedFile(consts.DefaultOutputSwaggerFile, "")
-		gen, err := generator.NewServerGenerator(serverConf, plugin.Files)
+		gen, err := generator.NewServerGenerator(serverConf, plugin.Files, diag)
 		if err != nil {
 			return err
 		}
 		if err = gen.Generate(outputFile); err != nil {
 			return err
 		}
-		return nil
+		if err = gen.GenerateProxy(plugin.NewGeneratedFile(consts.DefaultOutputProxyFile, "")); err != nil {
+			return err
+		}
+		for _, statuses := range errorStatuses {
+			gen.AddErrorStatuses(statuses)
+		}
+		if err = gen.GenerateIdl(plugin.NewGeneratedFile(consts.DefaultOutputIdlFile, "")); err != nil {
+			return err
+		}
+		return diag.Report(os.Stderr, *conf.Strict)
 	})
 }
@@ -23,7 +23,12 @@ import (
 )
 
 type Arguments struct {
-	OutputDir string
+	OutputDir                string
+	DefaultResponse          string
+	StreamContentType        string
+	DisableStreamingTryItOut bool
+	SplitIOSchemas           bool
+	Strict                   bool
 }
 
 func (a *Arguments) Unpack(args []string) error {
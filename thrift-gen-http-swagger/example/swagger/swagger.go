@@ -20,19 +20,47 @@ package swagger
 import (
 	"context"
 	_ "embed"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
 	"github.com/hertz-contrib/cors"
 	"github.com/hertz-contrib/swagger"
 	swaggerFiles "github.com/swaggo/files"
+	"gopkg.in/yaml.v3"
 )
 
 //go:embed openapi.yaml
 var openapiYAML []byte
 
+// BindSwagger serves the Swagger UI and, in mock mode, answers the documented
+// operations registered after it, or having no route, with mock data.
 func BindSwagger(h *server.Hertz) {
 	h.Use(cors.Default())
+	if err := MockMode.loadEnv(); err != nil {
+		hlog.Fatal("Invalid mock options:", err)
+	}
+	if MockMode.Enabled {
+		mock, err := mockMiddleware(openapiYAML)
+		if err != nil {
+			hlog.Fatal("Failed to parse openapi.yaml:", err)
+		}
+		h.Use(mock)
+	}
 
 	h.GET("/swagger/*any", swagger.WrapHandler(
 		swaggerFiles.Handler,
@@ -44,3 +72,504 @@ func BindSwagger(h *server.Hertz) {
 		ctx.Write(openapiYAML)
 	})
 }
+
+// MockOptions configures the mock mode, in which the server answers the
+// documented operations with data synthesized from their response schema.
+type MockOptions struct {
+	Enabled bool
+	// Seed makes the synthesized data deterministic: a seed always gives the
+	// same response for an operation.
+	Seed int64
+	// FixtureDir holds static responses overriding the synthesized ones, in
+	// <operationId>.json files read on every request.
+	FixtureDir string
+
+	flags *flag.FlagSet
+}
+
+// MockMode configures the mock mode. Set it before the server starts. The
+// SWAGGER_MOCK, SWAGGER_MOCK_SEED and SWAGGER_MOCK_FIXTURE_DIR environment
+// variables override it when the server starts, unless their flag was set.
+var MockMode MockOptions
+
+// loadEnv overrides the options by the environment variables.
+func (o *MockOptions) loadEnv() error {
+	return loadEnv(o.flags, []envVar{
+		{"SWAGGER_MOCK", "swagger-mock", func(v string) (err error) {
+			o.Enabled, err = strconv.ParseBool(v)
+			return err
+		}},
+		{"SWAGGER_MOCK_SEED", "swagger-mock-seed", func(v string) (err error) {
+			o.Seed, err = strconv.ParseInt(v, 10, 64)
+			return err
+		}},
+		{"SWAGGER_MOCK_FIXTURE_DIR", "swagger-mock-fixture-dir", func(v string) error {
+			o.FixtureDir = v
+			return nil
+		}},
+	})
+}
+
+// envVar is an environment variable overriding an option, unless the flag of
+// the option was set.
+type envVar struct {
+	name string
+	flag string
+	set  func(string) error
+}
+
+// loadEnv sets the options of the environment variables that are not empty.
+// fs holds the flags of the options, if they were registered.
+func loadEnv(fs *flag.FlagSet, vars []envVar) error {
+	set := map[string]bool{}
+	if fs != nil {
+		fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	}
+	for _, v := range vars {
+		value := os.Getenv(v.name)
+		if value == "" || set[v.flag] {
+			continue
+		}
+		if err := v.set(value); err != nil {
+			return fmt.Errorf("invalid %s: %w", v.name, err)
+		}
+	}
+	return nil
+}
+
+// RegisterFlags registers the flags overriding the options in fs, e.g. in
+// flag.CommandLine before flag.Parse is called.
+func (o *MockOptions) RegisterFlags(fs *flag.FlagSet) {
+	o.flags = fs
+	fs.BoolVar(&o.Enabled, "swagger-mock", o.Enabled, "answer the documented operations with mock data")
+	fs.Int64Var(&o.Seed, "swagger-mock-seed", o.Seed, "seed of the mock data")
+	fs.StringVar(&o.FixtureDir, "swagger-mock-fixture-dir", o.FixtureDir, "directory of the <operationId>.json responses overriding the mock data")
+}
+
+// mockMiddleware answers the operations documented by spec with mock data.
+func mockMiddleware(spec []byte) (app.HandlerFunc, error) {
+	d, err := newMockDocument(spec)
+	if err != nil {
+		return nil, err
+	}
+	return func(c context.Context, ctx *app.RequestContext) {
+		if d.serve(ctx) {
+			ctx.Abort()
+			return
+		}
+		ctx.Next(c)
+	}, nil
+}
+
+// mockDocument answers the operations of an OpenAPI document with mock data.
+type mockDocument struct {
+	operations []*mockOperation
+	schemas    map[string]interface{}
+}
+
+type mockOperation struct {
+	*specOperation
+	id     string
+	status int
+	schema interface{}
+}
+
+func newMockDocument(spec []byte) (*mockDocument, error) {
+	root, err := parseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	d := &mockDocument{schemas: specObject(specObject(root["components"])["schemas"])}
+	for _, op := range specOperations(root) {
+		id, _ := op.spec["operationId"].(string)
+		status, schema := mockResponse(specObject(op.spec["responses"]))
+		d.operations = append(d.operations, &mockOperation{
+			specOperation: op,
+			id:            id,
+			status:        status,
+			schema:        schema,
+		})
+	}
+	return d, nil
+}
+
+// mockResponse returns the status and the JSON schema of the first success
+// response.
+func mockResponse(responses map[string]interface{}) (int, interface{}) {
+	var codes []int
+	for code := range responses {
+		if c, err := strconv.Atoi(code); err == nil && c >= 200 && c < 300 {
+			codes = append(codes, c)
+		}
+	}
+	if len(codes) == 0 {
+		return http.StatusOK, nil
+	}
+	sort.Ints(codes)
+	content := specObject(specObject(responses[strconv.Itoa(codes[0])])["content"])
+	return codes[0], specObject(content["application/json"])["schema"]
+}
+
+// serve answers the request with the fixture or the mock data of its
+// operation, reporting whether the operation is documented.
+func (d *mockDocument) serve(ctx *app.RequestContext) bool {
+	method, path := string(ctx.Method()), string(ctx.Path())
+	var op *mockOperation
+	for _, o := range d.operations {
+		if o.matches(method, path) {
+			op = o
+			break
+		}
+	}
+	if op == nil {
+		return false
+	}
+
+	if MockMode.FixtureDir != "" && op.id != "" {
+		fixture, err := os.ReadFile(filepath.Join(MockMode.FixtureDir, op.id+".json"))
+		if err == nil {
+			ctx.Data(op.status, "application/json", fixture)
+			return true
+		}
+		if !os.IsNotExist(err) {
+			hlog.Errorf("Failed to read fixture: %v", err)
+		}
+	}
+	if op.schema == nil {
+		ctx.SetStatusCode(op.status)
+		return true
+	}
+
+	// Each operation draws from its own source, so that its data does not
+	// depend on the requests made before.
+	h := fnv.New64a()
+	h.Write([]byte(op.method + " " + op.path.String()))
+	rnd := rand.New(rand.NewSource(MockMode.Seed ^ int64(h.Sum64())))
+	ctx.JSON(op.status, d.value(op.schema, rnd, 0))
+	return true
+}
+
+// mockMaxDepth bounds the nesting of the mock data of recursive schemas.
+const mockMaxDepth = 8
+
+// value synthesizes a value of schema from its example, default or enum, or
+// else from its type, format and constraints.
+func (d *mockDocument) value(schema interface{}, rnd *rand.Rand, depth int) interface{} {
+	s := specObject(schema)
+	if s == nil || depth > mockMaxDepth {
+		return nil
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		return d.value(d.schemas[strings.TrimPrefix(ref, "#/components/schemas/")], rnd, depth+1)
+	}
+	if v, ok := s["example"]; ok {
+		return v
+	}
+	if v, ok := s["default"]; ok {
+		return v
+	}
+	if values, ok := s["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[rnd.Intn(len(values))]
+	}
+
+	// The schema is combined with all of its allOf schemas and with one of its
+	// oneOf or anyOf schemas: the objects they describe are merged into the
+	// object of the schema itself.
+	var branches []interface{}
+	if schemas, ok := s["allOf"].([]interface{}); ok {
+		branches = append(branches, schemas...)
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if schemas, ok := s[key].([]interface{}); ok && len(schemas) > 0 {
+			branches = append(branches, schemas[rnd.Intn(len(schemas))])
+		}
+	}
+	value := d.typed(s, rnd, depth)
+	if len(branches) == 0 {
+		return value
+	}
+	obj, _ := value.(map[string]interface{})
+	for _, branch := range branches {
+		v := d.value(branch, rnd, depth+1)
+		sub, ok := v.(map[string]interface{})
+		if !ok {
+			if value == nil {
+				value = v
+			}
+			continue
+		}
+		if obj == nil {
+			obj = map[string]interface{}{}
+		}
+		for k, v := range sub {
+			obj[k] = v
+		}
+	}
+	if obj != nil {
+		return obj
+	}
+	return value
+}
+
+// typed synthesizes a value of schema from its type, format and constraints.
+func (d *mockDocument) typed(s map[string]interface{}, rnd *rand.Rand, depth int) interface{} {
+	switch s["type"] {
+	case "object":
+		properties := specObject(s["properties"])
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		obj := make(map[string]interface{}, len(names))
+		for _, name := range names {
+			obj[name] = d.value(properties[name], rnd, depth+1)
+		}
+		return obj
+	case "array":
+		items := make([]interface{}, mockLength(s, "minItems", "maxItems", 1, 3, rnd))
+		for i := range items {
+			items[i] = d.value(s["items"], rnd, depth+1)
+		}
+		return items
+	case "string":
+		return mockString(s, rnd)
+	case "integer":
+		lo, hi := mockRange(s, 1)
+		return mockInteger(lo, hi, rnd)
+	case "number":
+		lo, hi := mockRange(s, 0)
+		return lo + rnd.Float64()*(hi-lo)
+	case "boolean":
+		return rnd.Intn(2) == 1
+	}
+	return nil
+}
+
+// mockRange returns the bounds of a number schema, moved by step inside the
+// exclusive ones.
+func mockRange(s map[string]interface{}, step float64) (float64, float64) {
+	lo, hasLo := specNumber(s["minimum"])
+	hi, hasHi := specNumber(s["maximum"])
+	switch {
+	case hasLo && !hasHi:
+		hi = lo + 100
+	case !hasLo && hasHi:
+		lo = hi - 100
+	case !hasLo && !hasHi:
+		lo, hi = 0, 100
+	}
+	if exclusive, _ := s["exclusiveMinimum"].(bool); exclusive {
+		lo += step
+	}
+	if exclusive, _ := s["exclusiveMaximum"].(bool); exclusive {
+		hi -= step
+	}
+	if step == 1 {
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// mockInteger returns an integer within [lo, hi], both clamped to the int64
+// range. The width of the range is computed in uint64, as it may exceed
+// math.MaxInt64.
+func mockInteger(lo, hi float64, rnd *rand.Rand) int64 {
+	first, last := clampInt64(lo), clampInt64(hi)
+	if last <= first {
+		return first
+	}
+	n := uint64(last) - uint64(first)
+	if n < math.MaxInt64 {
+		return first + rnd.Int63n(int64(n)+1)
+	}
+	for {
+		// More than half of the values are at most n, or all of them if the
+		// range is the whole int64 range.
+		if v := rnd.Uint64(); n == math.MaxUint64 || v <= n {
+			return first + int64(v)
+		}
+	}
+}
+
+// clampInt64 converts f to the nearest int64.
+func clampInt64(f float64) int64 {
+	switch {
+	case f >= math.MaxInt64:
+		return math.MaxInt64
+	case f <= math.MinInt64:
+		return math.MinInt64
+	}
+	return int64(f)
+}
+
+// mockLength returns a length within the bounds named by minKey and maxKey,
+// defaulting to min and max.
+func mockLength(s map[string]interface{}, minKey, maxKey string, min, max int, rnd *rand.Rand) int {
+	if v, ok := specNumber(s[minKey]); ok {
+		min = int(v)
+		if max < min {
+			max = min
+		}
+	}
+	if v, ok := specNumber(s[maxKey]); ok {
+		max = int(v)
+		if min > max {
+			min = max
+		}
+	}
+	return min + rnd.Intn(max-min+1)
+}
+
+func mockString(s map[string]interface{}, rnd *rand.Rand) string {
+	switch s["format"] {
+	case "date-time":
+		return mockTime(rnd).Format(time.RFC3339)
+	case "date":
+		return mockTime(rnd).Format("2006-01-02")
+	case "time":
+		return mockTime(rnd).Format("15:04:05")
+	case "uuid":
+		b := make([]byte, 16)
+		rnd.Read(b)
+		b[6], b[8] = b[6]&0x0f|0x40, b[8]&0x3f|0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "email":
+		return mockWord(8, rnd) + "@example.com"
+	case "uri", "url":
+		return "https://example.com/" + mockWord(8, rnd)
+	case "hostname":
+		return mockWord(8, rnd) + ".example.com"
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", rnd.Intn(254)+1)
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", rnd.Intn(0xffff)+1)
+	case "byte", "binary":
+		b := make([]byte, mockLength(s, "minLength", "maxLength", 4, 12, rnd))
+		rnd.Read(b)
+		return base64.StdEncoding.EncodeToString(b)
+	case "int64", "uint64", "fixed64", "sfixed64", "sint64":
+		return strconv.FormatInt(rnd.Int63n(1000000), 10)
+	}
+	return mockWord(mockLength(s, "minLength", "maxLength", 5, 10, rnd), rnd)
+}
+
+func mockTime(rnd *rand.Rand) time.Time {
+	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(rnd.Int63n(365*24*3600)) * time.Second)
+}
+
+func mockWord(n int, rnd *rand.Rand) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + rnd.Intn(26))
+	}
+	return string(b)
+}
+
+func specNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// specOperation is an operation of an OpenAPI document.
+type specOperation struct {
+	method string         // Upper-case HTTP method.
+	path   *regexp.Regexp // Request paths of the operation.
+	params int            // Number of parameters of the path template.
+	spec   map[string]interface{}
+}
+
+var specPathParam = regexp.MustCompile("\\{[^/}]+\\}")
+
+// specOperations returns the operations of the document, the literal paths
+// before the templated ones they match.
+func specOperations(root map[string]interface{}) []*specOperation {
+	var operations []*specOperation
+	for path, item := range specObject(root["paths"]) {
+		parts := specPathParam.Split(path, -1)
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		pattern := regexp.MustCompile("^" + strings.Join(parts, "[^/]+") + "$")
+		for method, operation := range specObject(item) {
+			switch method {
+			case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+			default:
+				continue
+			}
+			operations = append(operations, &specOperation{
+				method: strings.ToUpper(method),
+				path:   pattern,
+				params: len(parts) - 1,
+				spec:   specObject(operation),
+			})
+		}
+	}
+	sort.SliceStable(operations, func(i, j int) bool {
+		if operations[i].params != operations[j].params {
+			return operations[i].params < operations[j].params
+		}
+		if operations[i].path.String() != operations[j].path.String() {
+			return operations[i].path.String() < operations[j].path.String()
+		}
+		return operations[i].method < operations[j].method
+	})
+	return operations
+}
+
+// matches reports whether the operation answers the requests of method on
+// path.
+func (op *specOperation) matches(method, path string) bool {
+	return op.method == method && op.path.MatchString(path)
+}
+
+// parseSpec parses an OpenAPI document into JSON values.
+func parseSpec(spec []byte) (map[string]interface{}, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, err
+	}
+	return specObject(specJSON(doc)), nil
+}
+
+// specObject returns v as a JSON object, or nil.
+func specObject(v interface{}) map[string]interface{} {
+	obj, _ := v.(map[string]interface{})
+	return obj
+}
+
+// specJSON converts the YAML maps of v, whose keys may not be strings, to JSON
+// objects.
+func specJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = specJSON(e)
+		}
+		return v
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			obj[fmt.Sprint(k)] = specJSON(e)
+		}
+		return obj
+	case []interface{}:
+		for i, e := range v {
+			v[i] = specJSON(e)
+		}
+		return v
+	}
+	return v
+}
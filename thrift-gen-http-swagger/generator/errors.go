@@ -0,0 +1,52 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"github.com/cloudwego/thriftgo/thrift_reflection"
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/thriftgen"
+	openapi "github.com/hertz-contrib/swagger-generate/idl/thrift"
+)
+
+// addDefaultResponse adds the struct of the `DefaultResponse` argument as the
+// `default` response of op, unless the method is annotated with
+// `openapi.skip_default_response="true"`.
+func (g *OpenAPIGenerator) addDefaultResponse(d *openapi.Document, m *thrift_reflection.MethodDescriptor, op *openapi.Operation) {
+	if g.defaultResponse == nil || thriftgen.SkipDefaultResponse(m) {
+		return
+	}
+	if op.Responses == nil {
+		op.Responses = &openapi.Responses{}
+	}
+	if op.Responses.Default != nil {
+		return
+	}
+
+	header, content := g.getResponseForStruct(d, g.defaultResponse)
+	response := &openapi.Response{Description: g.filterCommentString(g.defaultResponse.Comments)}
+	if response.Description == "" {
+		response.Description = consts.DefaultErrorResponseDesc
+	}
+	if header != nil && len(header.AdditionalProperties) != 0 {
+		response.Headers = header
+	}
+	if content != nil && len(content.AdditionalProperties) != 0 {
+		response.Content = content
+	}
+	op.Responses.Default = &openapi.ResponseOrReference{Response: response}
+}
@@ -34,41 +34,54 @@
 package generator
 
 import (
+	"fmt"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 
-	"github.com/cloudwego/hertz/cmd/hz/util/logs"
 	"github.com/cloudwego/thriftgo/parser"
 	"github.com/cloudwego/thriftgo/plugin"
 	"github.com/cloudwego/thriftgo/thrift_reflection"
 	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"github.com/hertz-contrib/swagger-generate/common/thriftgen"
 	common "github.com/hertz-contrib/swagger-generate/common/utils"
+	"github.com/hertz-contrib/swagger-generate/common/validator"
 	openapi "github.com/hertz-contrib/swagger-generate/idl/thrift"
 	"github.com/hertz-contrib/swagger-generate/thrift-gen-http-swagger/args"
 	"github.com/hertz-contrib/swagger-generate/thrift-gen-http-swagger/utils"
 )
 
 type OpenAPIGenerator struct {
-	fileDesc         *thrift_reflection.FileDescriptor
-	ast              *parser.Thrift
-	generatedSchemas []string
-	requiredSchemas  []string
-	requiredTypeDesc []*thrift_reflection.StructDescriptor
+	fileDesc                 *thrift_reflection.FileDescriptor
+	ast                      *parser.Thrift
+	diag                     *diagnostics.Collector
+	src                      *diagnostics.ThriftSource
+	generatedSchemas         []string
+	requiredSchemas          []string
+	requiredTypeDesc         []*thrift_reflection.StructDescriptor
+	defaultResponse          *thrift_reflection.StructDescriptor // Struct added as the `default` response of every operation.
+	streamContentType        string
+	disableStreamingTryItOut bool
+	splitIOSchemas           bool
+	direction                string // Direction of the schemas being built, for `SplitIOSchemas`.
 }
 
-// NewOpenAPIGenerator creates a new generator for a protoc plugin invocation.
-func NewOpenAPIGenerator(ast *parser.Thrift) *OpenAPIGenerator {
+// NewOpenAPIGenerator creates a new generator for a thriftgo plugin invocation.
+// Problems found while generating are reported to diag.
+func NewOpenAPIGenerator(ast *parser.Thrift, diag *diagnostics.Collector) *OpenAPIGenerator {
 	_, fileDesc := thrift_reflection.RegisterAST(ast)
 	return &OpenAPIGenerator{
 		fileDesc:         fileDesc,
 		ast:              ast,
+		diag:             diag,
+		src:              diagnostics.NewThriftSource(),
 		generatedSchemas: make([]string, 0),
 	}
 }
 
-func (g *OpenAPIGenerator) BuildDocument(arguments *args.Arguments) []*plugin.Generated {
+func (g *OpenAPIGenerator) BuildDocument(arguments *args.Arguments) ([]*plugin.Generated, error) {
 	d := &openapi.Document{}
 
 	version := consts.OpenAPIVersion
@@ -85,20 +98,17 @@ func (g *OpenAPIGenerator) BuildDocument(arguments *args.Arguments) []*plugin.Ge
 		},
 	}
 
-	var extDocument *openapi.Document
-	err := g.getDocumentOption(&extDocument)
+	err := g.mergeDocumentOption(d)
 	if err != nil {
-		logs.Errorf("Error merging document option: %s", err)
-		return nil
-	}
-	if extDocument != nil {
-		err := common.MergeStructs(d, extDocument)
-		if err != nil {
-			logs.Errorf("Error merging document option: %s", err)
-			return nil
-		}
+		kind, name := g.getDocumentAnnotationInWhichServiceOrStruct()
+		g.diag.ErrorfAt(g.src.Document(g.ast.Filename, kind, name), "Error getting document option: %s", err)
 	}
 
+	g.defaultResponse = thriftgen.LookupDefaultResponse(g.fileDesc, arguments.DefaultResponse, g.diag)
+	g.streamContentType = thriftgen.LookupStreamContentType(arguments.StreamContentType, g.diag)
+	g.disableStreamingTryItOut = arguments.DisableStreamingTryItOut
+	g.splitIOSchemas = arguments.SplitIOSchemas
+
 	g.addPathsToDocument(d, g.fileDesc.GetServices())
 
 	for len(g.requiredSchemas) > 0 {
@@ -205,24 +215,25 @@ func (g *OpenAPIGenerator) BuildDocument(arguments *args.Arguments) []*plugin.Ge
 
 	bytes, err := d.YAMLValue("Generated with " + consts.PluginNameThriftHttpSwagger + "\n" + consts.InfoURL + "blob/main/" + consts.PluginNameThriftHttpSwagger)
 	if err != nil {
-		logs.Errorf("Error converting to yaml: %s", err)
-		return nil
+		return nil, fmt.Errorf("failed to convert document to yaml: %s", err)
 	}
 	outputDir := arguments.OutputDir
 	if outputDir == "" {
 		outputDir = consts.DefaultOutputDir
 	}
 	filePath := filepath.Join(outputDir, consts.DefaultOutputYamlFile)
+	validator.Validate(filePath, bytes, g.diag)
+
 	var ret []*plugin.Generated
 	ret = append(ret, &plugin.Generated{
 		Content: string(bytes),
 		Name:    &filePath,
 	})
 
-	return ret
+	return ret, nil
 }
 
-func (g *OpenAPIGenerator) getDocumentOption(obj interface{}) error {
+func (g *OpenAPIGenerator) mergeDocumentOption(d *openapi.Document) error {
 	serviceOrStruct, name := g.getDocumentAnnotationInWhichServiceOrStruct()
 
 	if serviceOrStruct == "" || name == "" {
@@ -232,7 +243,7 @@ func (g *OpenAPIGenerator) getDocumentOption(obj interface{}) error {
 	if serviceOrStruct == consts.DocumentOptionServiceType {
 		serviceDesc := g.fileDesc.GetServiceDescriptor(name)
 		if serviceDesc != nil {
-			err := utils.ParseServiceOption(serviceDesc, consts.OpenapiDocument, obj)
+			err := utils.MergeServiceOption(serviceDesc, consts.OpenapiDocument, d)
 			if err != nil {
 				return err
 			}
@@ -240,7 +251,7 @@ func (g *OpenAPIGenerator) getDocumentOption(obj interface{}) error {
 	} else if serviceOrStruct == consts.DocumentOptionStructType {
 		structDesc := g.fileDesc.GetStructDescriptor(name)
 		if structDesc != nil {
-			err := utils.ParseStructOption(structDesc, consts.OpenapiDocument, obj)
+			err := utils.MergeStructOption(structDesc, consts.OpenapiDocument, d)
 			if err != nil {
 				return err
 			}
@@ -276,16 +287,16 @@ func (g *OpenAPIGenerator) addPathsToDocument(d *openapi.Document, services []*t
 
 				if len(m.Args) > 0 {
 					if len(m.Args) > 1 {
-						logs.Warnf("function '%s' has more than one argument, but only the first can be used in plugin now", m.GetName())
+						g.diag.WarnfAt(g.src.MethodOf(s, m, ""), "function '%s' has more than one argument, but only the first can be used in plugin now", m.GetName())
 					}
 					// TODO: support more argument types
 					if m.Args[0].GetType().IsStruct() {
 						inputDesc, err = m.Args[0].GetType().GetStructDescriptor()
 						if err != nil {
-							logs.Errorf("Error getting arguments descriptor: %s", err)
+							g.diag.ErrorfAt(g.src.MethodOf(s, m, ""), "Error getting arguments descriptor: %s", err)
 						}
 					} else {
-						logs.Errorf("now only support struct type for input, but got %s", m.Args[0].GetType().GetName())
+						g.diag.ErrorfAt(g.src.MethodOf(s, m, ""), "now only support struct type for input, but got %s", m.Args[0].GetType().GetName())
 					}
 				}
 
@@ -293,16 +304,16 @@ func (g *OpenAPIGenerator) addPathsToDocument(d *openapi.Document, services []*t
 				if m.Response.IsStruct() {
 					outputDesc, err = m.Response.GetStructDescriptor()
 					if err != nil {
-						logs.Errorf("Error getting response descriptor: %s", err)
+						g.diag.ErrorfAt(g.src.MethodOf(s, m, ""), "Error getting response descriptor: %s", err)
 					}
 				} else if m.Response.Name != "void" {
-					logs.Errorf("now only support struct type for output, but got %s", m.Response.Name)
+					g.diag.ErrorfAt(g.src.MethodOf(s, m, ""), "now only support struct type for output, but got %s", m.Response.Name)
 				}
 
 				if len(m.ThrowExceptions) > 0 {
 					throwDesc, err = m.ThrowExceptions[0].GetType().GetExceptionDescriptor()
 					if err != nil {
-						logs.Errorf("Error getting exception descriptor: %s", err)
+						g.diag.ErrorfAt(g.src.MethodOf(s, m, ""), "Error getting exception descriptor: %s", err)
 					}
 				}
 
@@ -322,14 +333,13 @@ func (g *OpenAPIGenerator) addPathsToDocument(d *openapi.Document, services []*t
 
 						op, path2 := g.buildOperation(d, methodName, comment, operationID, s.GetName(), path[0], host, inputDesc, outputDesc, throwDesc)
 
-						newOp := &openapi.Operation{}
-						err = utils.ParseMethodOption(m, consts.OpenapiOperation, &newOp)
-						if err != nil {
-							logs.Errorf("Error parsing method option: %s", err)
-						}
-						err = common.MergeStructs(op, newOp)
+						g.addDefaultResponse(d, m, op)
+						thriftgen.ApplyStreaming(m, op, g.streamContentType, g.disableStreamingTryItOut)
+						thriftgen.ApplyDeprecation(s, m, op)
+
+						err = utils.MergeMethodOption(m, consts.OpenapiOperation, op)
 						if err != nil {
-							logs.Errorf("Error merging method option: %s", err)
+							g.diag.ErrorfAt(g.src.MethodOf(s, m, consts.OpenapiOperation), "Error parsing method option: %s", err)
 						}
 
 						g.addOperationToDocument(d, op, path2, methodName)
@@ -356,6 +366,10 @@ func (g *OpenAPIGenerator) buildOperation(
 	outputDesc *thrift_reflection.StructDescriptor,
 	throwDesc *thrift_reflection.StructDescriptor,
 ) (*openapi.Operation, string) {
+	// Requests and responses use the schemas of their direction.
+	g.direction = thriftgen.DirectionInput
+	defer func() { g.direction = "" }()
+
 	// Parameters array to hold all parameter objects
 	var parameters []*openapi.ParameterOrReference
 
@@ -363,6 +377,9 @@ func (g *OpenAPIGenerator) buildOperation(
 
 	if inputDesc != nil {
 		for _, v := range inputDesc.GetFields() {
+			if thriftgen.HiddenField(v, g.schemaDirection()) {
+				continue
+			}
 			var paramName, paramIn, paramDesc string
 			var fieldSchema *openapi.SchemaOrReference
 			required := false
@@ -372,16 +389,14 @@ func (g *OpenAPIGenerator) buildOperation(
 				if ext := v.Annotations[consts.ApiQuery][0]; ext != "" {
 					paramIn = consts.ParameterInQuery
 					paramName = ext
-					paramDesc = g.filterCommentString(v.Comments)
+					paramDesc = thriftgen.FieldDescription(v)
 					fieldSchema = g.schemaOrReferenceForField(v.Type)
 					extPropertyOrNil := v.Annotations[consts.OpenapiProperty]
 					if len(extPropertyOrNil) > 0 && fieldSchema.IsSetSchema() {
-						newFieldSchema := &openapi.Schema{}
-						err := utils.ParseFieldOption(v, consts.OpenapiProperty, &newFieldSchema)
+						err := utils.MergeFieldOption(v, consts.OpenapiProperty, fieldSchema.Schema)
 						if err != nil {
-							logs.Errorf("Error parsing field option: %s", err)
+							g.diag.ErrorfAt(g.src.FieldOf(inputDesc, v, consts.OpenapiProperty), "Error parsing field option: %s", err)
 						}
-						common.MergeStructs(fieldSchema.Schema, newFieldSchema)
 					}
 				}
 			}
@@ -390,16 +405,14 @@ func (g *OpenAPIGenerator) buildOperation(
 				if ext := v.Annotations[consts.ApiPath][0]; ext != "" {
 					paramIn = consts.ParameterInPath
 					paramName = ext
-					paramDesc = g.filterCommentString(v.Comments)
+					paramDesc = thriftgen.FieldDescription(v)
 					fieldSchema = g.schemaOrReferenceForField(v.Type)
 					extPropertyOrNil := v.Annotations[consts.OpenapiProperty]
 					if len(extPropertyOrNil) > 0 && fieldSchema.IsSetSchema() {
-						newFieldSchema := &openapi.Schema{}
-						err := utils.ParseFieldOption(v, consts.OpenapiProperty, &newFieldSchema)
+						err := utils.MergeFieldOption(v, consts.OpenapiProperty, fieldSchema.Schema)
 						if err != nil {
-							logs.Errorf("Error parsing field option: %s", err)
+							g.diag.ErrorfAt(g.src.FieldOf(inputDesc, v, consts.OpenapiProperty), "Error parsing field option: %s", err)
 						}
-						common.MergeStructs(fieldSchema.Schema, newFieldSchema)
 					}
 					required = true
 				}
@@ -409,16 +422,14 @@ func (g *OpenAPIGenerator) buildOperation(
 				if ext := v.Annotations[consts.ApiCookie][0]; ext != "" {
 					paramIn = consts.ParameterInCookie
 					paramName = ext
-					paramDesc = g.filterCommentString(v.Comments)
+					paramDesc = thriftgen.FieldDescription(v)
 					fieldSchema = g.schemaOrReferenceForField(v.Type)
 					extPropertyOrNil := v.Annotations[consts.OpenapiProperty]
 					if len(extPropertyOrNil) > 0 && fieldSchema.IsSetSchema() {
-						newFieldSchema := &openapi.Schema{}
-						err := utils.ParseFieldOption(v, consts.OpenapiProperty, &newFieldSchema)
+						err := utils.MergeFieldOption(v, consts.OpenapiProperty, fieldSchema.Schema)
 						if err != nil {
-							logs.Errorf("Error parsing field option: %s", err)
+							g.diag.ErrorfAt(g.src.FieldOf(inputDesc, v, consts.OpenapiProperty), "Error parsing field option: %s", err)
 						}
-						common.MergeStructs(fieldSchema.Schema, newFieldSchema)
 					}
 				}
 			}
@@ -427,16 +438,14 @@ func (g *OpenAPIGenerator) buildOperation(
 				if ext := v.Annotations[consts.ApiHeader][0]; ext != "" {
 					paramIn = consts.ParameterInHeader
 					paramName = ext
-					paramDesc = g.filterCommentString(v.Comments)
+					paramDesc = thriftgen.FieldDescription(v)
 					fieldSchema = g.schemaOrReferenceForField(v.Type)
 					extPropertyOrNil := v.Annotations[consts.OpenapiProperty]
 					if len(extPropertyOrNil) > 0 && fieldSchema.IsSetSchema() {
-						newFieldSchema := &openapi.Schema{}
-						err := utils.ParseFieldOption(v, consts.OpenapiProperty, &newFieldSchema)
+						err := utils.MergeFieldOption(v, consts.OpenapiProperty, fieldSchema.Schema)
 						if err != nil {
-							logs.Errorf("Error parsing field option: %s", err)
+							g.diag.ErrorfAt(g.src.FieldOf(inputDesc, v, consts.OpenapiProperty), "Error parsing field option: %s", err)
 						}
-						common.MergeStructs(fieldSchema.Schema, newFieldSchema)
 					}
 				}
 			}
@@ -446,15 +455,15 @@ func (g *OpenAPIGenerator) buildOperation(
 				In:          paramIn,
 				Description: paramDesc,
 				Required:    required,
+				Deprecated:  thriftgen.IsDeprecated(v.Annotations, v.Comments),
+				Example:     thriftgen.FieldExample(v),
 				Schema:      fieldSchema,
 			}
 
-			var extParameter *openapi.Parameter
-			err := utils.ParseFieldOption(v, consts.OpenapiParameter, &extParameter)
+			err := utils.MergeFieldOption(v, consts.OpenapiParameter, parameter)
 			if err != nil {
-				logs.Errorf("Error parsing field option: %s", err)
+				g.diag.ErrorfAt(g.src.FieldOf(inputDesc, v, consts.OpenapiParameter), "Error parsing field option: %s", err)
 			}
-			common.MergeStructs(parameter, extParameter)
 
 			// Append the parameter to the parameters array if it was set
 			if paramName != "" && paramIn != "" {
@@ -471,11 +480,11 @@ func (g *OpenAPIGenerator) buildOperation(
 
 			if bodySchema != nil && bodySchema.Properties != nil && len(bodySchema.Properties.AdditionalProperties) > 0 {
 				bodyRefSchema := &openapi.NamedSchemaOrReference{
-					Name:  inputDesc.GetName() + consts.ComponentSchemaSuffixBody,
+					Name:  thriftgen.SchemaName(inputDesc, g.schemaDirection()) + consts.ComponentSchemaSuffixBody,
 					Value: &openapi.SchemaOrReference{Schema: bodySchema},
 				}
 
-				bodyRef := consts.ComponentSchemaPrefix + inputDesc.GetName() + consts.ComponentSchemaSuffixBody
+				bodyRef := consts.ComponentSchemaPrefix + thriftgen.SchemaName(inputDesc, g.schemaDirection()) + consts.ComponentSchemaSuffixBody
 
 				g.addSchemaToDocument(d, bodyRefSchema)
 
@@ -493,11 +502,11 @@ func (g *OpenAPIGenerator) buildOperation(
 
 			if formSchema != nil && formSchema.Properties != nil && len(formSchema.Properties.AdditionalProperties) > 0 {
 				formRefSchema := &openapi.NamedSchemaOrReference{
-					Name:  inputDesc.GetName() + consts.ComponentSchemaSuffixForm,
+					Name:  thriftgen.SchemaName(inputDesc, g.schemaDirection()) + consts.ComponentSchemaSuffixForm,
 					Value: &openapi.SchemaOrReference{Schema: formSchema},
 				}
 
-				formRef := consts.ComponentSchemaPrefix + inputDesc.GetName() + consts.ComponentSchemaSuffixForm
+				formRef := consts.ComponentSchemaPrefix + thriftgen.SchemaName(inputDesc, g.schemaDirection()) + consts.ComponentSchemaSuffixForm
 
 				g.addSchemaToDocument(d, formRefSchema)
 
@@ -524,11 +533,11 @@ func (g *OpenAPIGenerator) buildOperation(
 
 			if rawBodySchema != nil && rawBodySchema.Properties != nil && len(rawBodySchema.Properties.AdditionalProperties) > 0 {
 				rawBodyRefSchema := &openapi.NamedSchemaOrReference{
-					Name:  inputDesc.GetName() + consts.ComponentSchemaSuffixRawBody,
+					Name:  thriftgen.SchemaName(inputDesc, g.schemaDirection()) + consts.ComponentSchemaSuffixRawBody,
 					Value: &openapi.SchemaOrReference{Schema: rawBodySchema},
 				}
 
-				rawBodyRef := consts.ComponentSchemaPrefix + inputDesc.GetName() + consts.ComponentSchemaSuffixRawBody
+				rawBodyRef := consts.ComponentSchemaPrefix + thriftgen.SchemaName(inputDesc, g.schemaDirection()) + consts.ComponentSchemaSuffixRawBody
 
 				g.addSchemaToDocument(d, rawBodyRefSchema)
 
@@ -556,6 +565,7 @@ func (g *OpenAPIGenerator) buildOperation(
 		}
 	}
 
+	g.direction = thriftgen.DirectionOutput
 	var responses *openapi.Responses
 
 	if outputDesc != nil {
@@ -581,8 +591,11 @@ func (g *OpenAPIGenerator) buildOperation(
 	re := regexp.MustCompile(`:(\w+)`)
 	path = re.ReplaceAllString(path, `{$1}`)
 
+	// The first sentence of the method comment is the summary.
+	summary, description := common.SplitSummary(description)
 	op := &openapi.Operation{
 		Tags:        []string{tagName},
+		Summary:     summary,
 		Description: description,
 		OperationID: operationID,
 		Parameters:  parameters,
@@ -661,13 +674,18 @@ func (g *OpenAPIGenerator) getResponseForStruct(d *openapi.Document, desc *thrif
 	headers := &openapi.HeadersOrReferences{AdditionalProperties: []*openapi.NamedHeaderOrReference{}}
 
 	for _, field := range desc.Fields {
+		if thriftgen.HiddenField(field, g.schemaDirection()) {
+			continue
+		}
 		if len(field.Annotations[consts.ApiHeader]) < 1 {
 			continue
 		}
 		if ext := field.Annotations[consts.ApiHeader][0]; ext != "" {
 			headerName := ext
 			header := &openapi.Header{
-				Description: g.filterCommentString(field.Comments),
+				Description: thriftgen.FieldDescription(field),
+				Deprecated:  thriftgen.IsDeprecated(field.Annotations, field.Comments),
+				Example:     thriftgen.FieldExample(field),
 				Schema:      g.schemaOrReferenceForField(field.Type),
 			}
 			headers.AdditionalProperties = append(headers.AdditionalProperties, &openapi.NamedHeaderOrReference{
@@ -685,10 +703,10 @@ func (g *OpenAPIGenerator) getResponseForStruct(d *openapi.Document, desc *thrif
 
 	if bodySchema != nil && bodySchema.Properties != nil && len(bodySchema.Properties.AdditionalProperties) > 0 {
 		refSchema := &openapi.NamedSchemaOrReference{
-			Name:  desc.GetName() + consts.ComponentSchemaSuffixBody,
+			Name:  thriftgen.SchemaName(desc, g.schemaDirection()) + consts.ComponentSchemaSuffixBody,
 			Value: &openapi.SchemaOrReference{Schema: bodySchema},
 		}
-		ref := consts.ComponentSchemaPrefix + desc.GetName() + consts.ComponentSchemaSuffixBody
+		ref := consts.ComponentSchemaPrefix + thriftgen.SchemaName(desc, g.schemaDirection()) + consts.ComponentSchemaSuffixBody
 		g.addSchemaToDocument(d, refSchema)
 		additionalProperties = append(additionalProperties, &openapi.NamedMediaType{
 			Name: consts.ContentTypeJSON,
@@ -712,21 +730,11 @@ func (g *OpenAPIGenerator) getSchemaWithoutOptions(inputDesc *thrift_reflection.
 		AdditionalProperties: make([]*openapi.NamedSchemaOrReference, 0),
 	}
 
-	var allRequired []string
-	var extSchema *openapi.Schema
-	err := utils.ParseStructOption(inputDesc, consts.OpenapiSchema, &extSchema)
-	if err != nil {
-		logs.Errorf("Error parsing struct option: %s", err)
-	}
-	if extSchema != nil {
-		if extSchema.Required != nil {
-			allRequired = extSchema.Required
-		}
-	}
-
-	var required []string
 afterFieldLoop:
 	for _, field := range inputDesc.GetFields() {
+		if thriftgen.HiddenField(field, g.schemaDirection()) {
+			continue
+		}
 		for _, opt := range blacklistOpts {
 			if field.Annotations[opt] != nil {
 				continue afterFieldLoop
@@ -735,27 +743,25 @@ afterFieldLoop:
 
 		extName := field.GetName()
 
-		if common.Contains(allRequired, extName) {
-			required = append(required, extName)
-		}
-
 		// Get the field description from the comments.
-		description := g.filterCommentString(field.Comments)
+		description := thriftgen.FieldDescription(field)
 		fieldSchema := g.schemaOrReferenceForField(field.Type)
 		if fieldSchema == nil {
 			continue
 		}
 
+		if thriftgen.IsDeprecated(field.Annotations, field.Comments) {
+			fieldSchema = thriftgen.DeprecateSchema(fieldSchema)
+		}
+		if example := thriftgen.FieldExample(field); example != nil {
+			fieldSchema = thriftgen.ExampleSchema(fieldSchema, example)
+		}
+		fieldSchema = thriftgen.BehaviorSchema(field, fieldSchema)
 		if fieldSchema.IsSetSchema() {
 			fieldSchema.Schema.Description = description
-			newFieldSchema := &openapi.Schema{}
-			err := utils.ParseFieldOption(field, consts.OpenapiProperty, &newFieldSchema)
+			err := utils.MergeFieldOption(field, consts.OpenapiProperty, fieldSchema.Schema)
 			if err != nil {
-				logs.Errorf("Error parsing field option: %s", err)
-			}
-			err = common.MergeStructs(fieldSchema.Schema, newFieldSchema)
-			if err != nil {
-				logs.Errorf("Error merging field option: %s", err)
+				g.diag.ErrorfAt(g.src.FieldOf(inputDesc, field, consts.OpenapiProperty), "Error parsing field option: %s", err)
 			}
 		}
 
@@ -771,16 +777,14 @@ afterFieldLoop:
 	schema := &openapi.Schema{
 		Type:       consts.SchemaObjectType,
 		Properties: definitionProperties,
+		Deprecated: thriftgen.IsDeprecated(inputDesc.Annotations, inputDesc.Comments),
 	}
 
-	if extSchema != nil {
-		err := common.MergeStructs(schema, extSchema)
-		if err != nil {
-			logs.Errorf("Error merging struct option: %s", err)
-		}
+	names := thriftgen.PropertyNames(schema)
+	if err := utils.MergeStructOption(inputDesc, consts.OpenapiSchema, schema); err != nil {
+		g.diag.ErrorfAt(g.src.StructOf(inputDesc, consts.OpenapiSchema), "Error parsing struct option: %s", err)
 	}
-
-	schema.Required = required
+	thriftgen.KeepProperties(schema, names)
 	return schema
 }
 
@@ -789,47 +793,35 @@ func (g *OpenAPIGenerator) getSchemaByOption(inputDesc *thrift_reflection.Struct
 		AdditionalProperties: make([]*openapi.NamedSchemaOrReference, 0),
 	}
 
-	var allRequired []string
-	var extSchema *openapi.Schema
-	err := utils.ParseStructOption(inputDesc, consts.OpenapiSchema, &extSchema)
-	if err != nil {
-		logs.Errorf("Error parsing struct option: %s", err)
-	}
-	if extSchema != nil {
-		if extSchema.Required != nil {
-			allRequired = extSchema.Required
-		}
-	}
-
-	var required []string
 	for _, field := range inputDesc.GetFields() {
+		if thriftgen.HiddenField(field, g.schemaDirection()) {
+			continue
+		}
 		if field.Annotations[option] != nil {
 			extName := field.GetName()
 			if field.Annotations[option] != nil && field.Annotations[option][0] != "" {
 				extName = field.Annotations[option][0]
 			}
 
-			if common.Contains(allRequired, extName) {
-				required = append(required, extName)
-			}
-
 			// Get the field description from the comments.
-			description := g.filterCommentString(field.Comments)
+			description := thriftgen.FieldDescription(field)
 			fieldSchema := g.schemaOrReferenceForField(field.Type)
 			if fieldSchema == nil {
 				continue
 			}
 
+			if thriftgen.IsDeprecated(field.Annotations, field.Comments) {
+				fieldSchema = thriftgen.DeprecateSchema(fieldSchema)
+			}
+			if example := thriftgen.FieldExample(field); example != nil {
+				fieldSchema = thriftgen.ExampleSchema(fieldSchema, example)
+			}
+			fieldSchema = thriftgen.BehaviorSchema(field, fieldSchema)
 			if fieldSchema.IsSetSchema() {
 				fieldSchema.Schema.Description = description
-				newFieldSchema := &openapi.Schema{}
-				err := utils.ParseFieldOption(field, consts.OpenapiProperty, &newFieldSchema)
+				err := utils.MergeFieldOption(field, consts.OpenapiProperty, fieldSchema.Schema)
 				if err != nil {
-					logs.Errorf("Error parsing field option: %s", err)
-				}
-				err = common.MergeStructs(fieldSchema.Schema, newFieldSchema)
-				if err != nil {
-					logs.Errorf("Error merging field option: %s", err)
+					g.diag.ErrorfAt(g.src.FieldOf(inputDesc, field, consts.OpenapiProperty), "Error parsing field option: %s", err)
 				}
 			}
 
@@ -846,59 +838,29 @@ func (g *OpenAPIGenerator) getSchemaByOption(inputDesc *thrift_reflection.Struct
 	schema := &openapi.Schema{
 		Type:       consts.SchemaObjectType,
 		Properties: definitionProperties,
+		Deprecated: thriftgen.IsDeprecated(inputDesc.Annotations, inputDesc.Comments),
 	}
 
-	if extSchema != nil {
-		err := common.MergeStructs(schema, extSchema)
-		if err != nil {
-			logs.Errorf("Error merging struct option: %s", err)
-		}
+	names := thriftgen.PropertyNames(schema)
+	if err := utils.MergeStructOption(inputDesc, consts.OpenapiSchema, schema); err != nil {
+		g.diag.ErrorfAt(g.src.StructOf(inputDesc, consts.OpenapiSchema), "Error parsing struct option: %s", err)
 	}
-
-	schema.Required = required
+	thriftgen.KeepProperties(schema, names)
 	return schema
 }
 
-// filterCommentString removes linter rules from comments.
+// filterCommentString removes comment markers and directives from comments.
 func (g *OpenAPIGenerator) filterCommentString(str string) string {
-	var comments []string
-	matches := regexp.MustCompile(consts.CommentPatternRegexp).FindAllStringSubmatch(str, -1)
-
-	for _, match := range matches {
-		if match[1] != "" {
-			// Handle one-line comments
-			comments = append(comments, strings.TrimSpace(match[1]))
-		} else if match[2] != "" {
-			// Handle multiline comments
-			multiLineComment := match[2]
-			lines := strings.Split(multiLineComment, "\n")
-
-			// Find the minimum indentation level (excluding empty lines)
-			minIndent := -1
-			for _, line := range lines {
-				trimmedLine := strings.TrimSpace(line)
-				if trimmedLine != "" {
-					lineIndent := len(line) - len(strings.TrimLeft(line, " "))
-					if minIndent == -1 || lineIndent < minIndent {
-						minIndent = lineIndent
-					}
-				}
-			}
-
-			// Remove the minimum indentation and any leading '*' from each line
-			for i, line := range lines {
-				if minIndent > 0 && len(line) >= minIndent {
-					line = line[minIndent:]
-				}
-				lines[i] = strings.TrimPrefix(line, "*")
-			}
+	return thriftgen.FilterComment(str)
+}
 
-			// Remove leading and trailing empty lines from the comment block
-			comments = append(comments, strings.TrimSpace(strings.Join(lines, "\n")))
-		}
+// schemaDirection returns the direction of the schemas being built, or ""
+// unless the `SplitIOSchemas` argument splits them.
+func (g *OpenAPIGenerator) schemaDirection() string {
+	if !g.splitIOSchemas {
+		return ""
 	}
-
-	return strings.Join(comments, "\n")
+	return g.direction
 }
 
 func (g *OpenAPIGenerator) addSchemasForStructsToDocument(d *openapi.Document, structs []*thrift_reflection.StructDescriptor) {
@@ -907,11 +869,15 @@ func (g *OpenAPIGenerator) addSchemasForStructsToDocument(d *openapi.Document, s
 		for _, f := range s.GetFields() {
 			fieldType := f.GetType()
 			if fieldType == nil {
-				logs.Errorf("Warning: field type is nil for field: %s\n", f.GetName())
+				g.diag.WarnfAt(g.src.FieldOf(s, f, ""), "field type is nil for field: %s", f.GetName())
 				continue
 			}
 			if fieldType.IsStruct() {
-				structDesc, _ := fieldType.GetStructDescriptor()
+				structDesc, err := fieldType.GetStructDescriptor()
+				if err != nil {
+					g.diag.ErrorfAt(g.src.FieldOf(s, f, ""), "Error getting struct descriptor: %s", err)
+					continue
+				}
 				sls = append(sls, structDesc)
 			}
 		}
@@ -919,86 +885,95 @@ func (g *OpenAPIGenerator) addSchemasForStructsToDocument(d *openapi.Document, s
 			g.addSchemasForStructsToDocument(d, sls)
 		}
 
-		schemaName := s.GetName()
-
-		// Only generate this if we need it and haven't already generated it.
-		if !common.Contains(g.requiredSchemas, schemaName) ||
-			common.Contains(g.generatedSchemas, schemaName) {
-			continue
-		}
-
-		// Get the description from the comments.
-		messageDescription := g.filterCommentString(s.Comments)
-
-		// Build an array holding the fields of the message.
-		definitionProperties := &openapi.Properties{
-			AdditionalProperties: make([]*openapi.NamedSchemaOrReference, 0),
-		}
+		// Structs split by direction have a schema per direction.
+		for _, direction := range []string{"", thriftgen.DirectionInput, thriftgen.DirectionOutput} {
+			schemaName := s.GetName() + direction
 
-		for _, field := range s.Fields {
-			// Get the field description from the comments.
-			description := g.filterCommentString(field.Comments)
-			fieldSchema := g.schemaOrReferenceForField(field.Type)
-			if fieldSchema == nil {
+			// Only generate this if we need it and haven't already generated it.
+			if !common.Contains(g.requiredSchemas, schemaName) ||
+				common.Contains(g.generatedSchemas, schemaName) {
 				continue
 			}
+			g.direction = direction
+			g.addSchemaForStruct(d, s, schemaName)
+			g.direction = ""
+		}
+	}
+}
 
-			if fieldSchema.IsSetSchema() {
-				fieldSchema.Schema.Description = description
-				newFieldSchema := &openapi.Schema{}
-				err := utils.ParseFieldOption(field, consts.OpenapiProperty, &newFieldSchema)
-				if err != nil {
-					logs.Errorf("Error parsing field option: %s", err)
-				}
-				err = common.MergeStructs(fieldSchema.Schema, newFieldSchema)
-				if err != nil {
-					logs.Errorf("Error merging field option: %s", err)
-				}
-			}
+// addSchemaForStruct adds the schema of s named schemaName, built for the
+// current direction.
+func (g *OpenAPIGenerator) addSchemaForStruct(d *openapi.Document, s *thrift_reflection.StructDescriptor, schemaName string) {
+	// Get the description from the comments.
+	messageDescription := g.filterCommentString(s.Comments)
 
-			extName := field.GetName()
-			options := []string{consts.ApiHeader, consts.ApiBody, consts.ApiForm, consts.ApiRawBody}
-			for _, option := range options {
-				if field.Annotations[option] != nil && field.Annotations[option][0] != "" {
-					extName = field.Annotations[option][0]
-				}
-			}
+	// Build an array holding the fields of the message.
+	definitionProperties := &openapi.Properties{
+		AdditionalProperties: make([]*openapi.NamedSchemaOrReference, 0),
+	}
 
-			definitionProperties.AdditionalProperties = append(
-				definitionProperties.AdditionalProperties,
-				&openapi.NamedSchemaOrReference{
-					Name:  extName,
-					Value: fieldSchema,
-				},
-			)
+	for _, field := range s.Fields {
+		if thriftgen.HiddenField(field, g.schemaDirection()) {
+			continue
 		}
-
-		schema := &openapi.Schema{
-			Type:        consts.SchemaObjectType,
-			Description: messageDescription,
-			Properties:  definitionProperties,
+		// Get the field description from the comments.
+		description := thriftgen.FieldDescription(field)
+		fieldSchema := g.schemaOrReferenceForField(field.Type)
+		if fieldSchema == nil {
+			continue
 		}
 
-		var extSchema *openapi.Schema
-		err := utils.ParseStructOption(s, consts.OpenapiSchema, &extSchema)
-		if err != nil {
-			logs.Errorf("Error parsing struct option: %s", err)
+		if thriftgen.IsDeprecated(field.Annotations, field.Comments) {
+			fieldSchema = thriftgen.DeprecateSchema(fieldSchema)
 		}
-		if extSchema != nil {
-			err = common.MergeStructs(schema, extSchema)
+		if example := thriftgen.FieldExample(field); example != nil {
+			fieldSchema = thriftgen.ExampleSchema(fieldSchema, example)
+		}
+		fieldSchema = thriftgen.BehaviorSchema(field, fieldSchema)
+		if fieldSchema.IsSetSchema() {
+			fieldSchema.Schema.Description = description
+			err := utils.MergeFieldOption(field, consts.OpenapiProperty, fieldSchema.Schema)
 			if err != nil {
-				logs.Errorf("Error merging struct option: %s", err)
+				g.diag.ErrorfAt(g.src.FieldOf(s, field, consts.OpenapiProperty), "Error parsing field option: %s", err)
+			}
+		}
+
+		extName := field.GetName()
+		options := []string{consts.ApiHeader, consts.ApiBody, consts.ApiForm, consts.ApiRawBody}
+		for _, option := range options {
+			if field.Annotations[option] != nil && field.Annotations[option][0] != "" {
+				extName = field.Annotations[option][0]
 			}
 		}
 
-		// Add the schema to the components.schema list.
-		g.addSchemaToDocument(d, &openapi.NamedSchemaOrReference{
-			Name: schemaName,
-			Value: &openapi.SchemaOrReference{
-				Schema: schema,
+		definitionProperties.AdditionalProperties = append(
+			definitionProperties.AdditionalProperties,
+			&openapi.NamedSchemaOrReference{
+				Name:  extName,
+				Value: fieldSchema,
 			},
-		})
+		)
 	}
+
+	schema := &openapi.Schema{
+		Type:        consts.SchemaObjectType,
+		Description: messageDescription,
+		Properties:  definitionProperties,
+		Deprecated:  thriftgen.IsDeprecated(s.Annotations, s.Comments),
+	}
+
+	err := utils.MergeStructOption(s, consts.OpenapiSchema, schema)
+	if err != nil {
+		g.diag.ErrorfAt(g.src.StructOf(s, consts.OpenapiSchema), "Error parsing struct option: %s", err)
+	}
+
+	// Add the schema to the components.schema list.
+	g.addSchemaToDocument(d, &openapi.NamedSchemaOrReference{
+		Name: schemaName,
+		Value: &openapi.SchemaOrReference{
+			Schema: schema,
+		},
+	})
 }
 
 // addSchemaToDocument adds the schema to the document if required
@@ -1043,7 +1018,7 @@ func (g *OpenAPIGenerator) addOperationToDocument(d *openapi.Document, op *opena
 }
 
 func (g *OpenAPIGenerator) schemaReferenceForMessage(message *thrift_reflection.StructDescriptor) string {
-	schemaName := message.GetName()
+	schemaName := thriftgen.SchemaName(message, g.schemaDirection())
 	if !common.Contains(g.requiredSchemas, schemaName) {
 		g.requiredSchemas = append(g.requiredSchemas, schemaName)
 		g.requiredTypeDesc = append(g.requiredTypeDesc, message)
@@ -1058,7 +1033,7 @@ func (g *OpenAPIGenerator) schemaOrReferenceForField(fieldType *thrift_reflectio
 	case fieldType.IsStruct():
 		structDesc, err := fieldType.GetStructDescriptor()
 		if err != nil {
-			logs.Errorf("Error getting struct descriptor: %s", err)
+			g.diag.ErrorfAt(g.src.TypeOf(fieldType), "Error getting struct descriptor: %s", err)
 			return nil
 		}
 		ref := g.schemaReferenceForMessage(structDesc)
@@ -1091,7 +1066,7 @@ func (g *OpenAPIGenerator) schemaOrReferenceForField(fieldType *thrift_reflectio
 	case fieldType.IsTypedef():
 		typedefDesc, err := fieldType.GetTypedefDescriptor()
 		if err != nil {
-			logs.Errorf("Error getting typedef descriptor: %s", err)
+			g.diag.ErrorfAt(g.src.TypeOf(fieldType), "Error getting typedef descriptor: %s", err)
 			return nil
 		}
 		kindSchema = g.schemaOrReferenceForField(typedefDesc.Type)
@@ -1099,7 +1074,7 @@ func (g *OpenAPIGenerator) schemaOrReferenceForField(fieldType *thrift_reflectio
 	case fieldType.IsEnum():
 		enumDesc, err := fieldType.GetEnumDescriptor()
 		if err != nil {
-			logs.Errorf("Error getting enum descriptor: %s", err)
+			g.diag.ErrorfAt(g.src.TypeOf(fieldType), "Error getting enum descriptor: %s", err)
 			return nil
 		}
 		kindSchema = &openapi.SchemaOrReference{Schema: &openapi.Schema{}}
@@ -1113,7 +1088,7 @@ func (g *OpenAPIGenerator) schemaOrReferenceForField(fieldType *thrift_reflectio
 	case fieldType.IsUnion():
 		unionDesc, err := fieldType.GetUnionDescriptor()
 		if err != nil {
-			logs.Errorf("Error getting union descriptor: %s", err)
+			g.diag.ErrorfAt(g.src.TypeOf(fieldType), "Error getting union descriptor: %s", err)
 			return nil
 		}
 		kindSchema = &openapi.SchemaOrReference{Schema: &openapi.Schema{}}
@@ -1124,7 +1099,7 @@ func (g *OpenAPIGenerator) schemaOrReferenceForField(fieldType *thrift_reflectio
 		}
 
 	case fieldType.IsException():
-		logs.Errorf("Error: exception type not supported: %s for field", fieldType.GetName())
+		g.diag.ErrorfAt(g.src.TypeOf(fieldType), "Error: exception type not supported: %s for field", fieldType.GetName())
 
 	default:
 		kindSchema = &openapi.SchemaOrReference{Schema: &openapi.Schema{}}
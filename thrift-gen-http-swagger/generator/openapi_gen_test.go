@@ -0,0 +1,84 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudwego/thriftgo/parser"
+	"github.com/cloudwego/thriftgo/semantic"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"github.com/hertz-contrib/swagger-generate/thrift-gen-http-swagger/args"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestBuildDocument(t *testing.T) {
+	tests := []struct {
+		name string
+		idl  string
+	}{
+		// The `openapi.schema` overrides of MergeReq name fields of the body
+		// and of the form: each request body only gets the properties of its
+		// own location, and `$replace` sets the required fields.
+		{name: "schema merge", idl: "schema_merge.thrift"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join("testdata", tt.idl)
+			ast, err := parser.ParseFile(path, nil, true)
+			if err != nil {
+				t.Fatalf("parse %s: %s", path, err)
+			}
+			if _, err := semantic.NewChecker(semantic.Options{FixWarnings: true}).CheckAll(ast); err != nil {
+				t.Fatalf("check %s: %s", path, err)
+			}
+			if err := semantic.ResolveSymbols(ast); err != nil {
+				t.Fatalf("resolve %s: %s", path, err)
+			}
+
+			diag := diagnostics.NewCollector()
+			files, err := NewOpenAPIGenerator(ast, diag).BuildDocument(&args.Arguments{})
+			if err != nil {
+				t.Fatalf("BuildDocument() error = %s", err)
+			}
+			if diag.HasErrors() {
+				t.Fatalf("BuildDocument() diagnostics = %s", diag.Err())
+			}
+			if len(files) != 1 {
+				t.Fatalf("BuildDocument() generated %d files, want 1", len(files))
+			}
+
+			golden := path[:len(path)-len(filepath.Ext(path))] + ".yaml"
+			if *update {
+				if err := os.WriteFile(golden, []byte(files[0].Content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := files[0].Content; got != string(want) {
+				t.Errorf("BuildDocument() = \n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+namespace go example
+
+// Merged into the body and the form schema of the request: the overrides
+// only apply to the fields of each location.
+struct MergeReq {
+    1: string Name (api.body = "name")
+    2: string Nick (api.body = "nick")
+    3: string Token (api.form = "token")
+    4: string Trace (api.header = "X-Trace")
+}(
+    openapi.schema = '{
+        required: {$replace: ["nick", "token"]},
+        properties: {
+            additional_properties: [
+                {name: "name", value: {schema: {title: "name of the user"}}},
+                {name: "token", value: {schema: {description: "token of the session"}}}
+            ]
+        }
+    }'
+)
+
+struct MergeResp {
+    1: string Message (api.body = "message")
+}
+
+service MergeService {
+    MergeResp Merge(1: MergeReq req) (api.post = "/merge")
+}
@@ -23,9 +23,16 @@ import (
 )
 
 type Arguments struct {
-	OutputDir string
-	HertzAddr string
-	KitexAddr string
+	OutputDir                string
+	HertzAddr                string
+	KitexAddr                string
+	DefaultResponse          string
+	StreamContentType        string
+	DisableStreamingTryItOut bool
+	SplitIOSchemas           bool
+	PathStyle                string
+	MetainfoStyle            string
+	Strict                   bool
 }
 
 func (a *Arguments) Unpack(args []string) error {
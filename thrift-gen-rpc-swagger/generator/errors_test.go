@@ -0,0 +1,70 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cloudwego/thriftgo/parser"
+	"github.com/cloudwego/thriftgo/semantic"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"github.com/hertz-contrib/swagger-generate/thrift-gen-rpc-swagger/args"
+)
+
+func TestExceptionStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		idl  string
+		want map[string]int
+	}{
+		{name: "declared exception", idl: "exceptions.thrift", want: map[string]int{"Svc/Get": 400}},
+		{
+			name: "first error response",
+			idl:  "exception_responses.thrift",
+			want: map[string]int{"Svc/Get": 404},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join("testdata", tt.idl)
+			ast, err := parser.ParseFile(path, nil, true)
+			if err != nil {
+				t.Fatalf("parse %s: %s", path, err)
+			}
+			if _, err := semantic.NewChecker(semantic.Options{FixWarnings: true}).CheckAll(ast); err != nil {
+				t.Fatalf("check %s: %s", path, err)
+			}
+			if err := semantic.ResolveSymbols(ast); err != nil {
+				t.Fatalf("resolve %s: %s", path, err)
+			}
+
+			diag := diagnostics.NewCollector()
+			g := NewOpenAPIGenerator(ast, diag)
+			if _, err := g.BuildDocument(&args.Arguments{}); err != nil {
+				t.Fatalf("BuildDocument() error = %s", err)
+			}
+			if diag.HasErrors() {
+				t.Fatalf("BuildDocument() diagnostics = %s", diag.Err())
+			}
+			if got := g.ExceptionStatuses(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExceptionStatuses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
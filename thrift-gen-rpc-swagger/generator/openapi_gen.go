@@ -34,41 +34,58 @@
 package generator
 
 import (
+	"fmt"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 
-	"github.com/cloudwego/hertz/cmd/hz/util/logs"
 	"github.com/cloudwego/thriftgo/parser"
 	"github.com/cloudwego/thriftgo/plugin"
 	"github.com/cloudwego/thriftgo/thrift_reflection"
 	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"github.com/hertz-contrib/swagger-generate/common/thriftgen"
 	common "github.com/hertz-contrib/swagger-generate/common/utils"
+	"github.com/hertz-contrib/swagger-generate/common/validator"
 	openapi "github.com/hertz-contrib/swagger-generate/idl/thrift"
 	"github.com/hertz-contrib/swagger-generate/thrift-gen-rpc-swagger/args"
 	"github.com/hertz-contrib/swagger-generate/thrift-gen-rpc-swagger/utils"
 )
 
 type OpenAPIGenerator struct {
-	fileDesc         *thrift_reflection.FileDescriptor
-	ast              *parser.Thrift
-	generatedSchemas []string
-	requiredSchemas  []string
-	requiredTypeDesc []*thrift_reflection.StructDescriptor
+	fileDesc                 *thrift_reflection.FileDescriptor
+	ast                      *parser.Thrift
+	diag                     *diagnostics.Collector
+	src                      *diagnostics.ThriftSource
+	generatedSchemas         []string
+	requiredSchemas          []string
+	requiredTypeDesc         []*thrift_reflection.StructDescriptor
+	defaultResponse          *thrift_reflection.StructDescriptor // Struct added as the `default` response of every operation.
+	streamContentType        string
+	disableStreamingTryItOut bool
+	splitIOSchemas           bool
+	pathStyle                string
+	metainfoStyle            string
+	direction                string         // Direction of the schemas being built, for `SplitIOSchemas`.
+	exceptionStatuses        map[string]int // HTTP status of the exception declared by each method.
 }
 
-// NewOpenAPIGenerator creates a new generator for a protoc plugin invocation.
-func NewOpenAPIGenerator(ast *parser.Thrift) *OpenAPIGenerator {
+// NewOpenAPIGenerator creates a new generator for a thriftgo plugin invocation.
+// Problems found while generating are reported to diag.
+func NewOpenAPIGenerator(ast *parser.Thrift, diag *diagnostics.Collector) *OpenAPIGenerator {
 	_, fileDesc := thrift_reflection.RegisterAST(ast)
 	return &OpenAPIGenerator{
-		fileDesc:         fileDesc,
-		ast:              ast,
-		generatedSchemas: make([]string, 0),
+		fileDesc:          fileDesc,
+		ast:               ast,
+		diag:              diag,
+		src:               diagnostics.NewThriftSource(),
+		generatedSchemas:  make([]string, 0),
+		exceptionStatuses: map[string]int{},
 	}
 }
 
-func (g *OpenAPIGenerator) BuildDocument(arguments *args.Arguments) []*plugin.Generated {
+func (g *OpenAPIGenerator) BuildDocument(arguments *args.Arguments) ([]*plugin.Generated, error) {
 	d := &openapi.Document{}
 
 	version := consts.OpenAPIVersion
@@ -85,20 +102,19 @@ func (g *OpenAPIGenerator) BuildDocument(arguments *args.Arguments) []*plugin.Ge
 		},
 	}
 
-	var extDocument *openapi.Document
-	err := g.getDocumentOption(&extDocument)
+	err := g.mergeDocumentOption(d)
 	if err != nil {
-		logs.Errorf("Error getting document option: %s", err)
-		return nil
-	}
-	if extDocument != nil {
-		err := common.MergeStructs(d, extDocument)
-		if err != nil {
-			logs.Errorf("Error merging document option: %s", err)
-			return nil
-		}
+		kind, name := g.getDocumentAnnotationInWhichServiceOrStruct()
+		g.diag.ErrorfAt(g.src.Document(g.ast.Filename, kind, name), "Error getting document option: %s", err)
 	}
 
+	g.defaultResponse = thriftgen.LookupDefaultResponse(g.fileDesc, arguments.DefaultResponse, g.diag)
+	g.streamContentType = thriftgen.LookupStreamContentType(arguments.StreamContentType, g.diag)
+	g.disableStreamingTryItOut = arguments.DisableStreamingTryItOut
+	g.splitIOSchemas = arguments.SplitIOSchemas
+	g.pathStyle = g.lookupPathStyle(arguments.PathStyle)
+	g.metainfoStyle = g.lookupMetainfoStyle(arguments.MetainfoStyle)
+
 	g.addPathsToDocument(d, g.fileDesc.GetServices())
 
 	for len(g.requiredSchemas) > 0 {
@@ -186,24 +202,25 @@ func (g *OpenAPIGenerator) BuildDocument(arguments *args.Arguments) []*plugin.Ge
 
 	bytes, err := d.YAMLValue("Generated with " + consts.PluginNameThriftRpcSwagger + "\n" + consts.InfoURL + "blob/main/" + consts.PluginNameThriftRpcSwagger)
 	if err != nil {
-		logs.Errorf("Error converting to yaml: %s", err)
-		return nil
+		return nil, fmt.Errorf("failed to convert document to yaml: %s", err)
 	}
 	outputDir := arguments.OutputDir
 	if outputDir == "" {
 		outputDir = consts.DefaultOutputDir
 	}
 	filePath := filepath.Join(outputDir, consts.DefaultOutputYamlFile)
+	validator.Validate(filePath, bytes, g.diag)
+
 	var ret []*plugin.Generated
 	ret = append(ret, &plugin.Generated{
 		Content: string(bytes),
 		Name:    &filePath,
 	})
 
-	return ret
+	return ret, nil
 }
 
-func (g *OpenAPIGenerator) getDocumentOption(obj interface{}) error {
+func (g *OpenAPIGenerator) mergeDocumentOption(d *openapi.Document) error {
 	serviceOrStruct, name := g.getDocumentAnnotationInWhichServiceOrStruct()
 
 	if serviceOrStruct == "" || name == "" {
@@ -213,7 +230,7 @@ func (g *OpenAPIGenerator) getDocumentOption(obj interface{}) error {
 	if serviceOrStruct == consts.DocumentOptionServiceType {
 		serviceDesc := g.fileDesc.GetServiceDescriptor(name)
 		if serviceDesc != nil {
-			err := utils.ParseServiceOption(serviceDesc, consts.OpenapiDocument, obj)
+			err := utils.MergeServiceOption(serviceDesc, consts.OpenapiDocument, d)
 			if err != nil {
 				return err
 			}
@@ -221,7 +238,7 @@ func (g *OpenAPIGenerator) getDocumentOption(obj interface{}) error {
 	} else if serviceOrStruct == consts.DocumentOptionStructType {
 		structDesc := g.fileDesc.GetStructDescriptor(name)
 		if structDesc != nil {
-			err := utils.ParseStructOption(structDesc, consts.OpenapiDocument, obj)
+			err := utils.MergeStructOption(structDesc, consts.OpenapiDocument, d)
 			if err != nil {
 				return err
 			}
@@ -240,16 +257,16 @@ func (g *OpenAPIGenerator) addPathsToDocument(d *openapi.Document, services []*t
 
 				if len(m.Args) > 0 {
 					if len(m.Args) > 1 {
-						logs.Warnf("function '%s' has more than one argument, but only the first can be used in plugin now", m.GetName())
+						g.diag.WarnfAt(g.src.MethodOf(s, m, ""), "function '%s' has more than one argument, but only the first can be used in plugin now", m.GetName())
 					}
 					// TODO: support more argument types
 					if m.Args[0].GetType().IsStruct() {
 						inputDesc, err = m.Args[0].GetType().GetStructDescriptor()
 						if err != nil {
-							logs.Errorf("Error getting arguments descriptor: %s", err)
+							g.diag.ErrorfAt(g.src.MethodOf(s, m, ""), "Error getting arguments descriptor: %s", err)
 						}
 					} else {
-						logs.Errorf("now only support struct type for input, but got %s", m.Args[0].GetType().GetName())
+						g.diag.ErrorfAt(g.src.MethodOf(s, m, ""), "now only support struct type for input, but got %s", m.Args[0].GetType().GetName())
 					}
 				}
 
@@ -257,16 +274,16 @@ func (g *OpenAPIGenerator) addPathsToDocument(d *openapi.Document, services []*t
 				if m.Response.IsStruct() {
 					outputDesc, err = m.Response.GetStructDescriptor()
 					if err != nil {
-						logs.Errorf("Error getting response descriptor: %s", err)
+						g.diag.ErrorfAt(g.src.MethodOf(s, m, ""), "Error getting response descriptor: %s", err)
 					}
 				} else if m.Response.Name != "void" {
-					logs.Errorf("now only support struct type for output, but got %s", m.Response.Name)
+					g.diag.ErrorfAt(g.src.MethodOf(s, m, ""), "now only support struct type for output, but got %s", m.Response.Name)
 				}
 
 				if len(m.ThrowExceptions) > 0 {
 					throwDesc, err = m.ThrowExceptions[0].GetType().GetExceptionDescriptor()
 					if err != nil {
-						logs.Errorf("Error getting exception descriptor: %s", err)
+						g.diag.ErrorfAt(g.src.MethodOf(s, m, ""), "Error getting exception descriptor: %s", err)
 					}
 				}
 				var host string
@@ -279,21 +296,27 @@ func (g *OpenAPIGenerator) addPathsToDocument(d *openapi.Document, services []*t
 
 				annotationsCount++
 				operationID := s.GetName() + "_" + m.GetName()
-				path := "/" + m.GetName()
+				path := common.RPCPath(g.pathStyle, s.GetName(), m.GetName())
 				comment := g.filterCommentString(m.Comments)
 
 				op, path2 := g.buildOperation(d, comment, operationID, s.GetName(), path, host, inputDesc, outputDesc, throwDesc)
 
-				newOp := &openapi.Operation{}
-				err = utils.ParseMethodOption(m, consts.OpenapiOperation, &newOp)
+				g.addDefaultResponse(d, m, op)
+				thriftgen.ApplyStreaming(m, op, g.streamContentType, g.disableStreamingTryItOut)
+				thriftgen.ApplyDeprecation(s, m, op)
+
+				err = utils.MergeMethodOption(m, consts.OpenapiOperation, op)
 				if err != nil {
-					logs.Errorf("Error parsing method option: %s", err)
+					g.diag.ErrorfAt(g.src.MethodOf(s, m, consts.OpenapiOperation), "Error parsing method option: %s", err)
 				}
-				err = common.MergeStructs(op, newOp)
-				if err != nil {
-					logs.Errorf("Error merging method option: %s", err)
+
+				if throwDesc != nil {
+					g.recordExceptionStatus(s, m, op)
 				}
 
+				if g.hasOperation(d, path2) {
+					g.diag.WarnfAt(g.src.MethodOf(s, m, ""), "path %s of method %s is already used by another service, use the %q path style to tell them apart", path2, operationID, consts.PathStyleService)
+				}
 				g.addOperationToDocument(d, op, path2)
 			}
 			if annotationsCount > 0 {
@@ -315,24 +338,12 @@ func (g *OpenAPIGenerator) buildOperation(
 	outputDesc *thrift_reflection.StructDescriptor,
 	throwDesc *thrift_reflection.StructDescriptor,
 ) (*openapi.Operation, string) {
-	// Parameters array to hold all parameter objects
-	var parameters []*openapi.ParameterOrReference
+	// Requests and responses use the schemas of their direction.
+	g.direction = thriftgen.DirectionInput
+	defer func() { g.direction = "" }()
 
-	fieldSchema := &openapi.SchemaOrReference{
-		Schema: &openapi.Schema{
-			Type: consts.SchemaObjectType,
-		},
-	}
-	parameter := &openapi.Parameter{
-		Name:        consts.ParameterNameTTHeader,
-		In:          consts.ParameterInQuery,
-		Description: consts.ParameterDescription,
-		Required:    false,
-		Schema:      fieldSchema,
-	}
-	parameters = append(parameters, &openapi.ParameterOrReference{
-		Parameter: parameter,
-	})
+	// Parameters array to hold all parameter objects
+	parameters := g.metainfoParameters()
 
 	var RequestBody *openapi.RequestBodyOrReference
 
@@ -342,11 +353,11 @@ func (g *OpenAPIGenerator) buildOperation(
 		var additionalProperties []*openapi.NamedMediaType
 		if bodySchema != nil && bodySchema.Properties != nil && len(bodySchema.Properties.AdditionalProperties) > 0 {
 			refSchema := &openapi.NamedSchemaOrReference{
-				Name:  inputDesc.GetName(),
+				Name:  thriftgen.SchemaName(inputDesc, g.schemaDirection()),
 				Value: &openapi.SchemaOrReference{Schema: bodySchema},
 			}
 
-			ref := consts.ComponentSchemaPrefix + inputDesc.GetName()
+			ref := consts.ComponentSchemaPrefix + thriftgen.SchemaName(inputDesc, g.schemaDirection())
 
 			g.addSchemaToDocument(d, refSchema)
 
@@ -372,6 +383,7 @@ func (g *OpenAPIGenerator) buildOperation(
 		}
 	}
 
+	g.direction = thriftgen.DirectionOutput
 	var (
 		desc                    string
 		contentOrEmpty          *openapi.MediaTypes
@@ -445,13 +457,18 @@ func (g *OpenAPIGenerator) buildOperation(
 	re := regexp.MustCompile(`:(\w+)`)
 	path = re.ReplaceAllString(path, `{$1}`)
 
+	// The first sentence of the method comment is the summary.
+	summary, description := common.SplitSummary(description)
 	op := &openapi.Operation{
 		Tags:        []string{tagName},
+		Summary:     summary,
 		Description: description,
 		OperationID: operationID,
 		Parameters:  parameters,
 		Responses:   responses,
 		RequestBody: RequestBody,
+
+		SpecificationExtension: g.metainfoExtension(),
 	}
 
 	if host != "" {
@@ -490,10 +507,10 @@ func (g *OpenAPIGenerator) getResponseForStruct(d *openapi.Document, desc *thrif
 
 	if bodySchema != nil && bodySchema.Properties != nil && len(bodySchema.Properties.AdditionalProperties) > 0 {
 		refSchema := &openapi.NamedSchemaOrReference{
-			Name:  desc.GetName(),
+			Name:  thriftgen.SchemaName(desc, g.schemaDirection()),
 			Value: &openapi.SchemaOrReference{Schema: bodySchema},
 		}
-		ref := consts.ComponentSchemaPrefix + desc.GetName()
+		ref := consts.ComponentSchemaPrefix + thriftgen.SchemaName(desc, g.schemaDirection())
 		g.addSchemaToDocument(d, refSchema)
 		additionalProperties = append(additionalProperties, &openapi.NamedMediaType{
 			Name: consts.ContentTypeJSON,
@@ -519,10 +536,10 @@ func (g *OpenAPIGenerator) getExceptionForStruct(d *openapi.Document, desc *thri
 
 	if bodySchema != nil && bodySchema.Properties != nil && len(bodySchema.Properties.AdditionalProperties) > 0 {
 		refSchema := &openapi.NamedSchemaOrReference{
-			Name:  desc.GetName(),
+			Name:  thriftgen.SchemaName(desc, g.schemaDirection()),
 			Value: &openapi.SchemaOrReference{Schema: bodySchema},
 		}
-		ref := consts.ComponentSchemaPrefix + desc.GetName()
+		ref := consts.ComponentSchemaPrefix + thriftgen.SchemaName(desc, g.schemaDirection())
 		g.addSchemaToDocument(d, refSchema)
 		additionalProperties = append(additionalProperties, &openapi.NamedMediaType{
 			Name: consts.ContentTypeJSON,
@@ -546,43 +563,31 @@ func (g *OpenAPIGenerator) getSchemaByOption(inputDesc *thrift_reflection.Struct
 		AdditionalProperties: make([]*openapi.NamedSchemaOrReference, 0),
 	}
 
-	var allRequired []string
-	var extSchema *openapi.Schema
-	err := utils.ParseStructOption(inputDesc, consts.OpenapiSchema, &extSchema)
-	if err != nil {
-		logs.Errorf("Error parsing struct option: %s", err)
-	}
-	if extSchema != nil {
-		if extSchema.Required != nil {
-			allRequired = extSchema.Required
-		}
-	}
-
-	var required []string
 	for _, field := range inputDesc.GetFields() {
-		extName := field.GetName()
-
-		if common.Contains(allRequired, extName) {
-			required = append(required, extName)
+		if thriftgen.HiddenField(field, g.schemaDirection()) {
+			continue
 		}
+		extName := field.GetName()
 
 		// Get the field description from the comments.
-		description := g.filterCommentString(field.Comments)
+		description := thriftgen.FieldDescription(field)
 		fieldSchema := g.schemaOrReferenceForField(field.Type)
 		if fieldSchema == nil {
 			continue
 		}
 
+		if thriftgen.IsDeprecated(field.Annotations, field.Comments) {
+			fieldSchema = thriftgen.DeprecateSchema(fieldSchema)
+		}
+		if example := thriftgen.FieldExample(field); example != nil {
+			fieldSchema = thriftgen.ExampleSchema(fieldSchema, example)
+		}
+		fieldSchema = thriftgen.BehaviorSchema(field, fieldSchema)
 		if fieldSchema.IsSetSchema() {
 			fieldSchema.Schema.Description = description
-			newFieldSchema := &openapi.Schema{}
-			err := utils.ParseFieldOption(field, consts.OpenapiProperty, &newFieldSchema)
-			if err != nil {
-				logs.Errorf("Error parsing field option: %s", err)
-			}
-			err = common.MergeStructs(fieldSchema.Schema, newFieldSchema)
+			err := utils.MergeFieldOption(field, consts.OpenapiProperty, fieldSchema.Schema)
 			if err != nil {
-				logs.Errorf("Error merging field option: %s", err)
+				g.diag.ErrorfAt(g.src.FieldOf(inputDesc, field, consts.OpenapiProperty), "Error parsing field option: %s", err)
 			}
 		}
 
@@ -598,59 +603,29 @@ func (g *OpenAPIGenerator) getSchemaByOption(inputDesc *thrift_reflection.Struct
 	schema := &openapi.Schema{
 		Type:       consts.SchemaObjectType,
 		Properties: definitionProperties,
+		Deprecated: thriftgen.IsDeprecated(inputDesc.Annotations, inputDesc.Comments),
 	}
 
-	if extSchema != nil {
-		err := common.MergeStructs(schema, extSchema)
-		if err != nil {
-			logs.Errorf("Error merging struct option: %s", err)
-		}
+	names := thriftgen.PropertyNames(schema)
+	if err := utils.MergeStructOption(inputDesc, consts.OpenapiSchema, schema); err != nil {
+		g.diag.ErrorfAt(g.src.StructOf(inputDesc, consts.OpenapiSchema), "Error parsing struct option: %s", err)
 	}
-
-	schema.Required = required
+	thriftgen.KeepProperties(schema, names)
 	return schema
 }
 
-// filterCommentString removes linter rules from comments.
+// filterCommentString removes comment markers and directives from comments.
 func (g *OpenAPIGenerator) filterCommentString(str string) string {
-	var comments []string
-	matches := regexp.MustCompile(consts.CommentPatternRegexp).FindAllStringSubmatch(str, -1)
-
-	for _, match := range matches {
-		if match[1] != "" {
-			// Handle one-line comments
-			comments = append(comments, strings.TrimSpace(match[1]))
-		} else if match[2] != "" {
-			// Handle multiline comments
-			multiLineComment := match[2]
-			lines := strings.Split(multiLineComment, "\n")
-
-			// Find the minimum indentation level (excluding empty lines)
-			minIndent := -1
-			for _, line := range lines {
-				trimmedLine := strings.TrimSpace(line)
-				if trimmedLine != "" {
-					lineIndent := len(line) - len(strings.TrimLeft(line, " "))
-					if minIndent == -1 || lineIndent < minIndent {
-						minIndent = lineIndent
-					}
-				}
-			}
-
-			// Remove the minimum indentation and any leading '*' from each line
-			for i, line := range lines {
-				if minIndent > 0 && len(line) >= minIndent {
-					line = line[minIndent:]
-				}
-				lines[i] = strings.TrimPrefix(line, "*")
-			}
+	return thriftgen.FilterComment(str)
+}
 
-			// Remove leading and trailing empty lines from the comment block
-			comments = append(comments, strings.TrimSpace(strings.Join(lines, "\n")))
-		}
+// schemaDirection returns the direction of the schemas being built, or ""
+// unless the `SplitIOSchemas` argument splits them.
+func (g *OpenAPIGenerator) schemaDirection() string {
+	if !g.splitIOSchemas {
+		return ""
 	}
-
-	return strings.Join(comments, "\n")
+	return g.direction
 }
 
 func (g *OpenAPIGenerator) addSchemasForStructsToDocument(d *openapi.Document, structs []*thrift_reflection.StructDescriptor) {
@@ -659,11 +634,15 @@ func (g *OpenAPIGenerator) addSchemasForStructsToDocument(d *openapi.Document, s
 		for _, f := range s.GetFields() {
 			fieldType := f.GetType()
 			if fieldType == nil {
-				logs.Errorf("Warning: field type is nil for field: %s\n", f.GetName())
+				g.diag.WarnfAt(g.src.FieldOf(s, f, ""), "field type is nil for field: %s", f.GetName())
 				continue
 			}
 			if fieldType.IsStruct() {
-				structDesc, _ := fieldType.GetStructDescriptor()
+				structDesc, err := fieldType.GetStructDescriptor()
+				if err != nil {
+					g.diag.ErrorfAt(g.src.FieldOf(s, f, ""), "Error getting struct descriptor: %s", err)
+					continue
+				}
 				sls = append(sls, structDesc)
 			}
 		}
@@ -671,80 +650,89 @@ func (g *OpenAPIGenerator) addSchemasForStructsToDocument(d *openapi.Document, s
 			g.addSchemasForStructsToDocument(d, sls)
 		}
 
-		schemaName := s.GetName()
-
-		// Only generate this if we need it and haven't already generated it.
-		if !common.Contains(g.requiredSchemas, schemaName) ||
-			common.Contains(g.generatedSchemas, schemaName) {
-			continue
-		}
-
-		// Get the description from the comments.
-		messageDescription := g.filterCommentString(s.Comments)
+		// Structs split by direction have a schema per direction.
+		for _, direction := range []string{"", thriftgen.DirectionInput, thriftgen.DirectionOutput} {
+			schemaName := s.GetName() + direction
 
-		// Build an array holding the fields of the message.
-		definitionProperties := &openapi.Properties{
-			AdditionalProperties: make([]*openapi.NamedSchemaOrReference, 0),
-		}
-
-		for _, field := range s.Fields {
-			// Get the field description from the comments.
-			description := g.filterCommentString(field.Comments)
-			fieldSchema := g.schemaOrReferenceForField(field.Type)
-			if fieldSchema == nil {
+			// Only generate this if we need it and haven't already generated it.
+			if !common.Contains(g.requiredSchemas, schemaName) ||
+				common.Contains(g.generatedSchemas, schemaName) {
 				continue
 			}
+			g.direction = direction
+			g.addSchemaForStruct(d, s, schemaName)
+			g.direction = ""
+		}
+	}
+}
 
-			if fieldSchema.IsSetSchema() {
-				fieldSchema.Schema.Description = description
-				newFieldSchema := &openapi.Schema{}
-				err := utils.ParseFieldOption(field, consts.OpenapiProperty, &newFieldSchema)
-				if err != nil {
-					logs.Errorf("Error parsing field option: %s", err)
-				}
-				err = common.MergeStructs(fieldSchema.Schema, newFieldSchema)
-				if err != nil {
-					logs.Errorf("Error merging field option: %s", err)
-				}
-			}
+// addSchemaForStruct adds the schema of s named schemaName, built for the
+// current direction.
+func (g *OpenAPIGenerator) addSchemaForStruct(d *openapi.Document, s *thrift_reflection.StructDescriptor, schemaName string) {
+	// Get the description from the comments.
+	messageDescription := g.filterCommentString(s.Comments)
 
-			fName := field.GetName()
+	// Build an array holding the fields of the message.
+	definitionProperties := &openapi.Properties{
+		AdditionalProperties: make([]*openapi.NamedSchemaOrReference, 0),
+	}
 
-			definitionProperties.AdditionalProperties = append(
-				definitionProperties.AdditionalProperties,
-				&openapi.NamedSchemaOrReference{
-					Name:  fName,
-					Value: fieldSchema,
-				},
-			)
+	for _, field := range s.Fields {
+		if thriftgen.HiddenField(field, g.schemaDirection()) {
+			continue
 		}
-
-		schema := &openapi.Schema{
-			Type:        consts.SchemaObjectType,
-			Description: messageDescription,
-			Properties:  definitionProperties,
+		// Get the field description from the comments.
+		description := thriftgen.FieldDescription(field)
+		fieldSchema := g.schemaOrReferenceForField(field.Type)
+		if fieldSchema == nil {
+			continue
 		}
 
-		var extSchema *openapi.Schema
-		err := utils.ParseStructOption(s, consts.OpenapiSchema, &extSchema)
-		if err != nil {
-			logs.Errorf("Error parsing struct option: %s", err)
+		if thriftgen.IsDeprecated(field.Annotations, field.Comments) {
+			fieldSchema = thriftgen.DeprecateSchema(fieldSchema)
+		}
+		if example := thriftgen.FieldExample(field); example != nil {
+			fieldSchema = thriftgen.ExampleSchema(fieldSchema, example)
 		}
-		if extSchema != nil {
-			err = common.MergeStructs(schema, extSchema)
+		fieldSchema = thriftgen.BehaviorSchema(field, fieldSchema)
+		if fieldSchema.IsSetSchema() {
+			fieldSchema.Schema.Description = description
+			err := utils.MergeFieldOption(field, consts.OpenapiProperty, fieldSchema.Schema)
 			if err != nil {
-				logs.Errorf("Error merging struct option: %s", err)
+				g.diag.ErrorfAt(g.src.FieldOf(s, field, consts.OpenapiProperty), "Error parsing field option: %s", err)
 			}
 		}
 
-		// Add the schema to the components.schema list.
-		g.addSchemaToDocument(d, &openapi.NamedSchemaOrReference{
-			Name: schemaName,
-			Value: &openapi.SchemaOrReference{
-				Schema: schema,
+		fName := field.GetName()
+
+		definitionProperties.AdditionalProperties = append(
+			definitionProperties.AdditionalProperties,
+			&openapi.NamedSchemaOrReference{
+				Name:  fName,
+				Value: fieldSchema,
 			},
-		})
+		)
 	}
+
+	schema := &openapi.Schema{
+		Type:        consts.SchemaObjectType,
+		Description: messageDescription,
+		Properties:  definitionProperties,
+		Deprecated:  thriftgen.IsDeprecated(s.Annotations, s.Comments),
+	}
+
+	err := utils.MergeStructOption(s, consts.OpenapiSchema, schema)
+	if err != nil {
+		g.diag.ErrorfAt(g.src.StructOf(s, consts.OpenapiSchema), "Error parsing struct option: %s", err)
+	}
+
+	// Add the schema to the components.schema list.
+	g.addSchemaToDocument(d, &openapi.NamedSchemaOrReference{
+		Name: schemaName,
+		Value: &openapi.SchemaOrReference{
+			Schema: schema,
+		},
+	})
 }
 
 // addSchemaToDocument adds the schema to the document if required
@@ -756,6 +744,75 @@ func (g *OpenAPIGenerator) addSchemaToDocument(d *openapi.Document, schema *open
 	d.Components.Schemas.AdditionalProperties = append(d.Components.Schemas.AdditionalProperties, schema)
 }
 
+// lookupPathStyle checks the `PathStyle` argument, which defaults to
+// `/{Service}/{Method}` paths.
+func (g *OpenAPIGenerator) lookupPathStyle(style string) string {
+	if style == "" {
+		return consts.PathStyleService
+	}
+	if !common.IsPathStyle(style) {
+		g.diag.Errorf("PathStyle: unsupported path style %q, using %q", style, consts.PathStyleService)
+		return consts.PathStyleService
+	}
+	return style
+}
+
+// lookupMetainfoStyle checks the `MetainfoStyle` argument, which defaults to
+// metainfo carried in headers.
+func (g *OpenAPIGenerator) lookupMetainfoStyle(style string) string {
+	if style == "" {
+		return consts.MetainfoStyleHeader
+	}
+	if !common.IsMetainfoStyle(style) {
+		g.diag.Errorf("MetainfoStyle: unsupported metainfo style %q, using %q", style, consts.MetainfoStyleHeader)
+		return consts.MetainfoStyleHeader
+	}
+	return style
+}
+
+// metainfoParameters documents how the metainfo of a request is passed with
+// the "query" metainfo style: a `ttheader` object query parameter.
+func (g *OpenAPIGenerator) metainfoParameters() []*openapi.ParameterOrReference {
+	if g.metainfoStyle != consts.MetainfoStyleQuery {
+		return nil
+	}
+	return []*openapi.ParameterOrReference{{
+		Parameter: &openapi.Parameter{
+			Name:        consts.ParameterNameTTHeader,
+			In:          consts.ParameterInQuery,
+			Description: consts.ParameterDescription,
+			Required:    false,
+			Schema: &openapi.SchemaOrReference{
+				Schema: &openapi.Schema{
+					Type: consts.SchemaObjectType,
+				},
+			},
+		},
+	}}
+}
+
+// metainfoExtension documents the X-Metainfo-{Key} headers carrying metainfo
+// with the "header" metainfo style.
+func (g *OpenAPIGenerator) metainfoExtension() []*openapi.NamedAny {
+	if g.metainfoStyle == consts.MetainfoStyleQuery {
+		return nil
+	}
+	return []*openapi.NamedAny{{
+		Name:  consts.MetainfoExtensionName,
+		Value: &openapi.Any{Yaml: consts.MetainfoExtension},
+	}}
+}
+
+// hasOperation reports whether an operation is already documented at path.
+func (g *OpenAPIGenerator) hasOperation(d *openapi.Document, path string) bool {
+	for _, namedPathItem := range d.Paths.Path {
+		if namedPathItem.Name == path && namedPathItem.Value.Post != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (g *OpenAPIGenerator) addOperationToDocument(d *openapi.Document, op *openapi.Operation, path string) {
 	var selectedPathItem *openapi.NamedPathItem
 	for _, namedPathItem := range d.Paths.Path {
@@ -774,7 +831,7 @@ func (g *OpenAPIGenerator) addOperationToDocument(d *openapi.Document, op *opena
 }
 
 func (g *OpenAPIGenerator) schemaReferenceForMessage(message *thrift_reflection.StructDescriptor) string {
-	schemaName := message.GetName()
+	schemaName := thriftgen.SchemaName(message, g.schemaDirection())
 	if !common.Contains(g.requiredSchemas, schemaName) {
 		g.requiredSchemas = append(g.requiredSchemas, schemaName)
 		g.requiredTypeDesc = append(g.requiredTypeDesc, message)
@@ -789,7 +846,7 @@ func (g *OpenAPIGenerator) schemaOrReferenceForField(fieldType *thrift_reflectio
 	case fieldType.IsStruct():
 		structDesc, err := fieldType.GetStructDescriptor()
 		if err != nil {
-			logs.Errorf("Error getting struct descriptor: %s", err)
+			g.diag.ErrorfAt(g.src.TypeOf(fieldType), "Error getting struct descriptor: %s", err)
 			return nil
 		}
 		ref := g.schemaReferenceForMessage(structDesc)
@@ -821,7 +878,7 @@ func (g *OpenAPIGenerator) schemaOrReferenceForField(fieldType *thrift_reflectio
 	case fieldType.IsTypedef():
 		typedefDesc, err := fieldType.GetTypedefDescriptor()
 		if err != nil {
-			logs.Errorf("Error getting typedef descriptor: %s", err)
+			g.diag.ErrorfAt(g.src.TypeOf(fieldType), "Error getting typedef descriptor: %s", err)
 			return nil
 		}
 		kindSchema = g.schemaOrReferenceForField(typedefDesc.Type)
@@ -829,7 +886,7 @@ func (g *OpenAPIGenerator) schemaOrReferenceForField(fieldType *thrift_reflectio
 	case fieldType.IsEnum():
 		enumDesc, err := fieldType.GetEnumDescriptor()
 		if err != nil {
-			logs.Errorf("Error getting enum descriptor: %s", err)
+			g.diag.ErrorfAt(g.src.TypeOf(fieldType), "Error getting enum descriptor: %s", err)
 			return nil
 		}
 		kindSchema = &openapi.SchemaOrReference{Schema: &openapi.Schema{}}
@@ -843,7 +900,7 @@ func (g *OpenAPIGenerator) schemaOrReferenceForField(fieldType *thrift_reflectio
 	case fieldType.IsUnion():
 		unionDesc, err := fieldType.GetUnionDescriptor()
 		if err != nil {
-			logs.Errorf("Error getting union descriptor: %s", err)
+			g.diag.ErrorfAt(g.src.TypeOf(fieldType), "Error getting union descriptor: %s", err)
 			return nil
 		}
 		kindSchema = &openapi.SchemaOrReference{Schema: &openapi.Schema{}}
@@ -854,7 +911,7 @@ func (g *OpenAPIGenerator) schemaOrReferenceForField(fieldType *thrift_reflectio
 		}
 
 	case fieldType.IsException():
-		logs.Errorf("Error: exception type not supported: %s for field", fieldType.GetName())
+		g.diag.ErrorfAt(g.src.TypeOf(fieldType), "Error: exception type not supported: %s for field", fieldType.GetName())
 
 	default:
 		kindSchema = &openapi.SchemaOrReference{Schema: &openapi.Schema{}}
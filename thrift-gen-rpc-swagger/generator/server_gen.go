@@ -23,24 +23,55 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 
 	"github.com/cloudwego/thriftgo/parser"
 	"github.com/cloudwego/thriftgo/plugin"
 	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
 	"github.com/hertz-contrib/swagger-generate/common/tpl"
 	"github.com/hertz-contrib/swagger-generate/common/utils"
 	"github.com/hertz-contrib/swagger-generate/thrift-gen-rpc-swagger/args"
 )
 
 type ServerGenerator struct {
-	IdlPath   string
-	KitexAddr string
-	OutputDir string
+	IdlPath           string
+	HertzAddr         string
+	KitexAddr         string
+	OutputDir         string
+	PathStyle         string
+	MetainfoStyle     string
+	Services          []*ServiceInfo
+	IdlFiles          []*IdlFile
+	ExceptionStatuses []*ExceptionStatus
+
+	diag *diagnostics.Collector
+}
+
+// ExceptionStatus holds the HTTP status of the exception declared by a
+// method, keyed by `Service/Method`.
+type ExceptionStatus struct {
+	Method string
+	Status int
+}
+
+// IdlFile is an IDL file embedded in the generated server.
+type IdlFile struct {
+	Path    string // Path of the file, as resolved by the proxy from the includes.
+	Literal string // Content of the file as a Go string literal.
+}
+
+// ServiceInfo describes a service of the IDL, which the proxy calls through a
+// generic client of its own.
+type ServiceInfo struct {
+	Name    string
+	Methods []string
 }
 
-func NewServerGenerator(ast *parser.Thrift, args *args.Arguments) (*ServerGenerator, error) {
+func NewServerGenerator(ast *parser.Thrift, args *args.Arguments, diag *diagnostics.Collector) (*ServerGenerator, error) {
+	defaultHertzAddr := consts.DefaultHertzAddr
 	defaultKitexAddr := consts.DefaultKitexAddr
 	defaultOutputDir := consts.DefaultOutputDir
 
@@ -49,6 +80,11 @@ func NewServerGenerator(ast *parser.Thrift, args *args.Arguments) (*ServerGenera
 		return nil, errors.New("failed to get Thrift file path")
 	}
 
+	hertzAddr := args.HertzAddr
+	if hertzAddr == "" {
+		hertzAddr = defaultHertzAddr
+	}
+
 	kitexAddr := args.KitexAddr
 	if kitexAddr == "" {
 		kitexAddr = defaultKitexAddr
@@ -59,61 +95,158 @@ func NewServerGenerator(ast *parser.Thrift, args *args.Arguments) (*ServerGenera
 		outputDir = defaultOutputDir
 	}
 
+	if err := validateAddress(hertzAddr); err != nil {
+		return nil, err
+	}
 	if err := validateAddress(kitexAddr); err != nil {
 		return nil, err
 	}
 
+	pathStyle := args.PathStyle
+	if !utils.IsPathStyle(pathStyle) {
+		pathStyle = consts.PathStyleService
+	}
+	metainfoStyle := args.MetainfoStyle
+	if !utils.IsMetainfoStyle(metainfoStyle) {
+		metainfoStyle = consts.MetainfoStyleHeader
+	}
+
+	var services []*ServiceInfo
+	for _, s := range ast.Services {
+		service := &ServiceInfo{Name: s.Name}
+		for _, f := range s.Functions {
+			service.Methods = append(service.Methods, f.Name)
+		}
+		services = append(services, service)
+	}
+
+	idlFiles, err := collectIdlFiles(ast, filepath.Clean(idlPath), map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
 	return &ServerGenerator{
-		IdlPath:   idlPath,
-		KitexAddr: kitexAddr,
-		OutputDir: outputDir,
+		IdlPath:       idlPath,
+		HertzAddr:     hertzAddr,
+		KitexAddr:     kitexAddr,
+		OutputDir:     outputDir,
+		PathStyle:     pathStyle,
+		MetainfoStyle: metainfoStyle,
+		Services:      services,
+		IdlFiles:      idlFiles,
+		diag:          diag,
 	}, nil
 }
 
-func (g *ServerGenerator) Generate() ([]*plugin.Generated, error) {
-	filePath := filepath.Join(g.OutputDir, consts.DefaultOutputSwaggerFile)
+// collectIdlFiles reads the Thrift file of ast and, recursively, its
+// includes. Each include is keyed by its path relative to the directory of
+// the including file's key, as the proxy resolves it when parsing the
+// embedded IDL.
+func collectIdlFiles(ast *parser.Thrift, key string, seen map[string]bool) ([]*IdlFile, error) {
+	if seen[key] {
+		return nil, nil
+	}
+	seen[key] = true
 
-	if utils.FileExists(filePath) {
-		updatedContent, err := updateVariables(filePath, g.KitexAddr, g.IdlPath)
+	content, err := ioutil.ReadFile(ast.Filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Thrift file: %w", err)
+	}
+	files := []*IdlFile{{Path: key, Literal: utils.RawString(string(content))}}
+	for _, include := range ast.Includes {
+		if include.Reference == nil {
+			continue
+		}
+		includeFiles, err := collectIdlFiles(include.Reference, filepath.Join(filepath.Dir(key), include.Path), seen)
 		if err != nil {
 			return nil, err
 		}
-		return []*plugin.Generated{{
-			Content: updatedContent,
-			Name:    &filePath,
-		}}, nil
+		files = append(files, includeFiles...)
+	}
+	return files, nil
+}
+
+// AddExceptionStatuses adds the HTTP status of the exceptions of the methods
+// documented by an OpenAPI generator.
+func (g *ServerGenerator) AddExceptionStatuses(statuses map[string]int) {
+	for method, status := range statuses {
+		g.ExceptionStatuses = append(g.ExceptionStatuses, &ExceptionStatus{Method: method, Status: status})
 	}
+	sort.Slice(g.ExceptionStatuses, func(i, j int) bool { return g.ExceptionStatuses[i].Method < g.ExceptionStatuses[j].Method })
+}
+
+// Generate returns the settings of the server, only updated if they exist,
+// and its proxy and IDL, which are regenerated on every run.
+func (g *ServerGenerator) Generate() ([]*plugin.Generated, error) {
+	settingsPath := filepath.Join(g.OutputDir, consts.DefaultOutputSwaggerFile)
+	proxyPath := filepath.Join(g.OutputDir, consts.DefaultOutputProxyFile)
+	idlPath := filepath.Join(g.OutputDir, consts.DefaultOutputIdlFile)
 
-	tmpl, err := template.New("server").Delims("{{", "}}").Parse(consts.CodeGenerationCommentThriftRpc + "\n" + tpl.ServerTemplateRpc)
+	settings, err := g.settings(settingsPath)
 	if err != nil {
 		return nil, err
 	}
-
-	var buf bytes.Buffer
-	err = tmpl.Execute(&buf, g)
+	proxy, err := g.execute(consts.CodeGenerationCommentThriftRpc + consts.DoNotEditComment + "\n" + tpl.ServerTemplateRpc)
+	if err != nil {
+		return nil, err
+	}
+	idl, err := g.execute(consts.CodeGenerationCommentThriftRpc + consts.DoNotEditComment + "\n" + tpl.IdlTemplate + tpl.ServicesTemplate + tpl.ExceptionStatusesTemplate)
 	if err != nil {
 		return nil, err
 	}
 
-	return []*plugin.Generated{{
-		Content: buf.String(),
-		Name:    &filePath,
-	}}, nil
+	return []*plugin.Generated{
+		{Content: settings, Name: &settingsPath},
+		{Content: proxy, Name: &proxyPath},
+		{Content: idl, Name: &idlPath},
+	}, nil
+}
+
+// settings returns the settings file of the server: the existing one with its
+// settings updated or, if there is none or it still holds the proxy, a new
+// one.
+func (g *ServerGenerator) settings(filePath string) (string, error) {
+	if utils.FileExists(filePath) {
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return "", err
+		}
+		if !tpl.LegacyServerPattern.Match(content) {
+			return g.updateVariables(string(content)), nil
+		}
+		g.diag.Warnf("%s holds the proxy of an earlier version and is replaced by the settings of the server, the proxy being generated in %s", filePath, consts.DefaultOutputProxyFile)
+	}
+	return g.execute(consts.CodeGenerationCommentThriftRpc + "\n" + tpl.SettingsTemplateRpc)
 }
 
-func updateVariables(filePath, newKitexAddr, newIdlPath string) (string, error) {
-	content, err := ioutil.ReadFile(filePath)
+func (g *ServerGenerator) execute(text string) (string, error) {
+	tmpl, err := template.New("server").Delims("{{", "}}").Parse(text)
 	if err != nil {
 		return "", err
 	}
 
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, g); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// updateVariables updates the settings in the content of the existing
+// settings file, leaving the rest of it as customized by the user.
+func (g *ServerGenerator) updateVariables(content string) string {
+	hertzAddrPattern := regexp.MustCompile(`hertzAddr\s*=\s*"(.*?)"`)
 	kitexAddrPattern := regexp.MustCompile(`kitexAddr\s*=\s*"(.*?)"`)
 	idlPathPattern := regexp.MustCompile(`idlFile\s*=\s*"(.*?)"`)
+	pathStylePattern := regexp.MustCompile(`pathStyle\s*=\s*"(.*?)"`)
+	metainfoStylePattern := regexp.MustCompile(`metainfoStyle\s*=\s*"(.*?)"`)
 
-	updatedContent := kitexAddrPattern.ReplaceAllString(string(content), fmt.Sprintf(`kitexAddr = "%s"`, newKitexAddr))
-	updatedContent = idlPathPattern.ReplaceAllString(updatedContent, fmt.Sprintf(`idlFile = "%s"`, newIdlPath))
-
-	return updatedContent, nil
+	updatedContent := hertzAddrPattern.ReplaceAllString(content, fmt.Sprintf(`hertzAddr = "%s"`, g.HertzAddr))
+	updatedContent = kitexAddrPattern.ReplaceAllString(updatedContent, fmt.Sprintf(`kitexAddr = "%s"`, g.KitexAddr))
+	updatedContent = idlPathPattern.ReplaceAllString(updatedContent, fmt.Sprintf(`idlFile = "%s"`, g.IdlPath))
+	updatedContent = pathStylePattern.ReplaceAllString(updatedContent, fmt.Sprintf(`pathStyle = "%s"`, g.PathStyle))
+	updatedContent = metainfoStylePattern.ReplaceAllString(updatedContent, fmt.Sprintf(`metainfoStyle = "%s"`, g.MetainfoStyle))
+	return updatedContent
 }
 
 func validateAddress(addr string) error {
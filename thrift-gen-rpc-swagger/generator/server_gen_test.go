@@ -0,0 +1,105 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/thriftgo/parser"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
+	"github.com/hertz-contrib/swagger-generate/thrift-gen-rpc-swagger/args"
+)
+
+func TestServerGeneratorGenerate(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  string // Content of swagger.go before generation, if any.
+		want      []string
+		wantNot   []string
+		wantWarns int
+	}{
+		{
+			name: "new settings",
+			want: []string{`hertzAddr = "127.0.0.1:9090"`, `idlFile   = "testdata/exceptions.thrift"`},
+		},
+		{
+			name:     "settings updated",
+			existing: "package swagger\n\nconst (\n\thertzAddr = \"127.0.0.1:1\"\n\tkitexAddr = \"127.0.0.1:2\"\n)\n\nfunc init() { MockMode.Enabled = true }\n",
+			want:     []string{`hertzAddr = "127.0.0.1:9090"`, `kitexAddr = "127.0.0.1:8888"`, "func init() { MockMode.Enabled = true }"},
+		},
+		{
+			name:      "proxy of an earlier version replaced",
+			existing:  "package swagger\n\nconst hertzAddr = \"127.0.0.1:1\"\n\nfunc StartServer() {}\n",
+			want:      []string{`hertzAddr = "127.0.0.1:9090"`, "metainfoStyle"},
+			wantNot:   []string{"func StartServer"},
+			wantWarns: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, err := parser.ParseFile(filepath.Join("testdata", "exceptions.thrift"), nil, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dir := t.TempDir()
+			if tt.existing != "" {
+				if err := os.WriteFile(filepath.Join(dir, "swagger.go"), []byte(tt.existing), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			diag := diagnostics.NewCollector()
+			g, err := NewServerGenerator(ast, &args.Arguments{OutputDir: dir, HertzAddr: "127.0.0.1:9090"}, diag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files, err := g.Generate()
+			if err != nil {
+				t.Fatalf("Generate() error = %s", err)
+			}
+
+			contents := map[string]string{}
+			for _, f := range files {
+				contents[filepath.Base(f.GetName())] = f.Content
+			}
+			for _, name := range []string{"proxy.go", "idl.go"} {
+				if !strings.Contains(contents[name], "DO NOT EDIT.") {
+					t.Errorf("%s is not marked as regenerated", name)
+				}
+			}
+			if !strings.Contains(contents["idl.go"], `{name: "Svc", methods: []string{"Get", "Put"}}`) {
+				t.Errorf("idl.go does not list the services:\n%s", contents["idl.go"])
+			}
+			settings := contents["swagger.go"]
+			for _, s := range tt.want {
+				if !strings.Contains(settings, s) {
+					t.Errorf("swagger.go does not contain %q:\n%s", s, settings)
+				}
+			}
+			for _, s := range tt.wantNot {
+				if strings.Contains(settings, s) {
+					t.Errorf("swagger.go contains %q:\n%s", s, settings)
+				}
+			}
+			if got := diag.Count(diagnostics.SeverityWarning); got != tt.wantWarns {
+				t.Errorf("Generate() warnings = %v", diag.Diagnostics())
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+namespace go a
+struct Req { 1: string name }
+struct Resp { 1: string msg }
+exception NotFound { 1: string reason }
+service Svc {
+    Resp Get(1: Req req) throws (1: NotFound nf) (
+        openapi.operation = '{
+            responses: {response_or_reference: {$replace: [
+                {name: "200", value: {response: {description: "found"}}},
+                {name: "302", value: {response: {description: "moved"}}},
+                {name: "404", value: {response: {description: "not found"}}},
+                {name: "409", value: {response: {description: "conflict"}}}
+            ]}}
+        }'
+    )
+    Resp Redirect(1: Req req) throws (1: NotFound nf) (
+        openapi.operation = '{
+            responses: {response_or_reference: {$replace: [
+                {name: "200", value: {response: {description: "found"}}},
+                {name: "302", value: {response: {description: "moved"}}}
+            ]}}
+        }'
+    )
+    Resp Put(1: Req req)
+}
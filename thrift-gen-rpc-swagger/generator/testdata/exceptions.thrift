@@ -0,0 +1,8 @@
+namespace go a
+struct Req { 1: string name }
+struct Resp { 1: string msg }
+exception NotFound { 1: string reason }
+service Svc {
+    Resp Get(1: Req req) throws (1: NotFound nf)
+    Resp Put(1: Req req)
+}
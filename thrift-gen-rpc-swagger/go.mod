@@ -12,6 +12,7 @@ require (
 	github.com/hertz-contrib/swagger v0.1.0
 	github.com/hertz-contrib/swagger-generate v0.0.0-20240921161005-987932fb30c5
 	github.com/swaggo/files v1.0.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -68,7 +69,9 @@ require (
 	golang.org/x/tools v0.26.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20231106174013-bbf56f31fb17 // indirect
 	google.golang.org/protobuf v1.35.1 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
-replace github.com/apache/thrift v0.21.0 => github.com/apache/thrift v0.13.0
+replace (
+	github.com/apache/thrift v0.21.0 => github.com/apache/thrift v0.13.0
+	github.com/hertz-contrib/swagger-generate => ../
+)
@@ -23,6 +23,7 @@ import (
 
 	"github.com/cloudwego/hertz/cmd/hz/util/logs"
 	"github.com/cloudwego/thriftgo/plugin"
+	"github.com/hertz-contrib/swagger-generate/common/diagnostics"
 	"github.com/hertz-contrib/swagger-generate/thrift-gen-rpc-swagger/args"
 	"github.com/hertz-contrib/swagger-generate/thrift-gen-rpc-swagger/generator"
 )
@@ -61,18 +62,27 @@ func handleRequest(req *plugin.Request) (err error) {
 
 	ast := req.GetAST()
 
-	og := generator.NewOpenAPIGenerator(ast)
-	openapiContent := og.BuildDocument(args)
+	diag := diagnostics.NewCollector()
+	og := generator.NewOpenAPIGenerator(ast, diag)
+	openapiContent, err := og.BuildDocument(args)
+	if err != nil {
+		return err
+	}
 
-	sg, err := generator.NewServerGenerator(ast, args)
+	sg, err := generator.NewServerGenerator(ast, args, diag)
 	if err != nil {
 		return err
 	}
+	sg.AddExceptionStatuses(og.ExceptionStatuses())
 	serverContent, err := sg.Generate()
 	if err != nil {
 		return err
 	}
 
+	if err = diag.Report(os.Stderr, args.Strict); err != nil {
+		return err
+	}
+
 	res := &plugin.Response{
 		Contents: append(openapiContent, serverContent...),
 	}
@@ -22,9 +22,15 @@ import (
 
 	"github.com/cloudwego/thriftgo/extension/thrift_option"
 	"github.com/cloudwego/thriftgo/thrift_reflection"
+	"github.com/hertz-contrib/swagger-generate/common/consts"
+	"github.com/hertz-contrib/swagger-generate/common/thriftgen"
+	common "github.com/hertz-contrib/swagger-generate/common/utils"
 )
 
 func ParseStructOption(descriptor *thrift_reflection.StructDescriptor, optionName string, obj interface{}) error {
+	if err := checkOption(descriptor.Annotations, optionName, obj); err != nil {
+		return err
+	}
 	opt, err := thrift_option.ParseStructOption(descriptor, optionName)
 	if errors.Is(err, thrift_option.ErrKeyNotMatch) ||
 		errors.Is(err, thrift_option.ErrNotIncluded) ||
@@ -47,6 +53,9 @@ func ParseStructOption(descriptor *thrift_reflection.StructDescriptor, optionNam
 }
 
 func ParseServiceOption(descriptor *thrift_reflection.ServiceDescriptor, optionName string, obj interface{}) error {
+	if err := checkOption(descriptor.Annotations, optionName, obj); err != nil {
+		return err
+	}
 	opt, err := thrift_option.ParseServiceOption(descriptor, optionName)
 	if errors.Is(err, thrift_option.ErrKeyNotMatch) ||
 		errors.Is(err, thrift_option.ErrNotIncluded) ||
@@ -69,6 +78,9 @@ func ParseServiceOption(descriptor *thrift_reflection.ServiceDescriptor, optionN
 }
 
 func ParseMethodOption(descriptor *thrift_reflection.MethodDescriptor, optionName string, obj interface{}) error {
+	if err := checkOption(descriptor.Annotations, optionName, obj); err != nil {
+		return err
+	}
 	opt, err := thrift_option.ParseMethodOption(descriptor, optionName)
 	if errors.Is(err, thrift_option.ErrKeyNotMatch) ||
 		errors.Is(err, thrift_option.ErrNotIncluded) ||
@@ -91,6 +103,9 @@ func ParseMethodOption(descriptor *thrift_reflection.MethodDescriptor, optionNam
 }
 
 func ParseFieldOption(descriptor *thrift_reflection.FieldDescriptor, optionName string, obj interface{}) error {
+	if err := checkOption(descriptor.Annotations, optionName, obj); err != nil {
+		return err
+	}
 	opt, err := thrift_option.ParseFieldOption(descriptor, optionName)
 	if errors.Is(err, thrift_option.ErrKeyNotMatch) ||
 		errors.Is(err, thrift_option.ErrNotIncluded) ||
@@ -111,3 +126,73 @@ func ParseFieldOption(descriptor *thrift_reflection.FieldDescriptor, optionName
 	}
 	return err
 }
+
+// MergeStructOption deep merges the optionName annotation of descriptor into
+// dst, see common.MergeOptionValue.
+func MergeStructOption(descriptor *thrift_reflection.StructDescriptor, optionName string, dst interface{}) error {
+	return mergeOption(descriptor.Annotations, optionName, dst)
+}
+
+func MergeServiceOption(descriptor *thrift_reflection.ServiceDescriptor, optionName string, dst interface{}) error {
+	return mergeOption(descriptor.Annotations, optionName, dst)
+}
+
+func MergeMethodOption(descriptor *thrift_reflection.MethodDescriptor, optionName string, dst interface{}) error {
+	return mergeOption(descriptor.Annotations, optionName, dst)
+}
+
+func MergeFieldOption(descriptor *thrift_reflection.FieldDescriptor, optionName string, dst interface{}) error {
+	return mergeOption(descriptor.Annotations, optionName, dst)
+}
+
+// mergeOption checks the raw payload of optionName and deep merges it into
+// dst. Unlike the Parse*Option helpers it works on the payload itself rather
+// than on a decoded struct, so it knows which keys are set and can override
+// generated values with zero values or null.
+func mergeOption(annotations map[string][]string, optionName string, dst interface{}) error {
+	values := annotations[optionName]
+	if len(values) == 0 {
+		return nil
+	}
+	value, err := thriftgen.ParseOptionValue(values[0])
+	if err != nil {
+		return err
+	}
+	value = expandPropertyShorthand(optionName, value)
+	if err = common.CheckOptionValue(value, dst); err != nil {
+		return err
+	}
+	return common.MergeOptionValue(dst, value)
+}
+
+// checkOption strictly validates the raw payload of optionName against the
+// openapi model obj points to. thrift_option only knows the openapi.thrift
+// included by the IDL and its errors carry no hint, so this reports unknown
+// keys with suggestions, type mismatches and invalid enum values up front.
+func checkOption(annotations map[string][]string, optionName string, obj interface{}) error {
+	values := annotations[optionName]
+	if len(values) == 0 {
+		return nil
+	}
+	value, err := thriftgen.ParseOptionValue(values[0])
+	if err != nil {
+		return err
+	}
+	value = expandPropertyShorthand(optionName, value)
+	return common.CheckOptionValue(value, obj)
+}
+
+// expandPropertyShorthand turns a field behavior shorthand of
+// `openapi.property` into the property it stands for.
+func expandPropertyShorthand(optionName string, value interface{}) interface{} {
+	if optionName != consts.OpenapiProperty {
+		return value
+	}
+	switch value {
+	case thriftgen.FieldBehaviorInputOnly:
+		return map[string]interface{}{"write_only": "true"}
+	case thriftgen.FieldBehaviorOutputOnly:
+		return map[string]interface{}{"read_only": "true"}
+	}
+	return value
+}